@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/admin"
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/email"
+	"github.com/muskan953/college-Hop/internal/events"
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/middleware"
+	"github.com/muskan953/college-Hop/internal/moderation"
+	"github.com/muskan953/college-Hop/internal/profile"
+	"github.com/muskan953/college-Hop/internal/server"
+	"github.com/muskan953/college-Hop/pkg/db"
+	"github.com/muskan953/college-Hop/pkg/migrations"
+	"github.com/muskan953/college-Hop/pkg/notify"
+	"github.com/muskan953/college-Hop/pkg/storage"
+)
+
+func main() {
+	database, err := db.Connect()
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+
+	defer database.Close()
+	log.Println("Database connection established")
+	if err := migrations.Run(database); err != nil {
+		log.Fatalf("migration failed: %v", err)
+	}
+
+	log.Println("database migrations applied")
+
+	// Initialize file storage
+	uploadDir := os.Getenv("UPLOAD_DIR")
+	if uploadDir == "" {
+		uploadDir = "./uploads"
+	}
+	baseURL := os.Getenv("UPLOAD_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/uploads"
+	}
+
+	store, err := storage.NewLocalStorage(uploadDir, baseURL)
+	if err != nil {
+		log.Fatalf("failed to initialize storage: %v", err)
+	}
+
+	authRepo := auth.NewRepository(database)
+	profileRepo := profile.NewRepository(database)
+	adminRepo := admin.NewRepository(database)
+	eventsRepo := events.NewRepository(database)
+	groupsRepo := groups.NewRepository(database)
+	messagesRepo := messages.NewRepository(database)
+
+	// Initialize Email service
+	var emailService email.Service
+	if apiKey := os.Getenv("RESEND_API_KEY"); apiKey != "" {
+		fromAddr := os.Getenv("RESEND_FROM")
+		if fromAddr == "" {
+			fromAddr = "onboarding@resend.dev"
+		}
+		emailService = email.NewResendService(apiKey, fromAddr)
+	} else {
+		emailService = email.NewMockService()
+	}
+
+	// Initialize FCM for push notifications
+	notifier := notify.New()
+
+	// Background workers stop when the server shuts down
+	bgCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	// Content moderation: flagged content lands in the admin reports queue
+	moderator := moderation.New()
+	moderator.SetFlagger(moderation.NewFlagger(database))
+	if rulesFile := os.Getenv("MODERATION_RULES_FILE"); rulesFile != "" {
+		if err := moderator.LoadFile(rulesFile); err != nil {
+			log.Fatalf("failed to load moderation rules: %v", err)
+		}
+		log.Printf("loaded %d moderation rules from %s", moderator.RuleCount(), rulesFile)
+		go moderator.Watch(bgCtx, 30*time.Second)
+	}
+
+	// Start WebSocket hub for real-time messaging
+	hub := messages.NewHub(messagesRepo, notifier, moderator)
+	go hub.Run()
+
+	// Send scheduled messages as they come due
+	go messages.NewScheduler(messagesRepo, hub).Run(bgCtx)
+	// Delete disappearing messages once they expire
+	go messages.NewSweeper(messagesRepo, hub).Run(bgCtx)
+	// Pass unclaimed waitlist spots to the next user in line
+	go groups.NewWaitlistSweeper(groupsRepo, hub).Run(bgCtx)
+	// Complete and archive groups after their event ends
+	go groups.NewArchiver(groupsRepo).Run(bgCtx)
+	// Expire join requests nobody acted on
+	go groups.NewJoinRequestExpirer(groupsRepo, hub).Run(bgCtx)
+
+	// Group matching: MATCH_WEIGHTS overrides factor weights, e.g. "interests=0.5,fill=0"
+	weights := groups.DefaultWeights
+	if spec := os.Getenv("MATCH_WEIGHTS"); spec != "" {
+		var err error
+		if weights, err = groups.ParseWeights(spec); err != nil {
+			log.Fatalf("invalid MATCH_WEIGHTS: %v", err)
+		}
+	}
+	scorer := groups.NewWeightedScorer(weights)
+
+	mux := server.NewRouter(authRepo, emailService, profileRepo, adminRepo, eventsRepo, groupsRepo, messagesRepo, hub, store, uploadDir, database, moderator, scorer)
+
+	// Wrap with rate limiter: 20 requests/sec, burst of 40
+	limiter := middleware.NewRateLimiter(20, 40)
+	handler := limiter.Limit(mux)
+	handler = middleware.Cors(handler)
+
+	srv := &http.Server{
+		Addr:         ":8080",
+		Handler:      handler,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	// Start server in a goroutine
+	go func() {
+		log.Println("Server running on :8080")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	stopWorkers()
+
+	// Give outstanding requests 5 seconds to complete
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+
+	log.Println("Server exited gracefully")
+}
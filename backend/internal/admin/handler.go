@@ -5,14 +5,19 @@ import (
 	"net/http"
 	"os"
 	"strings"
+
+	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/moderation"
 )
 
 type Handler struct {
-	repo Repository
+	repo      Repository
+	hub       *messages.Hub
+	moderator *moderation.Moderator
 }
 
-func NewHandler(repo Repository) *Handler {
-	return &Handler{repo: repo}
+func NewHandler(repo Repository, hub *messages.Hub, moderator *moderation.Moderator) *Handler {
+	return &Handler{repo: repo, hub: hub, moderator: moderator}
 }
 
 // AdminAuth is a simple middleware that checks for the X-Admin-Secret header.
@@ -104,7 +109,27 @@ func (h *Handler) BlockUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "user not found", http.StatusNotFound)
 		return
 	}
+	if h.hub != nil {
+		h.hub.Disconnect(userID)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "user blocked", "user_id": userID})
 }
+
+// ReloadModerationRules re-reads the moderation rules file without a restart.
+func (h *Handler) ReloadModerationRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	m := h.moderator
+	if err := m.Reload(); err != nil {
+		http.Error(w, "failed to reload rules: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"rules": m.RuleCount()})
+}
@@ -0,0 +1,262 @@
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muskan953/college-Hop/internal/auth"
+)
+
+// Report target types.
+const (
+	TargetMessage = "message"
+	TargetUser    = "user"
+	TargetGroup   = "group"
+	TargetEvent   = "event"
+)
+
+// Report statuses.
+const (
+	ReportOpen      = "open"
+	ReportResolved  = "resolved"
+	ReportDismissed = "dismissed"
+)
+
+// Moderation actions an admin can take when closing a report.
+const (
+	ActionDismiss       = "dismiss"
+	ActionWarn          = "warn"
+	ActionDeleteContent = "delete_content"
+	ActionSuspend       = "suspend"
+)
+
+// ReportCategories are the reasons a user can pick when reporting.
+var ReportCategories = map[string]bool{
+	"harassment":    true,
+	"spam":          true,
+	"hate_speech":   true,
+	"inappropriate": true,
+	"impersonation": true,
+	"safety":        true,
+	"other":         true,
+}
+
+// MaxReportDetails caps the free-text part of a report.
+const MaxReportDetails = 1000
+
+// MaxSuspendDays caps a temporary suspension; longer ones should be a block.
+const MaxSuspendDays = 365
+
+var (
+	ErrAlreadyReported = errors.New("target already reported")
+	ErrSelfReport      = errors.New("cannot report yourself")
+	ErrReportClosed    = errors.New("report already closed")
+	ErrNoReportedUser  = errors.New("reported user no longer exists")
+)
+
+// Report is a user's complaint about a message, profile, group or event.
+type Report struct {
+	ID                string     `json:"id"`
+	ReporterID        *string    `json:"reporter_id"` // nil for automated reports
+	Source            string     `json:"source"`      // "user" or "automated"
+	TargetType        string     `json:"target_type"`
+	TargetID          string     `json:"target_id"`
+	ReportedUserID    *string    `json:"reported_user_id"`
+	ReportedUserEmail *string    `json:"reported_user_email,omitempty"`
+	ThreadID          *string    `json:"thread_id,omitempty"` // message reports only
+	Category          string     `json:"category"`
+	Details           *string    `json:"details"`
+	ContentSnapshot   *string    `json:"content_snapshot"`
+	Status            string     `json:"status"`
+	Resolution        *string    `json:"resolution"`
+	ResolutionNote    *string    `json:"resolution_note"`
+	ResolvedAt        *time.Time `json:"resolved_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// CreateReportRequest is the payload for POST /reports.
+type CreateReportRequest struct {
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Category   string `json:"category"`
+	Details    string `json:"details"`
+}
+
+// ResolveReportRequest is the payload for POST /admin/reports/{id}/resolve.
+type ResolveReportRequest struct {
+	Action      string `json:"action"`
+	SuspendDays int    `json:"suspend_days"` // only with action=suspend; 0 blocks the account
+	Note        string `json:"note"`
+}
+
+// POST /reports — Report a message, user, group or event to the admins.
+func (h *Handler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	switch req.TargetType {
+	case TargetMessage, TargetUser, TargetGroup, TargetEvent:
+	default:
+		http.Error(w, "target_type must be one of message, user, group, event", http.StatusBadRequest)
+		return
+	}
+	if _, err := uuid.Parse(req.TargetID); err != nil {
+		http.Error(w, "invalid target_id", http.StatusBadRequest)
+		return
+	}
+	if !ReportCategories[req.Category] {
+		http.Error(w, "invalid category", http.StatusBadRequest)
+		return
+	}
+	req.Details = strings.TrimSpace(req.Details)
+	if len(req.Details) > MaxReportDetails {
+		http.Error(w, "details too long (max 1000 chars)", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.repo.CreateReport(r.Context(), user.ID, req)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			http.Error(w, "target not found", http.StatusNotFound)
+		case errors.Is(err, ErrSelfReport):
+			http.Error(w, "you cannot report yourself", http.StatusBadRequest)
+		case errors.Is(err, ErrAlreadyReported):
+			http.Error(w, "you have already reported this", http.StatusConflict)
+		default:
+			http.Error(w, "failed to create report", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"id": report.ID, "status": report.Status})
+}
+
+// GET /admin/reports?status=open — The moderation queue. Open reports come oldest first.
+func (h *Handler) ListReports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = ReportOpen
+	}
+	switch status {
+	case ReportOpen, ReportResolved, ReportDismissed:
+	default:
+		http.Error(w, "status must be one of open, resolved, dismissed", http.StatusBadRequest)
+		return
+	}
+
+	reports, err := h.repo.ListReports(r.Context(), status)
+	if err != nil {
+		http.Error(w, "failed to list reports", http.StatusInternalServerError)
+		return
+	}
+
+	if reports == nil {
+		reports = []Report{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// POST /admin/reports/{id}/resolve — Close a report by dismissing it, warning the
+// reported user, deleting the content or suspending the account.
+func (h *Handler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// /admin/reports/{id}/resolve
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 {
+		http.Error(w, "missing report ID", http.StatusBadRequest)
+		return
+	}
+	reportID := parts[2]
+
+	var req ResolveReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	switch req.Action {
+	case ActionDismiss, ActionWarn, ActionDeleteContent, ActionSuspend:
+	default:
+		http.Error(w, "action must be one of dismiss, warn, delete_content, suspend", http.StatusBadRequest)
+		return
+	}
+	if req.SuspendDays != 0 && req.Action != ActionSuspend {
+		http.Error(w, "suspend_days requires action=suspend", http.StatusBadRequest)
+		return
+	}
+	if req.SuspendDays < 0 || req.SuspendDays > MaxSuspendDays {
+		http.Error(w, "suspend_days must be between 0 and 365", http.StatusBadRequest)
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+
+	report, err := h.repo.ResolveReport(r.Context(), reportID, req)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			http.Error(w, "report not found", http.StatusNotFound)
+		case errors.Is(err, ErrReportClosed):
+			http.Error(w, "report already closed", http.StatusConflict)
+		case errors.Is(err, ErrNoReportedUser):
+			http.Error(w, "reported user no longer exists", http.StatusBadRequest)
+		default:
+			http.Error(w, "failed to resolve report", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Let connected clients and the reported user know what happened
+	if h.hub != nil {
+		switch req.Action {
+		case ActionDeleteContent:
+			if report.TargetType == TargetMessage && report.ThreadID != nil {
+				h.hub.BroadcastMessageDeleted(r.Context(), *report.ThreadID, report.TargetID)
+			}
+		case ActionWarn:
+			body := req.Note
+			if body == "" {
+				body = "Your content was reported and found to break the community guidelines."
+			}
+			h.hub.SendNotification(r.Context(), *report.ReportedUserID, "Warning from moderators", body,
+				map[string]string{"type": "moderation_warning", "report_id": report.ID})
+		case ActionSuspend:
+			h.hub.Disconnect(*report.ReportedUserID)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
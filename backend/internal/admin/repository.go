@@ -3,6 +3,7 @@ package admin
 import (
 	"context"
 	"database/sql"
+	"fmt"
 )
 
 // UserRow represents a user with their profile data for admin review.
@@ -18,6 +19,15 @@ type UserRow struct {
 type Repository interface {
 	ListUsersByStatus(ctx context.Context, status string) ([]UserRow, error)
 	UpdateUserStatus(ctx context.Context, userID string, status string) error
+
+	// Reports
+	// CreateReport files a report after checking the target exists and is visible
+	// to the reporter. Returns sql.ErrNoRows if it is not.
+	CreateReport(ctx context.Context, reporterID string, req CreateReportRequest) (Report, error)
+	ListReports(ctx context.Context, status string) ([]Report, error)
+	// ResolveReport applies the moderation action and closes every open report
+	// on the same target. Returns the report as closed.
+	ResolveReport(ctx context.Context, reportID string, req ResolveReportRequest) (Report, error)
 }
 
 type PostgresRepository struct {
@@ -68,3 +78,213 @@ func (r *PostgresRepository) UpdateUserStatus(ctx context.Context, userID string
 	}
 	return nil
 }
+
+func (r *PostgresRepository) CreateReport(ctx context.Context, reporterID string, req CreateReportRequest) (Report, error) {
+	rep := Report{
+		ReporterID: &reporterID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Category:   req.Category,
+	}
+	if req.Details != "" {
+		rep.Details = &req.Details
+	}
+
+	// Find who owns the target and snapshot its text
+	var row *sql.Row
+	switch req.TargetType {
+	case TargetMessage:
+		// Only messages in the reporter's own threads can be reported
+		row = r.db.QueryRowContext(ctx, `
+			SELECT m.sender_id::text, m.thread_id::text, m.content
+			FROM messages m
+			JOIN thread_participants tp ON tp.thread_id = m.thread_id AND tp.user_id = $2
+			WHERE m.id = $1
+		`, req.TargetID, reporterID)
+	case TargetUser:
+		row = r.db.QueryRowContext(ctx, `
+			SELECT u.id::text, NULL, p.bio
+			FROM users u
+			LEFT JOIN profiles p ON p.user_id = u.id
+			WHERE u.id = $1
+		`, req.TargetID)
+	case TargetGroup:
+		row = r.db.QueryRowContext(ctx, `
+			SELECT created_by::text, NULL, name || COALESCE(E'\n' || description, '')
+			FROM travel_groups WHERE id = $1
+		`, req.TargetID)
+	case TargetEvent:
+		row = r.db.QueryRowContext(ctx, `
+			SELECT submitted_by::text, NULL, name
+			FROM events WHERE id = $1
+		`, req.TargetID)
+	default:
+		return Report{}, fmt.Errorf("unknown report target type %q", req.TargetType)
+	}
+	if err := row.Scan(&rep.ReportedUserID, &rep.ThreadID, &rep.ContentSnapshot); err != nil {
+		return Report{}, err
+	}
+	if rep.ReportedUserID != nil && *rep.ReportedUserID == reporterID {
+		return Report{}, ErrSelfReport
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO reports (reporter_id, target_type, target_id, reported_user_id, thread_id, category, details, content_snapshot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (reporter_id, target_type, target_id) WHERE status = 'open' DO NOTHING
+		RETURNING id, source, status, created_at
+	`, reporterID, rep.TargetType, rep.TargetID, rep.ReportedUserID, rep.ThreadID, rep.Category, rep.Details, rep.ContentSnapshot,
+	).Scan(&rep.ID, &rep.Source, &rep.Status, &rep.CreatedAt)
+	if err == sql.ErrNoRows {
+		return Report{}, ErrAlreadyReported
+	}
+	return rep, err
+}
+
+const reportColumns = `
+	rp.id, rp.reporter_id::text, rp.source, rp.target_type, rp.target_id::text, rp.reported_user_id::text, ru.email,
+	rp.thread_id::text, rp.category, rp.details, rp.content_snapshot, rp.status,
+	rp.resolution, rp.resolution_note, rp.resolved_at, rp.created_at`
+
+func scanReport(row interface{ Scan(...any) error }) (Report, error) {
+	var rep Report
+	err := row.Scan(&rep.ID, &rep.ReporterID, &rep.Source, &rep.TargetType, &rep.TargetID, &rep.ReportedUserID, &rep.ReportedUserEmail,
+		&rep.ThreadID, &rep.Category, &rep.Details, &rep.ContentSnapshot, &rep.Status,
+		&rep.Resolution, &rep.ResolutionNote, &rep.ResolvedAt, &rep.CreatedAt)
+	return rep, err
+}
+
+func (r *PostgresRepository) ListReports(ctx context.Context, status string) ([]Report, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reportColumns+`
+		FROM reports rp
+		LEFT JOIN users ru ON ru.id = rp.reported_user_id
+		WHERE rp.status = $1
+		ORDER BY
+			CASE WHEN rp.status = 'open' THEN rp.created_at END ASC,
+			rp.resolved_at DESC
+	`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []Report
+	for rows.Next() {
+		rep, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, rep)
+	}
+	return reports, rows.Err()
+}
+
+func (r *PostgresRepository) ResolveReport(ctx context.Context, reportID string, req ResolveReportRequest) (Report, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Report{}, err
+	}
+	defer tx.Rollback()
+
+	rep, err := scanReport(tx.QueryRowContext(ctx, `
+		SELECT `+reportColumns+`
+		FROM reports rp
+		LEFT JOIN users ru ON ru.id = rp.reported_user_id
+		WHERE rp.id = $1
+		FOR UPDATE OF rp
+	`, reportID))
+	if err != nil {
+		return Report{}, err
+	}
+	if rep.Status != ReportOpen {
+		return Report{}, ErrReportClosed
+	}
+
+	if err := applyModerationAction(ctx, tx, rep, req); err != nil {
+		return Report{}, err
+	}
+
+	status, resolution := ReportResolved, &req.Action
+	if req.Action == ActionDismiss {
+		status, resolution = ReportDismissed, nil
+	}
+	var note *string
+	if req.Note != "" {
+		note = &req.Note
+	}
+
+	// Dismissals only close this report; actions settle every open report on the target
+	_, err = tx.ExecContext(ctx, `
+		UPDATE reports
+		SET status = $3, resolution = $4, resolution_note = $5, resolved_at = NOW()
+		WHERE status = 'open' AND (id = $1 OR ($2 AND target_type = $6 AND target_id = $7))
+	`, rep.ID, req.Action != ActionDismiss, status, resolution, note, rep.TargetType, rep.TargetID)
+	if err != nil {
+		return Report{}, err
+	}
+
+	rep, err = scanReport(tx.QueryRowContext(ctx, `
+		SELECT `+reportColumns+`
+		FROM reports rp
+		LEFT JOIN users ru ON ru.id = rp.reported_user_id
+		WHERE rp.id = $1
+	`, reportID))
+	if err != nil {
+		return Report{}, err
+	}
+	return rep, tx.Commit()
+}
+
+// applyModerationAction carries out the side effect of resolving a report.
+func applyModerationAction(ctx context.Context, tx *sql.Tx, rep Report, req ResolveReportRequest) error {
+	switch req.Action {
+	case ActionDismiss:
+		return nil
+
+	case ActionWarn:
+		// The warning itself is the recorded resolution plus a notification
+		if rep.ReportedUserID == nil {
+			return ErrNoReportedUser
+		}
+		return nil
+
+	case ActionDeleteContent:
+		var err error
+		switch rep.TargetType {
+		case TargetMessage:
+			_, err = tx.ExecContext(ctx, `DELETE FROM messages WHERE id = $1`, rep.TargetID)
+		case TargetUser:
+			_, err = tx.ExecContext(ctx, `UPDATE profiles SET bio = NULL, profile_photo_url = NULL WHERE user_id = $1`, rep.TargetID)
+		case TargetGroup:
+			// The group chat goes with the group; its messages and participants cascade
+			_, err = tx.ExecContext(ctx, `DELETE FROM message_threads WHERE group_id = $1`, rep.TargetID)
+			if err == nil {
+				_, err = tx.ExecContext(ctx, `DELETE FROM travel_groups WHERE id = $1`, rep.TargetID)
+			}
+		case TargetEvent:
+			_, err = tx.ExecContext(ctx, `UPDATE events SET status = 'rejected' WHERE id = $1`, rep.TargetID)
+		}
+		return err
+
+	case ActionSuspend:
+		if rep.ReportedUserID == nil {
+			return ErrNoReportedUser
+		}
+		var err error
+		if req.SuspendDays > 0 {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE users SET suspended_until = NOW() + make_interval(days => $2) WHERE id = $1
+			`, *rep.ReportedUserID, req.SuspendDays)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE users SET status = 'blocked' WHERE id = $1`, *rep.ReportedUserID)
+		}
+		if err != nil {
+			return err
+		}
+		// Force a fresh login once the suspension ends
+		_, err = tx.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, *rep.ReportedUserID)
+		return err
+	}
+	return fmt.Errorf("unknown moderation action %q", req.Action)
+}
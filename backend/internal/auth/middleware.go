@@ -25,7 +25,7 @@ func AuthMiddleware(next http.Handler) http.Handler {
 }
 
 // NewAuthMiddleware returns an auth middleware that also checks whether a user
-// has been blocked, rejecting them with 403 Forbidden before hitting any handler.
+// has been blocked or suspended, rejecting them with 403 Forbidden before hitting any handler.
 func NewAuthMiddleware(repo Repository) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -34,15 +34,7 @@ func NewAuthMiddleware(repo Repository) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Look up live user status — blocks suspended accounts even if their
-			// JWT has not expired yet.
-			status, err := repo.GetUserStatus(r.Context(), claims.UserID)
-			if err != nil {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
-				return
-			}
-			if status == "blocked" {
-				http.Error(w, "your account has been blocked", http.StatusForbidden)
+			if !CheckUserStatus(w, r, repo, claims.UserID) {
 				return
 			}
 
@@ -77,3 +69,23 @@ func bearerClaims(w http.ResponseWriter, r *http.Request) (*Claims, bool) {
 	}
 	return claims, true
 }
+
+// CheckUserStatus looks up the user's live status — blocking suspended accounts
+// even if their JWT has not expired yet. It writes the error response and
+// returns false if the user may not continue.
+func CheckUserStatus(w http.ResponseWriter, r *http.Request, repo Repository, userID string) bool {
+	status, err := repo.GetUserStatus(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	if status == "blocked" {
+		http.Error(w, "your account has been blocked", http.StatusForbidden)
+		return false
+	}
+	if status == "suspended" {
+		http.Error(w, "your account is temporarily suspended", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -22,7 +22,8 @@ type Repository interface {
 	SaveRefreshToken(ctx context.Context, userID string, tokenHash string, expiresAt time.Time) error
 	GetRefreshToken(ctx context.Context, tokenHash string) (string, time.Time, error)
 	DeleteRefreshToken(ctx context.Context, tokenHash string) error
-	// GetUserStatus returns the current status of a user ("pending", "verified", "blocked").
+	// GetUserStatus returns the current status of a user ("pending", "verified", "blocked"),
+	// or "suspended" while a temporary suspension is in effect.
 	GetUserStatus(ctx context.Context, userID string) (string, error)
 }
 
@@ -219,7 +220,8 @@ func (r *PostgresRepository) UserExists(ctx context.Context, email string) (bool
 func (r *PostgresRepository) GetUserStatus(ctx context.Context, userID string) (string, error) {
 	var status string
 	err := r.db.QueryRowContext(ctx,
-		`SELECT status FROM users WHERE id = $1`,
+		`SELECT CASE WHEN status <> 'blocked' AND suspended_until > NOW() THEN 'suspended' ELSE status END
+		 FROM users WHERE id = $1`,
 		userID,
 	).Scan(&status)
 	if err != nil {
@@ -0,0 +1,78 @@
+package groups
+
+import (
+	"context"
+	"log"
+
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// publish pushes a live event to every member of the group and to any extra
+// users, such as a member who has just left. Like postSystemMessage it runs
+// after the change is committed, so failures are only logged.
+func (h *Handler) publish(ctx context.Context, groupID, eventType string, payload interface{}, extra ...string) {
+	h.publishTo(ctx, groupID, eventType, payload, func(string) bool { return true }, extra)
+}
+
+// publishToManagers pushes a live event to the group's owner and admins and to
+// any extra users.
+func (h *Handler) publishToManagers(ctx context.Context, groupID, eventType string, payload interface{}, extra ...string) {
+	h.publishTo(ctx, groupID, eventType, payload, CanManage, extra)
+}
+
+func (h *Handler) publishTo(ctx context.Context, groupID, eventType string, payload interface{}, include func(role string) bool, extra []string) {
+	if h.hub == nil {
+		return
+	}
+	members, err := h.repo.GetGroupMembers(ctx, groupID)
+	if err != nil {
+		log.Printf("[Groups] Failed to load members of group %s for %s event: %v", groupID, eventType, err)
+	}
+	userIDs := extra
+	for _, m := range members {
+		if include(m.Role) {
+			userIDs = append(userIDs, m.UserID)
+		}
+	}
+	h.hub.SendToUsers(userIDs, messages.WSOutgoing{Type: eventType, Payload: payload})
+}
+
+// groupUpdated tells members that something about the group changed.
+func (h *Handler) groupUpdated(ctx context.Context, groupID, actorID, change string) {
+	h.publish(ctx, groupID, messages.EventGroupUpdated, messages.WSGroupUpdated{
+		GroupID: groupID,
+		Change:  change,
+		ActorID: actorID,
+	})
+}
+
+// memberJoined tells members, including the new one, that userID joined.
+func (h *Handler) memberJoined(ctx context.Context, groupID, userID, via, approvedBy string) {
+	h.publish(ctx, groupID, messages.EventMemberJoined, messages.WSMemberJoined{
+		GroupID:    groupID,
+		UserID:     userID,
+		Via:        via,
+		ApprovedBy: approvedBy,
+	})
+}
+
+// memberLeft tells the remaining members and the user themselves that userID
+// is no longer in the group.
+func (h *Handler) memberLeft(ctx context.Context, groupID, userID, removedBy string) {
+	h.publish(ctx, groupID, messages.EventMemberLeft, messages.WSMemberLeft{
+		GroupID:   groupID,
+		UserID:    userID,
+		RemovedBy: removedBy,
+	}, userID)
+}
+
+// joinRequestResolved tells the requester and the group's managers what
+// became of a join request.
+func (h *Handler) joinRequestResolved(ctx context.Context, groupID, userID, status, resolvedBy string) {
+	h.publishToManagers(ctx, groupID, messages.EventJoinRequestResolved, messages.WSJoinRequestResolved{
+		GroupID:    groupID,
+		UserID:     userID,
+		Status:     status,
+		ResolvedBy: resolvedBy,
+	}, userID)
+}
@@ -0,0 +1,381 @@
+package groups
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// How an expense is divided between its participants.
+const (
+	SplitEqual      = "equal"
+	SplitExact      = "exact"
+	SplitPercentage = "percentage"
+)
+
+// MaxExpenseAmount caps a single expense or settlement, in minor units, so that
+// percentage splits cannot overflow.
+const MaxExpenseAmount int64 = 1_000_000_000_000
+
+// ExpenseShare is what one participant owes towards an expense.
+type ExpenseShare struct {
+	UserID  string   `json:"user_id"`
+	Amount  int64    `json:"amount"`            // minor units
+	Percent *float64 `json:"percent,omitempty"` // percentage splits only
+}
+
+// Expense is a cost paid by one member and split between participants.
+// Amounts are integer minor units of Currency (e.g. paise for INR).
+type Expense struct {
+	ID          string         `json:"id"`
+	GroupID     string         `json:"group_id"`
+	Description string         `json:"description"`
+	Amount      int64          `json:"amount"`
+	Currency    string         `json:"currency"`
+	PaidBy      string         `json:"paid_by"`
+	SplitType   string         `json:"split_type"`
+	Shares      []ExpenseShare `json:"shares"`
+	CreatedBy   string         `json:"created_by,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// CreateExpenseRequest is the payload for POST /groups/{id}/expenses.
+// PaidBy defaults to the caller. For equal splits Splits only needs user ids
+// and defaults to every current member.
+type CreateExpenseRequest struct {
+	Description string         `json:"description"`
+	Amount      int64          `json:"amount"`
+	Currency    string         `json:"currency"`
+	PaidBy      string         `json:"paid_by"`
+	SplitType   string         `json:"split_type"`
+	Splits      []ExpenseShare `json:"splits"`
+}
+
+// Settlement is a payment from one member to another that has been marked as paid.
+type Settlement struct {
+	ID        string    `json:"id"`
+	GroupID   string    `json:"group_id"`
+	FromUser  string    `json:"from_user"`
+	ToUser    string    `json:"to_user"`
+	Amount    int64     `json:"amount"`
+	Currency  string    `json:"currency"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+// Balance is a member's position in one currency. Net is positive when the
+// member is owed money and negative when they owe it.
+type Balance struct {
+	UserID   string `json:"user_id"`
+	Currency string `json:"currency"`
+	Paid     int64  `json:"paid"`  // total of expenses they paid for
+	Share    int64  `json:"share"` // total of their shares of expenses
+	Net      int64  `json:"net"`   // paid - share, adjusted for settlements
+}
+
+// Transfer is one payment of a settle-up plan.
+type Transfer struct {
+	FromUser string `json:"from_user"`
+	ToUser   string `json:"to_user"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// ExpenseSummary is returned by GET /groups/{id}/expenses/balances
+type ExpenseSummary struct {
+	Balances []Balance  `json:"balances"`
+	SettleUp []Transfer `json:"settle_up"`
+}
+
+// normalizeCurrency upper-cases an ISO 4217 code and checks its shape.
+func normalizeCurrency(c string) (string, error) {
+	c = strings.ToUpper(strings.TrimSpace(c))
+	if len(c) != 3 {
+		return "", errors.New("currency must be a 3-letter ISO 4217 code")
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return "", errors.New("currency must be a 3-letter ISO 4217 code")
+		}
+	}
+	return c, nil
+}
+
+func validAmount(a int64) error {
+	if a <= 0 || a > MaxExpenseAmount {
+		return errors.New("amount must be a positive number of minor units")
+	}
+	return nil
+}
+
+// Validate trims and checks the request and fills in defaults: the caller as
+// payer and, for equal splits without participants, every member.
+func (req *CreateExpenseRequest) Validate(callerID string, members []string) error {
+	req.Description = strings.TrimSpace(req.Description)
+	req.PaidBy = strings.TrimSpace(req.PaidBy)
+	req.SplitType = strings.TrimSpace(req.SplitType)
+	if req.PaidBy == "" {
+		req.PaidBy = callerID
+	}
+	if req.SplitType == "" {
+		req.SplitType = SplitEqual
+	}
+
+	if req.Description == "" {
+		return errors.New("description is required")
+	}
+	if len(req.Description) > 100 {
+		return errors.New("description too long (max 100 chars)")
+	}
+	if err := validAmount(req.Amount); err != nil {
+		return err
+	}
+	currency, err := normalizeCurrency(req.Currency)
+	if err != nil {
+		return err
+	}
+	req.Currency = currency
+
+	isMember := make(map[string]bool, len(members))
+	for _, m := range members {
+		isMember[m] = true
+	}
+	if !isMember[req.PaidBy] {
+		return errors.New("paid_by must be a group member")
+	}
+	if req.SplitType == SplitEqual && len(req.Splits) == 0 {
+		for _, m := range members {
+			req.Splits = append(req.Splits, ExpenseShare{UserID: m})
+		}
+	}
+	if len(req.Splits) == 0 {
+		return errors.New("splits are required")
+	}
+	seen := make(map[string]bool, len(req.Splits))
+	for _, s := range req.Splits {
+		if !isMember[s.UserID] {
+			return errors.New("every participant must be a group member")
+		}
+		if seen[s.UserID] {
+			return errors.New("duplicate participant " + s.UserID)
+		}
+		seen[s.UserID] = true
+	}
+	return nil
+}
+
+// SplitShares divides amount between participants according to splitType. Shares
+// always add up to amount; leftover minor units go to the first participants
+// (equal) or those with the largest fractional remainder (percentage).
+func SplitShares(amount int64, splitType string, splits []ExpenseShare) ([]ExpenseShare, error) {
+	shares := make([]ExpenseShare, len(splits))
+	switch splitType {
+	case SplitEqual:
+		n := int64(len(splits))
+		for i, s := range splits {
+			shares[i] = ExpenseShare{UserID: s.UserID, Amount: amount / n}
+			if int64(i) < amount%n {
+				shares[i].Amount++
+			}
+		}
+
+	case SplitExact:
+		var total int64
+		for i, s := range splits {
+			if s.Amount < 0 || s.Amount > amount {
+				return nil, errors.New("exact shares must be between 0 and the expense amount")
+			}
+			shares[i] = ExpenseShare{UserID: s.UserID, Amount: s.Amount}
+			total += s.Amount
+		}
+		if total != amount {
+			return nil, errors.New("exact shares must add up to the expense amount")
+		}
+
+	case SplitPercentage:
+		// Work in basis points so percentages with two decimals are exact
+		var totalBP int64
+		bps := make([]int64, len(splits))
+		for i, s := range splits {
+			if s.Percent == nil || *s.Percent < 0 || *s.Percent > 100 {
+				return nil, errors.New("every participant needs a percent between 0 and 100")
+			}
+			bp := math.Round(*s.Percent * 100)
+			if math.Abs(bp-*s.Percent*100) > 1e-6 {
+				return nil, errors.New("percent may have at most two decimals")
+			}
+			bps[i] = int64(bp)
+			totalBP += bps[i]
+		}
+		if totalBP != 10000 {
+			return nil, errors.New("percentages must add up to 100")
+		}
+
+		var assigned int64
+		order := make([]int, len(splits))
+		for i, s := range splits {
+			p := float64(bps[i]) / 100
+			shares[i] = ExpenseShare{UserID: s.UserID, Amount: amount * bps[i] / 10000, Percent: &p}
+			assigned += shares[i].Amount
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return amount*bps[order[a]]%10000 > amount*bps[order[b]]%10000
+		})
+		for i := int64(0); i < amount-assigned; i++ {
+			shares[order[i]].Amount++
+		}
+
+	default:
+		return nil, errors.New("split_type must be one of: equal, exact, percentage")
+	}
+	return shares, nil
+}
+
+// Validate checks a settlement before it is recorded.
+func (s *Settlement) Validate() error {
+	s.FromUser = strings.TrimSpace(s.FromUser)
+	s.ToUser = strings.TrimSpace(s.ToUser)
+	if s.FromUser == "" || s.ToUser == "" {
+		return errors.New("from_user and to_user are required")
+	}
+	if s.FromUser == s.ToUser {
+		return errors.New("from_user and to_user must differ")
+	}
+	if err := validAmount(s.Amount); err != nil {
+		return err
+	}
+	currency, err := normalizeCurrency(s.Currency)
+	if err != nil {
+		return err
+	}
+	s.Currency = currency
+	return nil
+}
+
+// ErrSettlementExceedsBalance means a settlement is more than the payer owes
+// or the recipient is owed.
+var ErrSettlementExceedsBalance = errors.New("amount is more than the outstanding balance")
+
+// WithinBalance reports whether the settlement is no more than FromUser owes
+// and ToUser is owed in its currency. The payer may have left the group but
+// still owe money, so this goes by balances rather than membership.
+func (s *Settlement) WithinBalance(balances []Balance) bool {
+	var debt, credit int64
+	for _, b := range balances {
+		if b.Currency != s.Currency {
+			continue
+		}
+		switch b.UserID {
+		case s.FromUser:
+			debt = -b.Net
+		case s.ToUser:
+			credit = b.Net
+		}
+	}
+	return s.Amount <= debt && s.Amount <= credit
+}
+
+// ComputeBalances totals what each member paid and owes per currency and
+// applies settlements. Members whose every total is zero are left out.
+func ComputeBalances(expenses []Expense, settlements []Settlement) []Balance {
+	type key struct{ currency, user string }
+	byKey := make(map[key]*Balance)
+	get := func(currency, user string) *Balance {
+		k := key{currency, user}
+		if byKey[k] == nil {
+			byKey[k] = &Balance{UserID: user, Currency: currency}
+		}
+		return byKey[k]
+	}
+
+	for _, e := range expenses {
+		payer := get(e.Currency, e.PaidBy)
+		payer.Paid += e.Amount
+		payer.Net += e.Amount
+		for _, s := range e.Shares {
+			b := get(e.Currency, s.UserID)
+			b.Share += s.Amount
+			b.Net -= s.Amount
+		}
+	}
+	for _, s := range settlements {
+		get(s.Currency, s.FromUser).Net += s.Amount
+		get(s.Currency, s.ToUser).Net -= s.Amount
+	}
+
+	balances := make([]Balance, 0, len(byKey))
+	for _, b := range byKey {
+		if b.Paid != 0 || b.Share != 0 || b.Net != 0 {
+			balances = append(balances, *b)
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Currency != balances[j].Currency {
+			return balances[i].Currency < balances[j].Currency
+		}
+		return balances[i].UserID < balances[j].UserID
+	})
+	return balances
+}
+
+// SettleUp returns transfers that bring every net balance to zero. Within each
+// currency the largest debtor repeatedly pays the largest creditor, which
+// needs at most one transfer fewer than the number of members with a balance.
+func SettleUp(balances []Balance) []Transfer {
+	byCurrency := make(map[string][]Balance)
+	var currencies []string
+	for _, b := range balances {
+		if b.Net == 0 {
+			continue
+		}
+		if _, ok := byCurrency[b.Currency]; !ok {
+			currencies = append(currencies, b.Currency)
+		}
+		byCurrency[b.Currency] = append(byCurrency[b.Currency], b)
+	}
+	sort.Strings(currencies)
+
+	transfers := []Transfer{}
+	for _, currency := range currencies {
+		var creditors, debtors []Balance
+		for _, b := range byCurrency[currency] {
+			if b.Net > 0 {
+				creditors = append(creditors, b)
+			} else {
+				b.Net = -b.Net
+				debtors = append(debtors, b)
+			}
+		}
+		largestFirst := func(bs []Balance) {
+			sort.Slice(bs, func(i, j int) bool {
+				if bs[i].Net != bs[j].Net {
+					return bs[i].Net > bs[j].Net
+				}
+				return bs[i].UserID < bs[j].UserID
+			})
+		}
+
+		for len(creditors) > 0 && len(debtors) > 0 {
+			largestFirst(creditors)
+			largestFirst(debtors)
+			c, d := &creditors[0], &debtors[0]
+			amount := c.Net
+			if d.Net < amount {
+				amount = d.Net
+			}
+			transfers = append(transfers, Transfer{FromUser: d.UserID, ToUser: c.UserID, Amount: amount, Currency: currency})
+			c.Net -= amount
+			d.Net -= amount
+			if c.Net == 0 {
+				creditors = creditors[1:]
+			}
+			if d.Net == 0 {
+				debtors = debtors[1:]
+			}
+		}
+	}
+	return transfers
+}
@@ -0,0 +1,272 @@
+package groups
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// expenseMembers returns the user ids of the group's current members.
+func (h *Handler) expenseMembers(r *http.Request, groupID string) ([]string, error) {
+	members, err := h.repo.GetGroupMembers(r.Context(), groupID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(members))
+	for i, m := range members {
+		ids[i] = m.UserID
+	}
+	return ids, nil
+}
+
+// GET /groups/{id}/expenses — List the group's expenses, newest first (members only)
+func (h *Handler) ListExpenses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, _, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can view expenses", http.StatusForbidden)
+		return
+	}
+
+	expenses, err := h.repo.GetExpenses(r.Context(), groupID)
+	if err != nil {
+		http.Error(w, "failed to get expenses", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(expenses)
+}
+
+// POST /groups/{id}/expenses — Record an expense and how it is split (members only)
+func (h *Handler) CreateExpense(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can add expenses", http.StatusForbidden)
+		return
+	}
+
+	var req CreateExpenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.expenseMembers(r, groupID)
+	if err != nil {
+		http.Error(w, "failed to get group members", http.StatusInternalServerError)
+		return
+	}
+	if err := req.Validate(userID, members); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	shares, err := SplitShares(req.Amount, req.SplitType, req.Splits)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	expense := &Expense{
+		GroupID:     groupID,
+		Description: req.Description,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		PaidBy:      req.PaidBy,
+		SplitType:   req.SplitType,
+		Shares:      shares,
+		CreatedBy:   userID,
+	}
+	if err := h.repo.CreateExpense(r.Context(), expense); err != nil {
+		http.Error(w, "failed to create expense", http.StatusInternalServerError)
+		return
+	}
+	h.groupUpdated(r.Context(), groupID, userID, messages.GroupChangeExpenses)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(expense)
+}
+
+// DELETE /groups/{id}/expenses/{expenseId} — Only the payer or whoever added it may delete
+func (h *Handler) DeleteExpense(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can edit expenses", http.StatusForbidden)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	expenseID := parts[3]
+
+	expense, err := h.repo.GetExpense(r.Context(), groupID, expenseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "expense not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to get expense", http.StatusInternalServerError)
+		return
+	}
+	if expense.PaidBy != userID && expense.CreatedBy != userID {
+		http.Error(w, "only the payer or the member who added the expense can delete it", http.StatusForbidden)
+		return
+	}
+
+	if err := h.repo.DeleteExpense(r.Context(), groupID, expenseID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "expense not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to delete expense", http.StatusInternalServerError)
+		return
+	}
+	h.groupUpdated(r.Context(), groupID, userID, messages.GroupChangeExpenses)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "expense deleted"})
+}
+
+// expenseBalances computes the group's balances from its expenses and settlements.
+func (h *Handler) expenseBalances(r *http.Request, groupID string) ([]Balance, error) {
+	expenses, err := h.repo.GetExpenses(r.Context(), groupID)
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := h.repo.GetSettlements(r.Context(), groupID)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeBalances(expenses, settlements), nil
+}
+
+// GET /groups/{id}/expenses/balances — Per-member balances and a settle-up plan (members only)
+func (h *Handler) GetExpenseBalances(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, _, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can view expenses", http.StatusForbidden)
+		return
+	}
+
+	balances, err := h.expenseBalances(r, groupID)
+	if err != nil {
+		http.Error(w, "failed to compute balances", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ExpenseSummary{Balances: balances, SettleUp: SettleUp(balances)})
+}
+
+// GET /groups/{id}/expenses/settlements — Payments marked as paid, newest first (members only)
+func (h *Handler) ListSettlements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, _, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can view expenses", http.StatusForbidden)
+		return
+	}
+
+	settlements, err := h.repo.GetSettlements(r.Context(), groupID)
+	if err != nil {
+		http.Error(w, "failed to get settlements", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settlements)
+}
+
+// POST /groups/{id}/expenses/settlements — Mark a payment as received.
+// Only the recipient can confirm it, and only up to what the payer still owes them.
+func (h *Handler) CreateSettlement(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can settle expenses", http.StatusForbidden)
+		return
+	}
+
+	var s Settlement
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := s.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if s.ToUser != userID {
+		http.Error(w, "only the recipient can mark a payment as paid", http.StatusForbidden)
+		return
+	}
+
+	s.GroupID = groupID
+	s.CreatedBy = userID
+	if err := h.repo.CreateSettlement(r.Context(), &s); err != nil {
+		if errors.Is(err, ErrSettlementExceedsBalance) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "failed to record settlement", http.StatusInternalServerError)
+		return
+	}
+	h.groupUpdated(r.Context(), groupID, userID, messages.GroupChangeExpenses)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(s)
+}
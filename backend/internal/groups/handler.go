@@ -1,25 +1,55 @@
 package groups
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/muskan953/college-Hop/internal/auth"
 	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/moderation"
 )
 
-const DefaultThreshold = 0.1 // Minimum Jaccard score to keep a group
+const DefaultThreshold = 0.1 // Minimum interest similarity to keep a peer match
 
 type Handler struct {
-	repo Repository
-	hub  *messages.Hub
+	repo      Repository
+	hub       *messages.Hub
+	moderator *moderation.Moderator
+	scorer    Scorer
 }
 
-func NewHandler(repo Repository, hub *messages.Hub) *Handler {
-	return &Handler{repo: repo, hub: hub}
+// NewHandler creates a groups Handler. A nil scorer ranks with DefaultWeights.
+func NewHandler(repo Repository, hub *messages.Hub, moderator *moderation.Moderator, scorer Scorer) *Handler {
+	if scorer == nil {
+		scorer = NewWeightedScorer(DefaultWeights)
+	}
+	return &Handler{repo: repo, hub: hub, moderator: moderator, scorer: scorer}
+}
+
+// postSystemMessage records a membership or settings change in the group chat.
+// Failures are logged; the change itself has already been committed.
+func (h *Handler) postSystemMessage(ctx context.Context, groupID, actorID, kind string, metadata map[string]string) {
+	if h.hub == nil {
+		return
+	}
+	threadID, err := h.repo.GetGroupThreadID(ctx, groupID)
+	if err != nil {
+		log.Printf("[Groups] No chat thread for group %s: %v", groupID, err)
+		return
+	}
+	if _, err := h.hub.PostSystemMessage(ctx, threadID, actorID, kind, metadata); err != nil {
+		log.Printf("[Groups] Failed to post %s message in group %s: %v", kind, groupID, err)
+	}
 }
 
 // POST /groups — Create a new travel group
@@ -52,15 +82,41 @@ func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		maxMembers = 4
 	}
 
+	if req.GenderPreference == "" {
+		req.GenderPreference = GenderAny
+	}
+	if !ValidGenderPreference(req.GenderPreference) {
+		http.Error(w, "gender_preference must be one of: any, women_only, men_only", http.StatusBadRequest)
+		return
+	}
+	if err := validateRoute(req.Origin, req.Destination, req.TransportMode); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	verdict := h.moderator.Check(moderation.Input{
+		Field: moderation.FieldGroupDescription,
+		Text:  strings.TrimSpace(req.Description),
+	})
+	if verdict.Rejected() {
+		http.Error(w, "description was blocked by the content filter", http.StatusBadRequest)
+		return
+	}
+
 	group := &Group{
 		EventID:       req.EventID,
 		Name:          req.Name,
-		Description:   strings.TrimSpace(req.Description),
+		Description:   verdict.Text,
 		CreatedBy:     user.ID,
 		MaxMembers:       maxMembers,
 		DepartureDate:    req.DepartureDate,
 		MeetingPoint:     strings.TrimSpace(req.MeetingPoint),
 		RequiresApproval: req.RequiresApproval,
+		GenderPreference: req.GenderPreference,
+		Origin:           req.Origin,
+		Destination:      req.Destination,
+		TransportMode:    req.TransportMode,
+		Status:           StatusOpen,
 	}
 
 	if err := h.repo.CreateGroup(r.Context(), group); err != nil {
@@ -68,6 +124,13 @@ func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.moderator.FlagForReview(r.Context(), verdict, moderation.Flag{
+		TargetType: "group",
+		TargetID:   group.ID,
+		UserID:     user.ID,
+		Content:    group.Description,
+	})
+
 	// Auto-join creator as first member
 	if err := h.repo.JoinGroup(r.Context(), group.ID, user.ID); err != nil {
 		http.Error(w, "failed to join group", http.StatusInternalServerError)
@@ -79,69 +142,81 @@ func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(group)
 }
 
-// POST /groups/{id}/join — Join a travel group
-func (h *Handler) JoinGroup(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+// notifyUser sends an in-app and push notification; it is a no-op without a hub.
+func (h *Handler) notifyUser(ctx context.Context, userID, title, body string, data map[string]string) {
+	if h.hub == nil {
 		return
 	}
+	h.hub.SendNotification(ctx, userID, title, body, data)
+}
 
-	user, ok := auth.UserFromContext(r.Context())
-	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
-		return
+// genderAllowed checks the group's gender preference against the user's profile.
+// It writes the error response and returns false if the user may not join.
+func (h *Handler) genderAllowed(w http.ResponseWriter, r *http.Request, group *Group, userID string) bool {
+	if group.GenderPreference == "" || group.GenderPreference == GenderAny {
+		return true
+	}
+	profile, err := h.repo.GetMatchProfile(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "failed to get profile", http.StatusInternalServerError)
+		return false
+	}
+	if !GenderAllowed(group.GenderPreference, profile.Gender) {
+		http.Error(w, "this group is limited to "+strings.TrimSuffix(group.GenderPreference, "_only"), http.StatusForbidden)
+		return false
 	}
+	return true
+}
 
-	// Extract group ID from URL: /groups/{id}/join
-	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
-	if len(parts) < 3 {
-		http.Error(w, "invalid URL", http.StatusBadRequest)
-		return
+// memberRole returns the user's role in the group, or "" if they are not a member.
+// It writes the error response and returns ok=false on failure.
+func (h *Handler) memberRole(w http.ResponseWriter, r *http.Request, groupID, userID string) (role string, ok bool) {
+	role, err := h.repo.GetMemberRole(r.Context(), groupID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", true
 	}
-	groupID := parts[1]
+	if err != nil {
+		http.Error(w, "failed to check membership", http.StatusInternalServerError)
+		return "", false
+	}
+	return role, true
+}
 
-	// Verify the group exists before attempting the atomic join.
-	if _, err := h.repo.GetGroup(r.Context(), groupID); err != nil {
-		http.Error(w, "group not found", http.StatusNotFound)
+// announceNewOwner posts a role_changed message for whoever owns the group
+// after previousOwnerID left it.
+func (h *Handler) announceNewOwner(ctx context.Context, groupID, previousOwnerID string) {
+	members, err := h.repo.GetGroupMembers(ctx, groupID)
+	if err != nil {
+		log.Printf("[Groups] Failed to look up new owner of group %s: %v", groupID, err)
 		return
 	}
-
-	if createdRequest, err := h.repo.JoinGroupChecked(r.Context(), groupID, user.ID); err != nil {
-		if errors.Is(err, ErrGroupFull) {
-			http.Error(w, "group is full", http.StatusBadRequest)
+	for _, m := range members {
+		if m.Role == RoleOwner {
+			h.postSystemMessage(ctx, groupID, previousOwnerID, messages.KindRoleChanged, map[string]string{
+				"user_id":        m.UserID,
+				"role":           RoleOwner,
+				"previous_owner": previousOwnerID,
+			})
+			h.groupUpdated(ctx, groupID, previousOwnerID, messages.GroupChangeRoles)
 			return
 		}
-		http.Error(w, "failed to join group", http.StatusInternalServerError)
-		return
-	} else if createdRequest {
-		// NOTIFY THE CREATOR!
-		group, _ := h.repo.GetGroup(r.Context(), groupID) // To get Creator ID
-		if group != nil {
-			h.hub.SendNotification(r.Context(), group.CreatedBy, "New Join Request", "Someone requested to join "+group.Name, map[string]string{"type": "notification", "group_id": groupID})
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusAccepted)
-		json.NewEncoder(w).Encode(map[string]string{"message": "request to join sent"})
-		return
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "joined group"})
 }
 
-// GET /groups/{id}/requests — Get pending join requests
-func (h *Handler) GetJoinRequests(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+// POST /groups/{id}/join — Join a travel group
+func (h *Handler) JoinGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+
 	user, ok := auth.UserFromContext(r.Context())
 	if !ok {
 		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
+	// Extract group ID from URL: /groups/{id}/join
 	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
 	if len(parts) < 3 {
 		http.Error(w, "invalid URL", http.StatusBadRequest)
@@ -149,84 +224,78 @@ func (h *Handler) GetJoinRequests(w http.ResponseWriter, r *http.Request) {
 	}
 	groupID := parts[1]
 
-	group, err := h.repo.GetGroup(r.Context(), groupID)
+	// Verify the group exists before attempting the atomic join.
+	target, err := h.repo.GetGroup(r.Context(), groupID)
 	if err != nil {
 		http.Error(w, "group not found", http.StatusNotFound)
 		return
 	}
-	if group.CreatedBy != user.ID {
-		http.Error(w, "forbidden: must be creator to view requests", http.StatusForbidden)
-		return
-	}
-
-	requests, err := h.repo.GetJoinRequests(r.Context(), groupID)
-	if err != nil {
-		http.Error(w, "failed to get requests", http.StatusInternalServerError)
+	if !h.genderAllowed(w, r, target, user.ID) {
 		return
 	}
-	
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(requests)
-}
-
-// POST /groups/{id}/requests/{userId}/accept
-func (h *Handler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
-	h.handleRequestAction(w, r, "accept")
-}
 
-// POST /groups/{id}/requests/{userId}/decline
-func (h *Handler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
-	h.handleRequestAction(w, r, "decline")
-}
-
-func (h *Handler) handleRequestAction(w http.ResponseWriter, r *http.Request, action string) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
+	// The body is optional: a note for the owner when the group requires approval
+	var req JoinGroupRequest
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
 	}
-	user, ok := auth.UserFromContext(r.Context())
-	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	req.Message = strings.TrimSpace(req.Message)
+	if utf8.RuneCountInString(req.Message) > MaxJoinRequestMessage {
+		http.Error(w, fmt.Sprintf("message must be at most %d characters", MaxJoinRequestMessage), http.StatusBadRequest)
 		return
 	}
-
-	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
-	if len(parts) < 5 { // groups/{id}/requests/{userId}/accept
-		http.Error(w, "invalid URL", http.StatusBadRequest)
+	verdict := h.moderator.Check(moderation.Input{
+		Field:         moderation.FieldMessage,
+		Text:          req.Message,
+		RequestThread: true,
+	})
+	if verdict.Rejected() {
+		http.Error(w, "message was blocked by the content filter", http.StatusBadRequest)
 		return
 	}
-	groupID := parts[1]
-	targetUserID := parts[3]
 
-	group, err := h.repo.GetGroup(r.Context(), groupID)
-	if err != nil {
-		http.Error(w, "group not found", http.StatusNotFound)
-		return
-	}
-	if group.CreatedBy != user.ID {
-		http.Error(w, "forbidden: must be creator to manage requests", http.StatusForbidden)
+	if createdRequest, err := h.repo.JoinGroupChecked(r.Context(), groupID, user.ID, false, verdict.Text); err != nil {
+		if errors.Is(err, ErrGroupFull) {
+			h.joinWaitlist(w, r, groupID, user.ID)
+			return
+		}
+		if errors.Is(err, ErrGroupClosed) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "failed to join group", http.StatusInternalServerError)
 		return
-	}
-
-	if action == "accept" {
-		err = h.repo.AcceptJoinRequest(r.Context(), groupID, targetUserID)
-		if err == nil {
-			h.hub.SendNotification(r.Context(), targetUserID, "Request Accepted!", "You've been added to "+group.Name, map[string]string{"type": "notification", "group_id": groupID})
+	} else if createdRequest {
+		// Notify everyone who can accept the request
+		members, _ := h.repo.GetGroupMembers(r.Context(), groupID)
+		for _, m := range members {
+			if CanManage(m.Role) {
+				h.notifyUser(r.Context(), m.UserID, "New Join Request", "Someone requested to join "+target.Name, map[string]string{"type": "notification", "group_id": groupID})
+			}
 		}
-	} else {
-		err = h.repo.DeclineJoinRequest(r.Context(), groupID, targetUserID)
-	}
+		h.publishToManagers(r.Context(), groupID, messages.EventJoinRequestReceived, messages.WSJoinRequestReceived{
+			GroupID: groupID,
+			UserID:  user.ID,
+			Message: verdict.Text,
+		})
 
-	if err != nil {
-		http.Error(w, "failed to process request", http.StatusInternalServerError)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"message": "request to join sent"})
 		return
 	}
 
+	h.postSystemMessage(r.Context(), groupID, user.ID, messages.KindMemberJoined, map[string]string{"user_id": user.ID})
+	h.memberJoined(r.Context(), groupID, user.ID, messages.JoinedDirectly, "")
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "request " + action + "ed"})
+	json.NewEncoder(w).Encode(map[string]string{"message": "joined group"})
 }
 
-// GET /groups/suggested?event_id=xxx — Get suggested groups with matching
+// GET /groups/suggested?event_id=xxx[&departure_date=YYYY-MM-DD][&origin=...] — Get suggested groups ranked by the match scorer
 func (h *Handler) SuggestedGroups(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -245,95 +314,77 @@ func (h *Handler) SuggestedGroups(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 1. Get user's interests (1 query)
-	userInterests, err := h.repo.GetUserInterests(r.Context(), user.ID)
+	var departureDate *time.Time
+	if d := r.URL.Query().Get("departure_date"); d != "" {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			http.Error(w, "invalid departure_date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		departureDate = &t
+	}
+
+	origin, err := ParseOriginFilter(r.URL.Query())
 	if err != nil {
-		http.Error(w, "failed to get user interests", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// 1. Get the user's matching attributes (college, city, gender, interests)
+	profile, err := h.repo.GetMatchProfile(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "failed to get user profile", http.StatusInternalServerError)
+		return
+	}
+	profile.DepartureDate = departureDate
+	if origin != nil {
+		profile.Origin = origin.Place
+	} else if profile.HomeCity != "" {
+		profile.Origin = &Place{City: profile.HomeCity}
+	}
+
 	// 2. Get all groups for this event WITH member counts (1 query, replaces GetGroupsForEvent + N×GetMemberCount)
 	eventGroups, err := h.repo.GetGroupsWithCountsForEvent(r.Context(), eventID)
 	if err != nil {
 		http.Error(w, "failed to get groups", http.StatusInternalServerError)
 		return
 	}
+	if origin != nil {
+		eventGroups = origin.Apply(eventGroups)
+	}
 
-	// 3. Get all member interests for every group in one shot (1 query, replaces N×GetGroupMemberInterests)
-	allMemberInterests, err := h.repo.GetGroupMemberInterestsForEvent(r.Context(), eventID)
+	// 3. Get every member's profile for every group in one shot
+	allMembers, err := h.repo.GetGroupMemberProfilesForEvent(r.Context(), eventID)
 	if err != nil {
-		http.Error(w, "failed to get group interests", http.StatusInternalServerError)
+		http.Error(w, "failed to get group members", http.StatusInternalServerError)
 		return
 	}
 
-	// 4. Score each group using strict filtering + Log-Enhanced Jaccard
-	var results []GroupWithDetails
+	// 4. Score each group, dropping the ones the user cannot join
+	results := []GroupWithDetails{}
 	for _, g := range eventGroups {
-		// Skip full groups (count already embedded in GroupWithDetails)
-		if g.MemberCount >= g.MaxMembers {
-			continue
-		}
-
-		memberInterestsList := allMemberInterests[g.ID] // nil if group has no members with interests
-
-		// Strict filtering: check each member
-		rejected := false
-		totalScore := 0.0
-		allInterests := make(map[string]bool)
-
-		for _, memberInterests := range memberInterestsList {
-			score := CalculateSimilarity(userInterests, memberInterests)
-			if score < DefaultThreshold {
-				rejected = true
-				break
-			}
-			totalScore += score
-			for _, interest := range memberInterests {
-				allInterests[interest] = true
-			}
-		}
-
-		if rejected {
+		match := h.scorer.Score(profile, g, allMembers[g.ID])
+		if !match.Eligible {
 			continue
 		}
-
-		avgScore := 0.0
-		if len(memberInterestsList) > 0 {
-			avgScore = totalScore / float64(len(memberInterestsList))
-		}
-
-		// Collect unique shared interests between the user and the group members
-		var interests []string
-		for k := range allInterests {
-			// Only add if the user also has this interest
-			for _, ui := range userInterests {
-				if ui == k {
-					interests = append(interests, k)
-					break
-				}
-			}
-		}
-
 		result := g // copy GroupWithDetails (already has MemberCount)
-		result.MatchScore = avgScore
-		result.Interests = interests
+		result.MatchScore = match.Score
+		result.MatchBreakdown = match.Breakdown
+		result.Interests = match.SharedInterests
 		results = append(results, result)
 	}
 
 	// 5. Sort by match score descending
-	sort.Slice(results, func(i, j int) bool {
+	sort.SliceStable(results, func(i, j int) bool {
 		return results[i].MatchScore > results[j].MatchScore
 	})
 
-	if results == nil {
-		results = []GroupWithDetails{}
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(results)
 }
 
-// GET /groups — List all travel groups with is_joined flag for the requesting user
+// GET /groups[?event_id=&departure_from=&departure_to=&open_seats=&requires_approval=&college=&origin=...&q=&sort=&cursor=&limit=]
+// — Search travel groups, with an is_joined flag for the requesting user
 func (h *Handler) ListAllGroups(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -346,17 +397,71 @@ func (h *Handler) ListAllGroups(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	groups, err := h.repo.GetAllGroups(r.Context(), user.ID)
+	search, err := ParseGroupSearch(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	groups, err := h.repo.SearchGroups(r.Context(), user.ID, search)
 	if err != nil {
 		http.Error(w, "failed to get groups", http.StatusInternalServerError)
 		return
 	}
-	if groups == nil {
-		groups = []GroupWithDetails{}
+
+	var next *GroupCursor
+	if search.InDatabase() {
+		groups, next = search.page(groups)
+	} else {
+		if search.Origin != nil {
+			groups = search.Origin.Apply(groups)
+		}
+		if search.Sort == SortMatch {
+			if err := h.scoreGroups(r.Context(), user.ID, search, groups); err != nil {
+				http.Error(w, "failed to score groups", http.StatusInternalServerError)
+				return
+			}
+		}
+		groups, next = search.paginate(groups)
+	}
+
+	resp := GroupPage{Groups: groups}
+	if next != nil {
+		resp.NextCursor = next.Encode()
+		resp.HasMore = true
+	}
+	if resp.Groups == nil {
+		resp.Groups = []GroupWithDetails{}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(groups)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// scoreGroups sets the caller's match score and shared interests on groups,
+// which all belong to search.EventID.
+func (h *Handler) scoreGroups(ctx context.Context, userID string, search GroupSearch, groups []GroupWithDetails) error {
+	profile, err := h.repo.GetMatchProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if search.Origin != nil {
+		profile.Origin = search.Origin.Place
+	} else if profile.HomeCity != "" {
+		profile.Origin = &Place{City: profile.HomeCity}
+	}
+	allMembers, err := h.repo.GetGroupMemberProfilesForEvent(ctx, search.EventID)
+	if err != nil {
+		return err
+	}
+
+	for i := range groups {
+		match := h.scorer.Score(profile, groups[i], allMembers[groups[i].ID])
+		groups[i].MatchScore = match.Score
+		groups[i].MatchBreakdown = match.Breakdown
+		groups[i].Interests = match.SharedInterests
+	}
+	return nil
 }
 
 // GET /me/groups — List all groups the authenticated user belongs to
@@ -435,7 +540,7 @@ func (h *Handler) GetGroup(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
-// PUT /groups/{id} — Update group name/description (creator only)
+// PUT /groups/{id} — Update group name/description (owner or admins)
 func (h *Handler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -460,8 +565,16 @@ func (h *Handler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "group not found", http.StatusNotFound)
 		return
 	}
-	if group.CreatedBy != user.ID {
-		http.Error(w, "only the group creator can update the group", http.StatusForbidden)
+	role, ok := h.memberRole(w, r, groupID, user.ID)
+	if !ok {
+		return
+	}
+	if !CanManage(role) {
+		http.Error(w, "only group admins can update the group", http.StatusForbidden)
+		return
+	}
+	if group.Status == StatusArchived {
+		http.Error(w, "archived groups cannot be changed", http.StatusConflict)
 		return
 	}
 
@@ -476,16 +589,66 @@ func (h *Handler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.repo.UpdateGroup(r.Context(), groupID, req.Name, strings.TrimSpace(req.Description), strings.TrimSpace(req.MeetingPoint), req.DepartureDate); err != nil {
+	verdict := h.moderator.Check(moderation.Input{
+		Field: moderation.FieldGroupDescription,
+		Text:  strings.TrimSpace(req.Description),
+	})
+	if verdict.Rejected() {
+		http.Error(w, "description was blocked by the content filter", http.StatusBadRequest)
+		return
+	}
+
+	if req.GenderPreference != "" && !ValidGenderPreference(req.GenderPreference) {
+		http.Error(w, "gender_preference must be one of: any, women_only, men_only", http.StatusBadRequest)
+		return
+	}
+	if err := validateRoute(req.Origin, req.Destination, req.TransportMode); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated := *group
+	updated.Name = req.Name
+	updated.Description = verdict.Text
+	updated.DepartureDate = req.DepartureDate
+	updated.MeetingPoint = strings.TrimSpace(req.MeetingPoint)
+	if req.GenderPreference != "" {
+		updated.GenderPreference = req.GenderPreference
+	}
+	if req.Origin != nil {
+		updated.Origin = req.Origin
+	}
+	if req.Destination != nil {
+		updated.Destination = req.Destination
+	}
+	if req.TransportMode != "" {
+		updated.TransportMode = req.TransportMode
+	}
+	if err := h.repo.UpdateGroup(r.Context(), &updated); err != nil {
 		http.Error(w, "failed to update group", http.StatusInternalServerError)
 		return
 	}
 
+	if updated.Name != group.Name {
+		h.postSystemMessage(r.Context(), groupID, user.ID, messages.KindGroupRenamed, map[string]string{"old_name": group.Name, "name": updated.Name})
+	}
+	if updated.MeetingPoint != group.MeetingPoint {
+		h.postSystemMessage(r.Context(), groupID, user.ID, messages.KindMeetingPointChanged, map[string]string{"old_meeting_point": group.MeetingPoint, "meeting_point": updated.MeetingPoint})
+	}
+	h.groupUpdated(r.Context(), groupID, user.ID, messages.GroupChangeDetails)
+
+	h.moderator.FlagForReview(r.Context(), verdict, moderation.Flag{
+		TargetType: "group",
+		TargetID:   groupID,
+		UserID:     user.ID,
+		Content:    verdict.Text,
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "group updated"})
 }
 
-// DELETE /groups/{id} — Delete the group (creator only)
+// DELETE /groups/{id} — Delete the group (owner only)
 func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -505,26 +668,48 @@ func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	}
 	groupID := parts[1]
 
-	group, err := h.repo.GetGroup(r.Context(), groupID)
-	if err != nil {
+	if _, err := h.repo.GetGroup(r.Context(), groupID); err != nil {
 		http.Error(w, "group not found", http.StatusNotFound)
 		return
 	}
-	if group.CreatedBy != user.ID {
-		http.Error(w, "only the group creator can delete the group", http.StatusForbidden)
+	role, ok := h.memberRole(w, r, groupID, user.ID)
+	if !ok {
+		return
+	}
+	if role != RoleOwner {
+		http.Error(w, "only the group owner can delete the group", http.StatusForbidden)
 		return
 	}
 
+	// Look up who to tell before the memberships are gone
+	var memberIDs []string
+	if h.hub != nil {
+		members, err := h.repo.GetGroupMembers(r.Context(), groupID)
+		if err != nil {
+			log.Printf("[Groups] Failed to load members of group %s before deleting it: %v", groupID, err)
+		}
+		for _, m := range members {
+			memberIDs = append(memberIDs, m.UserID)
+		}
+	}
+
 	if err := h.repo.DeleteGroup(r.Context(), groupID); err != nil {
 		http.Error(w, "failed to delete group", http.StatusInternalServerError)
 		return
 	}
 
+	if h.hub != nil {
+		h.hub.SendToUsers(memberIDs, messages.WSOutgoing{
+			Type:    messages.EventGroupUpdated,
+			Payload: messages.WSGroupUpdated{GroupID: groupID, Change: messages.GroupChangeDeleted, ActorID: user.ID},
+		})
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "group deleted"})
 }
 
-// POST /groups/{id}/leave — Leave a group (any member except creator)
+// POST /groups/{id}/leave — Leave a group. An owner hands the group to the longest-standing member.
 func (h *Handler) LeaveGroup(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -545,37 +730,48 @@ func (h *Handler) LeaveGroup(w http.ResponseWriter, r *http.Request) {
 	}
 	groupID := parts[1]
 
-	group, err := h.repo.GetGroup(r.Context(), groupID)
-	if err != nil {
+	if _, err := h.repo.GetGroup(r.Context(), groupID); err != nil {
 		http.Error(w, "group not found", http.StatusNotFound)
 		return
 	}
 
-	if group.CreatedBy == user.ID {
-		http.Error(w, "you are the group creator — delete the group instead of leaving", http.StatusBadRequest)
-		return
-	}
-
-	isMember, err := h.repo.IsGroupMember(r.Context(), groupID, user.ID)
-	if err != nil {
-		http.Error(w, "server error", http.StatusInternalServerError)
+	role, ok := h.memberRole(w, r, groupID, user.ID)
+	if !ok {
 		return
 	}
-	if !isMember {
+	if role == "" {
 		http.Error(w, "you are not a member of this group", http.StatusBadRequest)
 		return
 	}
+	if role == RoleOwner {
+		count, err := h.repo.GetMemberCount(r.Context(), groupID)
+		if err != nil {
+			http.Error(w, "server error", http.StatusInternalServerError)
+			return
+		}
+		if count <= 1 {
+			http.Error(w, "you are the only member — delete the group instead of leaving", http.StatusBadRequest)
+			return
+		}
+	}
 
 	if err := h.repo.RemoveMember(r.Context(), groupID, user.ID); err != nil {
 		http.Error(w, "failed to leave group", http.StatusInternalServerError)
 		return
 	}
 
+	h.postSystemMessage(r.Context(), groupID, user.ID, messages.KindMemberLeft, map[string]string{"user_id": user.ID})
+	h.memberLeft(r.Context(), groupID, user.ID, "")
+	if role == RoleOwner {
+		h.announceNewOwner(r.Context(), groupID, user.ID)
+	}
+	h.fillFromWaitlist(r.Context(), groupID)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "left group"})
 }
 
-// POST /groups/{id}/kick — Kick a member from the group (creator only)
+// POST /groups/{id}/kick — Kick a member from the group (owner, or admins for plain members)
 func (h *Handler) KickMember(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -595,13 +791,16 @@ func (h *Handler) KickMember(w http.ResponseWriter, r *http.Request) {
 	}
 	groupID := parts[1]
 
-	group, err := h.repo.GetGroup(r.Context(), groupID)
-	if err != nil {
+	if _, err := h.repo.GetGroup(r.Context(), groupID); err != nil {
 		http.Error(w, "group not found", http.StatusNotFound)
 		return
 	}
-	if group.CreatedBy != user.ID {
-		http.Error(w, "only the group creator can kick members", http.StatusForbidden)
+	role, ok := h.memberRole(w, r, groupID, user.ID)
+	if !ok {
+		return
+	}
+	if !CanManage(role) {
+		http.Error(w, "only group admins can kick members", http.StatusForbidden)
 		return
 	}
 
@@ -611,25 +810,32 @@ func (h *Handler) KickMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if req.UserID == user.ID {
-		http.Error(w, "you cannot kick yourself — delete the group instead", http.StatusBadRequest)
+		http.Error(w, "you cannot kick yourself — leave the group instead", http.StatusBadRequest)
 		return
 	}
 
-	isMember, err := h.repo.IsGroupMember(r.Context(), groupID, req.UserID)
-	if err != nil {
-		http.Error(w, "server error", http.StatusInternalServerError)
+	targetRole, ok := h.memberRole(w, r, groupID, req.UserID)
+	if !ok {
 		return
 	}
-	if !isMember {
+	if targetRole == "" {
 		http.Error(w, "user is not a member of this group", http.StatusBadRequest)
 		return
 	}
+	if !CanRemove(role, targetRole) {
+		http.Error(w, "only the group owner can remove admins", http.StatusForbidden)
+		return
+	}
 
 	if err := h.repo.RemoveMember(r.Context(), groupID, req.UserID); err != nil {
 		http.Error(w, "failed to kick member", http.StatusInternalServerError)
 		return
 	}
 
+	h.postSystemMessage(r.Context(), groupID, user.ID, messages.KindMemberKicked, map[string]string{"user_id": req.UserID})
+	h.memberLeft(r.Context(), groupID, req.UserID, user.ID)
+	h.fillFromWaitlist(r.Context(), groupID)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "member removed"})
 }
@@ -704,3 +910,31 @@ func (h *Handler) FindMatches(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(matches)
 }
+
+// memberGroup resolves the group in /groups/{id}/... and whether the
+// user is a member. It writes the error response and returns ok=false on failure.
+func (h *Handler) memberGroup(w http.ResponseWriter, r *http.Request) (groupID, userID string, member, ok bool) {
+	user, authed := auth.UserFromContext(r.Context())
+	if !authed {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return "", "", false, false
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return "", "", false, false
+	}
+	groupID = parts[1]
+
+	if _, err := h.repo.GetGroup(r.Context(), groupID); err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return "", "", false, false
+	}
+	member, err := h.repo.IsGroupMember(r.Context(), groupID, user.ID)
+	if err != nil {
+		http.Error(w, "failed to check membership", http.StatusInternalServerError)
+		return "", "", false, false
+	}
+	return groupID, user.ID, member, true
+}
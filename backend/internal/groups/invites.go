@@ -0,0 +1,134 @@
+package groups
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Kinds of group invites.
+const (
+	InviteLink   = "link"   // shareable code anyone can redeem
+	InviteDirect = "direct" // addressed to one user
+)
+
+// Invite statuses. Link invites stay active until revoked, expired or used up.
+const (
+	InviteActive   = "active"
+	InviteAccepted = "accepted"
+	InviteDeclined = "declined"
+	InviteRevoked  = "revoked"
+)
+
+// Invite lifetime and usage limits.
+const (
+	DefaultInviteTTL = 7 * 24 * time.Hour
+	MaxInviteTTL     = 30 * 24 * time.Hour
+	MaxInviteUses    = 100
+)
+
+var (
+	// ErrInviteUnavailable is returned by UseInvite when the invite was revoked,
+	// expired or used up before it could be claimed.
+	ErrInviteUnavailable = errors.New("invite is no longer valid")
+	// ErrInviteeNotFound is returned by CreateInvite when the invited user does not exist.
+	ErrInviteeNotFound = errors.New("user not found")
+)
+
+// Invite lets a user join a group without approval, capacity permitting.
+type Invite struct {
+	ID        string    `json:"id"`
+	GroupID   string    `json:"group_id"`
+	Code      string    `json:"code"`
+	Kind      string    `json:"kind"`
+	InviteeID string    `json:"invitee_id,omitempty"`
+	CreatedBy string    `json:"created_by"`
+	MaxUses   *int      `json:"max_uses,omitempty"` // nil = unlimited
+	Uses      int       `json:"uses"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InviteDetails is an invite with the group it leads to, shown before accepting.
+type InviteDetails struct {
+	Invite
+	GroupName   string `json:"group_name"`
+	EventID     string `json:"event_id"`
+	MemberCount int    `json:"member_count"`
+	MaxMembers  int    `json:"max_members"`
+}
+
+// CreateInviteRequest is the payload for POST /groups/{id}/invites.
+// Setting UserID creates a single-use direct invite; otherwise a link is created.
+type CreateInviteRequest struct {
+	UserID         string `json:"user_id"`
+	MaxUses        *int   `json:"max_uses"`
+	ExpiresInHours *int   `json:"expires_in_hours"`
+}
+
+// Invite checks the request and builds the invite it describes, with a fresh code.
+func (req *CreateInviteRequest) Invite(groupID, creatorID string, now time.Time) (*Invite, error) {
+	req.UserID = strings.TrimSpace(req.UserID)
+	inv := &Invite{
+		GroupID:   groupID,
+		Kind:      InviteLink,
+		CreatedBy: creatorID,
+		ExpiresAt: now.Add(DefaultInviteTTL),
+		Status:    InviteActive,
+	}
+
+	if req.ExpiresInHours != nil {
+		ttl := time.Duration(*req.ExpiresInHours) * time.Hour
+		if ttl <= 0 || ttl > MaxInviteTTL {
+			return nil, errors.New("expires_in_hours must be between 1 and 720")
+		}
+		inv.ExpiresAt = now.Add(ttl)
+	}
+	if req.UserID != "" {
+		if req.MaxUses != nil {
+			return nil, errors.New("max_uses only applies to invite links")
+		}
+		if req.UserID == creatorID {
+			return nil, errors.New("you cannot invite yourself")
+		}
+		one := 1
+		inv.Kind, inv.InviteeID, inv.MaxUses = InviteDirect, req.UserID, &one
+	} else if req.MaxUses != nil {
+		if *req.MaxUses < 1 || *req.MaxUses > MaxInviteUses {
+			return nil, errors.New("max_uses must be between 1 and 100")
+		}
+		inv.MaxUses = req.MaxUses
+	}
+
+	code, err := newInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	inv.Code = code
+	return inv, nil
+}
+
+// newInviteCode returns a random URL-safe code of 22 characters.
+func newInviteCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Usable reports why the invite can no longer be redeemed, or nil if it can.
+func (inv *Invite) Usable(now time.Time) error {
+	switch {
+	case inv.Status != InviteActive:
+		return ErrInviteUnavailable
+	case !now.Before(inv.ExpiresAt):
+		return errors.New("invite has expired")
+	case inv.MaxUses != nil && inv.Uses >= *inv.MaxUses:
+		return errors.New("invite has been used up")
+	}
+	return nil
+}
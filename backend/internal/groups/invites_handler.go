@@ -0,0 +1,325 @@
+package groups
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// managedGroup resolves the group in /groups/{id}/... and checks that the user
+// may manage it. It writes the error response and returns ok=false on failure.
+func (h *Handler) managedGroup(w http.ResponseWriter, r *http.Request) (group *Group, userID string, ok bool) {
+	user, authed := auth.UserFromContext(r.Context())
+	if !authed {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, "", false
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return nil, "", false
+	}
+
+	group, err := h.repo.GetGroup(r.Context(), parts[1])
+	if err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return nil, "", false
+	}
+	group.ID = parts[1]
+	role, ok := h.memberRole(w, r, group.ID, user.ID)
+	if !ok {
+		return nil, "", false
+	}
+	if !CanManage(role) {
+		http.Error(w, "forbidden: must be a group admin to manage invites", http.StatusForbidden)
+		return nil, "", false
+	}
+	return group, user.ID, true
+}
+
+// POST /groups/{id}/invites — Create an invite link, or invite a user directly (admins only)
+func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	group, userID, ok := h.managedGroup(w, r)
+	if !ok {
+		return
+	}
+	if group.Status != StatusOpen {
+		http.Error(w, ErrGroupClosed.Error(), http.StatusConflict)
+		return
+	}
+
+	var req CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	invite, err := req.Invite(group.ID, userID, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if invite.InviteeID != "" {
+		member, err := h.repo.IsGroupMember(r.Context(), group.ID, invite.InviteeID)
+		if err != nil {
+			http.Error(w, "failed to check membership", http.StatusInternalServerError)
+			return
+		}
+		if member {
+			http.Error(w, "user is already a member", http.StatusConflict)
+			return
+		}
+	}
+
+	if err := h.repo.CreateInvite(r.Context(), invite); err != nil {
+		if errors.Is(err, ErrInviteeNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to create invite", http.StatusInternalServerError)
+		return
+	}
+
+	if invite.InviteeID != "" {
+		h.notifyUser(r.Context(), invite.InviteeID, "Group Invite", "You've been invited to join "+group.Name,
+			map[string]string{"type": "notification", "group_id": group.ID, "invite_code": invite.Code})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(invite)
+}
+
+// GET /groups/{id}/invites — List active invites (admins only)
+func (h *Handler) ListInvites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	group, _, ok := h.managedGroup(w, r)
+	if !ok {
+		return
+	}
+
+	invites, err := h.repo.GetInvites(r.Context(), group.ID)
+	if err != nil {
+		http.Error(w, "failed to get invites", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invites)
+}
+
+// DELETE /groups/{id}/invites/{inviteId} — Revoke an invite (admins only)
+func (h *Handler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	group, _, ok := h.managedGroup(w, r)
+	if !ok {
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.CloseInvite(r.Context(), group.ID, parts[3], InviteRevoked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "invite not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to revoke invite", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "invite revoked"})
+}
+
+// GET /me/invites — Direct invites waiting for the current user
+func (h *Handler) GetMyInvites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	invites, err := h.repo.GetUserInvites(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "failed to get invites", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invites)
+}
+
+// inviteByCode resolves /invites/{code}/... and checks the invite is addressed
+// to the user, if to anyone. It writes the error response and returns ok=false on failure.
+func (h *Handler) inviteByCode(w http.ResponseWriter, r *http.Request) (invite *InviteDetails, userID string, ok bool) {
+	user, authed := auth.UserFromContext(r.Context())
+	if !authed {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, "", false
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return nil, "", false
+	}
+
+	invite, err := h.repo.GetInviteByCode(r.Context(), parts[1])
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "invite not found", http.StatusNotFound)
+		return nil, "", false
+	}
+	if err != nil {
+		http.Error(w, "failed to get invite", http.StatusInternalServerError)
+		return nil, "", false
+	}
+	if invite.InviteeID != "" && invite.InviteeID != user.ID {
+		http.Error(w, "this invite is for another user", http.StatusForbidden)
+		return nil, "", false
+	}
+	return invite, user.ID, true
+}
+
+// GET /invites/{code} — Preview the group an invite leads to
+func (h *Handler) GetInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	invite, _, ok := h.inviteByCode(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invite)
+}
+
+// POST /invites/{code}/accept — Join the group, skipping approval but not the member limit
+func (h *Handler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	invite, userID, ok := h.inviteByCode(w, r)
+	if !ok {
+		return
+	}
+	if err := invite.Usable(time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
+
+	member, err := h.repo.IsGroupMember(r.Context(), invite.GroupID, userID)
+	if err != nil {
+		http.Error(w, "failed to check membership", http.StatusInternalServerError)
+		return
+	}
+	if member {
+		http.Error(w, "already a member of this group", http.StatusConflict)
+		return
+	}
+
+	group, err := h.repo.GetGroup(r.Context(), invite.GroupID)
+	if err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return
+	}
+	if !h.genderAllowed(w, r, group, userID) {
+		return
+	}
+
+	// Claim the use first so concurrent redemptions cannot exceed max_uses
+	if err := h.repo.UseInvite(r.Context(), invite.ID); err != nil {
+		if errors.Is(err, ErrInviteUnavailable) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		}
+		http.Error(w, "failed to accept invite", http.StatusInternalServerError)
+		return
+	}
+	if _, err := h.repo.JoinGroupChecked(r.Context(), invite.GroupID, userID, true, ""); err != nil {
+		if relErr := h.repo.ReleaseInvite(r.Context(), invite.ID); relErr != nil {
+			log.Printf("[Groups] Failed to release invite %s: %v", invite.ID, relErr)
+		}
+		if errors.Is(err, ErrGroupFull) {
+			http.Error(w, "group is full", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrGroupClosed) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "failed to join group", http.StatusInternalServerError)
+		return
+	}
+
+	h.postSystemMessage(r.Context(), invite.GroupID, userID, messages.KindMemberJoined, map[string]string{
+		"user_id":    userID,
+		"invited_by": invite.CreatedBy,
+	})
+	h.memberJoined(r.Context(), invite.GroupID, userID, messages.JoinedByInvite, "")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "joined group", "group_id": invite.GroupID})
+}
+
+// POST /invites/{code}/decline — Turn down a direct invite
+func (h *Handler) DeclineInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	invite, _, ok := h.inviteByCode(w, r)
+	if !ok {
+		return
+	}
+	if invite.Kind != InviteDirect {
+		http.Error(w, "only direct invites can be declined", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.CloseInvite(r.Context(), invite.GroupID, invite.ID, InviteDeclined); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, ErrInviteUnavailable.Error(), http.StatusGone)
+			return
+		}
+		http.Error(w, "failed to decline invite", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "invite declined"})
+}
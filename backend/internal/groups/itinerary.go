@@ -0,0 +1,115 @@
+package groups
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxItineraryLegs caps how many legs one group itinerary may have.
+const MaxItineraryLegs = 10
+
+// ErrTooManyLegs is returned when an itinerary would exceed MaxItineraryLegs.
+var ErrTooManyLegs = errors.New("too many legs (max 10)")
+
+// Actions recorded in the metadata of itinerary_updated system messages.
+const (
+	ItinerarySaved      = "saved"
+	ItineraryRemoved    = "removed"
+	ItineraryLegAdded   = "leg_added"
+	ItineraryLegUpdated = "leg_updated"
+	ItineraryLegRemoved = "leg_removed"
+)
+
+// ItineraryLeg is one train, bus, flight or carpool ride of a group's trip.
+type ItineraryLeg struct {
+	ID               string    `json:"id"`
+	Mode             string    `json:"mode,omitempty"` // train, bus, flight, carpool
+	Carrier          string    `json:"carrier,omitempty"`
+	Number           string    `json:"number,omitempty"` // train, bus or flight number
+	DepartureStation string    `json:"departure_station"`
+	DepartureTime    time.Time `json:"departure_time"`
+	ArrivalStation   string    `json:"arrival_station"`
+	ArrivalTime      time.Time `json:"arrival_time"`
+	// BookingReference is the PNR or booking code; only returned to group members.
+	BookingReference string `json:"booking_reference,omitempty"`
+}
+
+// Itinerary is the shared ticket plan of a travel group, legs in departure order.
+type Itinerary struct {
+	GroupID   string         `json:"group_id"`
+	Legs      []ItineraryLeg `json:"legs"`
+	UpdatedBy string         `json:"updated_by,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// SaveItineraryRequest is the payload for PUT /groups/{id}/itinerary
+type SaveItineraryRequest struct {
+	Legs []ItineraryLeg `json:"legs"`
+}
+
+// Validate trims the leg's text fields and checks that it is complete.
+func (l *ItineraryLeg) Validate() error {
+	l.Mode = strings.TrimSpace(l.Mode)
+	l.Carrier = strings.TrimSpace(l.Carrier)
+	l.Number = strings.TrimSpace(l.Number)
+	l.DepartureStation = strings.TrimSpace(l.DepartureStation)
+	l.ArrivalStation = strings.TrimSpace(l.ArrivalStation)
+	l.BookingReference = strings.TrimSpace(l.BookingReference)
+
+	switch {
+	case l.Mode != "" && !ValidTransportMode(l.Mode):
+		return errors.New("mode must be one of: train, bus, flight, carpool")
+	case l.DepartureStation == "" || l.ArrivalStation == "":
+		return errors.New("departure_station and arrival_station are required")
+	case len(l.DepartureStation) > 100 || len(l.ArrivalStation) > 100:
+		return errors.New("station too long (max 100 chars)")
+	case len(l.Carrier) > 50:
+		return errors.New("carrier too long (max 50 chars)")
+	case len(l.Number) > 20:
+		return errors.New("number too long (max 20 chars)")
+	case len(l.BookingReference) > 30:
+		return errors.New("booking_reference too long (max 30 chars)")
+	case l.DepartureTime.IsZero() || l.ArrivalTime.IsZero():
+		return errors.New("departure_time and arrival_time are required")
+	case !l.ArrivalTime.After(l.DepartureTime):
+		return errors.New("arrival_time must be after departure_time")
+	}
+	return nil
+}
+
+// prepareLegs validates legs, assigns ids to new ones and sorts them by departure.
+// Ids not in existing are replaced so a client cannot pick or steal leg ids.
+func prepareLegs(legs []ItineraryLeg, existing []ItineraryLeg) error {
+	if len(legs) > MaxItineraryLegs {
+		return ErrTooManyLegs
+	}
+	known := make(map[string]bool, len(existing))
+	for _, l := range existing {
+		known[l.ID] = true
+	}
+	seen := make(map[string]bool, len(legs))
+	for i := range legs {
+		if err := legs[i].Validate(); err != nil {
+			return err
+		}
+		if !known[legs[i].ID] || seen[legs[i].ID] {
+			legs[i].ID = uuid.NewString()
+		}
+		seen[legs[i].ID] = true
+	}
+	sort.SliceStable(legs, func(i, j int) bool {
+		return legs[i].DepartureTime.Before(legs[j].DepartureTime)
+	})
+	return nil
+}
+
+// HideBookingReferences strips PNRs before the itinerary is shown to a non-member.
+func (it *Itinerary) HideBookingReferences() {
+	for i := range it.Legs {
+		it.Legs[i].BookingReference = ""
+	}
+}
@@ -0,0 +1,263 @@
+package groups
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// loadItineraryLegs returns the current legs, or none if the group has no itinerary yet.
+func (h *Handler) loadItineraryLegs(r *http.Request, groupID string) ([]ItineraryLeg, error) {
+	it, err := h.repo.GetItinerary(r.Context(), groupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []ItineraryLeg{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return it.Legs, nil
+}
+
+// itineraryChanged announces an itinerary edit in the group chat and writes the
+// saved itinerary as the response.
+func (h *Handler) itineraryChanged(w http.ResponseWriter, r *http.Request, groupID, userID string, it *Itinerary, action string, status int) {
+	// Booking references stay out of the chat; members read them from the itinerary
+	h.postSystemMessage(r.Context(), groupID, userID, messages.KindItineraryUpdated, map[string]string{
+		"action": action,
+		"legs":   strconv.Itoa(len(it.Legs)),
+	})
+	h.groupUpdated(r.Context(), groupID, userID, messages.GroupChangeItinerary)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(it)
+}
+
+// GET /groups/{id}/itinerary — Booking references are only shown to members
+func (h *Handler) GetItinerary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, _, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+
+	it, err := h.repo.GetItinerary(r.Context(), groupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "group has no itinerary", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to get itinerary", http.StatusInternalServerError)
+		return
+	}
+	if !member {
+		it.HideBookingReferences()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(it)
+}
+
+// PUT /groups/{id}/itinerary — Create or replace the itinerary (members only)
+func (h *Handler) SaveItinerary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can edit the itinerary", http.StatusForbidden)
+		return
+	}
+
+	var req SaveItineraryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Legs == nil {
+		req.Legs = []ItineraryLeg{}
+	}
+
+	existing, err := h.loadItineraryLegs(r, groupID)
+	if err != nil {
+		http.Error(w, "failed to get itinerary", http.StatusInternalServerError)
+		return
+	}
+	if err := prepareLegs(req.Legs, existing); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	it, err := h.repo.SaveItinerary(r.Context(), groupID, userID, req.Legs)
+	if err != nil {
+		http.Error(w, "failed to save itinerary", http.StatusInternalServerError)
+		return
+	}
+	h.itineraryChanged(w, r, groupID, userID, it, ItinerarySaved, http.StatusOK)
+}
+
+// DELETE /groups/{id}/itinerary — Remove the itinerary (members only)
+func (h *Handler) DeleteItinerary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can edit the itinerary", http.StatusForbidden)
+		return
+	}
+
+	if err := h.repo.DeleteItinerary(r.Context(), groupID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "group has no itinerary", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to delete itinerary", http.StatusInternalServerError)
+		return
+	}
+
+	h.postSystemMessage(r.Context(), groupID, userID, messages.KindItineraryUpdated, map[string]string{
+		"action": ItineraryRemoved,
+		"legs":   "0",
+	})
+	h.groupUpdated(r.Context(), groupID, userID, messages.GroupChangeItinerary)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "itinerary deleted"})
+}
+
+// POST /groups/{id}/itinerary/legs — Add a leg, creating the itinerary if needed (members only)
+func (h *Handler) AddItineraryLeg(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can edit the itinerary", http.StatusForbidden)
+		return
+	}
+
+	var leg ItineraryLeg
+	if err := json.NewDecoder(r.Body).Decode(&leg); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := leg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	leg.ID = uuid.NewString()
+
+	it, err := h.repo.AddItineraryLeg(r.Context(), groupID, userID, leg)
+	if errors.Is(err, ErrTooManyLegs) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to save itinerary", http.StatusInternalServerError)
+		return
+	}
+	h.itineraryChanged(w, r, groupID, userID, it, ItineraryLegAdded, http.StatusCreated)
+}
+
+// itineraryLeg resolves /groups/{id}/itinerary/legs/{legId} for a member.
+func (h *Handler) itineraryLeg(w http.ResponseWriter, r *http.Request) (groupID, userID, legID string, ok bool) {
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return "", "", "", false
+	}
+	if !member {
+		http.Error(w, "only group members can edit the itinerary", http.StatusForbidden)
+		return "", "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return "", "", "", false
+	}
+	return groupID, userID, parts[4], true
+}
+
+// PUT /groups/{id}/itinerary/legs/{legId} — Replace one leg (members only)
+func (h *Handler) UpdateItineraryLeg(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, legID, ok := h.itineraryLeg(w, r)
+	if !ok {
+		return
+	}
+
+	var leg ItineraryLeg
+	if err := json.NewDecoder(r.Body).Decode(&leg); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := leg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	leg.ID = legID
+
+	it, err := h.repo.UpdateItineraryLeg(r.Context(), groupID, userID, leg)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "leg not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to save itinerary", http.StatusInternalServerError)
+		return
+	}
+	h.itineraryChanged(w, r, groupID, userID, it, ItineraryLegUpdated, http.StatusOK)
+}
+
+// DELETE /groups/{id}/itinerary/legs/{legId} — Remove one leg (members only)
+func (h *Handler) DeleteItineraryLeg(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, legID, ok := h.itineraryLeg(w, r)
+	if !ok {
+		return
+	}
+
+	it, err := h.repo.DeleteItineraryLeg(r.Context(), groupID, userID, legID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "leg not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to save itinerary", http.StatusInternalServerError)
+		return
+	}
+	h.itineraryChanged(w, r, groupID, userID, it, ItineraryLegRemoved, http.StatusOK)
+}
@@ -0,0 +1,71 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// Group lifecycle states. Only open groups accept new members.
+const (
+	StatusOpen      = "open"
+	StatusLocked    = "locked"    // the owner has stopped new joins
+	StatusTraveling = "traveling" // departure time has passed
+	StatusCompleted = "completed" // the event is over
+	StatusArchived  = "archived"  // hidden from listings; the chat is read-only
+)
+
+// ArchiveAfter is how long a completed group stays around, for settling up
+// and rating each other, before it is archived.
+const ArchiveAfter = 14 * 24 * time.Hour
+
+// ErrGroupClosed is returned when someone tries to join a group that is not open.
+var ErrGroupClosed = errors.New("group is not accepting new members")
+
+// StatusChange is a lifecycle transition made by the Archiver.
+type StatusChange struct {
+	GroupID string
+	Status  string
+}
+
+// Archiver periodically moves groups through their lifecycle: traveling once
+// they depart, completed the day after their event ends, and archived
+// ArchiveAfter later.
+type Archiver struct {
+	repo     Repository
+	interval time.Duration
+}
+
+// NewArchiver creates an Archiver that runs every 15 minutes.
+func NewArchiver(repo Repository) *Archiver {
+	return &Archiver{repo: repo, interval: 15 * time.Minute}
+}
+
+// Run advances group lifecycles until ctx is cancelled. Start it as a goroutine.
+func (a *Archiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Advance(ctx)
+		}
+	}
+}
+
+// Advance applies every transition that is due now and returns them.
+func (a *Archiver) Advance(ctx context.Context) []StatusChange {
+	changes, err := a.repo.AdvanceGroupLifecycle(ctx, time.Now(), ArchiveAfter)
+	if err != nil {
+		log.Printf("[Archiver] Failed to advance group lifecycles: %v", err)
+		return nil
+	}
+	if len(changes) > 0 {
+		log.Printf("[Archiver] Moved %d group(s) to a new state", len(changes))
+	}
+	return changes
+}
@@ -0,0 +1,53 @@
+package groups
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// POST /groups/{id}/lock — Stop new joins (owner only)
+func (h *Handler) LockGroup(w http.ResponseWriter, r *http.Request) {
+	h.setGroupStatus(w, r, StatusOpen, StatusLocked)
+}
+
+// POST /groups/{id}/unlock — Accept new joins again, filling open spots from the waitlist (owner only)
+func (h *Handler) UnlockGroup(w http.ResponseWriter, r *http.Request) {
+	h.setGroupStatus(w, r, StatusLocked, StatusOpen)
+}
+
+func (h *Handler) setGroupStatus(w http.ResponseWriter, r *http.Request, from, to string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, ok := h.ownedGroup(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.SetGroupStatus(r.Context(), groupID, from, to); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "only "+from+" groups can be changed to "+to, http.StatusConflict)
+			return
+		}
+		http.Error(w, "failed to update group", http.StatusInternalServerError)
+		return
+	}
+	h.publish(r.Context(), groupID, messages.EventGroupUpdated, messages.WSGroupUpdated{
+		GroupID: groupID,
+		Change:  messages.GroupChangeStatus,
+		ActorID: userID,
+		Status:  to,
+	})
+	if to == StatusOpen {
+		h.fillFromWaitlist(r.Context(), groupID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": to})
+}
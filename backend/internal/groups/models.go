@@ -14,6 +14,11 @@ type Group struct {
 	DepartureDate    *time.Time `json:"departure_date,omitempty"`
 	MeetingPoint     string     `json:"meeting_point,omitempty"`
 	RequiresApproval bool       `json:"requires_approval"`
+	GenderPreference string     `json:"gender_preference"`
+	Origin           *Place     `json:"origin,omitempty"`
+	Destination      *Place     `json:"destination,omitempty"`
+	TransportMode    string     `json:"transport_mode,omitempty"`
+	Status           string     `json:"status"` // see StatusOpen and friends
 }
 
 // GroupWithDetails includes member count and match info for API responses
@@ -23,6 +28,10 @@ type GroupWithDetails struct {
 	IsJoined    bool     `json:"is_joined"`
 	MatchScore  float64  `json:"match_score"`
 	Interests   []string `json:"interests"` // combined unique interests of all members
+	// MatchBreakdown explains MatchScore; only set by GET /groups/suggested
+	MatchBreakdown []FactorScore `json:"match_breakdown,omitempty"`
+	// DistanceKm is how far the group's origin is from the requested origin
+	DistanceKm *float64 `json:"distance_km,omitempty"`
 }
 
 // CreateGroupRequest is the payload for POST /groups
@@ -34,6 +43,10 @@ type CreateGroupRequest struct {
 	DepartureDate    *time.Time `json:"departure_date,omitempty"`
 	MeetingPoint     string     `json:"meeting_point,omitempty"`
 	RequiresApproval bool       `json:"requires_approval"`
+	GenderPreference string     `json:"gender_preference,omitempty"` // any (default), women_only, men_only
+	Origin           *Place     `json:"origin,omitempty"`
+	Destination      *Place     `json:"destination,omitempty"`
+	TransportMode    string     `json:"transport_mode,omitempty"` // train, bus, flight, carpool
 }
 
 // GroupMember represents a member in a travel group
@@ -45,11 +58,13 @@ type GroupMember struct {
 
 // GroupMemberProfile contains displayable info about a group member
 type GroupMemberProfile struct {
-	UserID          string    `json:"user_id"`
-	FullName        string    `json:"full_name"`
-	CollegeName     string    `json:"college_name"`
-	ProfilePhotoURL string    `json:"profile_photo_url,omitempty"`
-	JoinedAt        time.Time `json:"joined_at"`
+	UserID          string      `json:"user_id"`
+	FullName        string      `json:"full_name"`
+	CollegeName     string      `json:"college_name"`
+	ProfilePhotoURL string      `json:"profile_photo_url,omitempty"`
+	Role            string      `json:"role,omitempty"` // owner, admin or member; empty for join requests
+	JoinedAt        time.Time   `json:"joined_at"`
+	TrustScore      *TrustScore `json:"trust_score,omitempty"` // nil below MinTrustRatings
 }
 
 // GroupDetailResponse is returned by GET /groups/{id} — full group info with members
@@ -67,6 +82,11 @@ type UpdateGroupRequest struct {
 	Description   string     `json:"description"`
 	DepartureDate *time.Time `json:"departure_date,omitempty"`
 	MeetingPoint  string     `json:"meeting_point,omitempty"`
+	// GenderPreference, Origin, Destination and TransportMode are left unchanged when omitted
+	GenderPreference string `json:"gender_preference,omitempty"`
+	Origin           *Place `json:"origin,omitempty"`
+	Destination      *Place `json:"destination,omitempty"`
+	TransportMode    string `json:"transport_mode,omitempty"`
 }
 
 // KickRequest is the payload for POST /groups/{id}/kick
@@ -74,6 +94,11 @@ type KickRequest struct {
 	UserID string `json:"user_id"`
 }
 
+// TransferRequest is the payload for POST /groups/{id}/transfer
+type TransferRequest struct {
+	UserID string `json:"user_id"`
+}
+
 // MatchedUser represents a peer match result
 type MatchedUser struct {
 	UserID          string   `json:"user_id"`
@@ -0,0 +1,71 @@
+package groups
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// MinTrustRatings is how many ratings a user needs before their trust score
+// is shown or used for matching.
+const MinTrustRatings = 3
+
+// ErrAlreadyRated is returned by CreateRating when the rater has already rated
+// the member for this trip.
+var ErrAlreadyRated = errors.New("you have already rated this member for this trip")
+
+// Rating is one member's rating of another after a completed trip. Each
+// criterion is scored from 1 to 5.
+type Rating struct {
+	ID            string    `json:"id"`
+	GroupID       string    `json:"group_id"`
+	RaterID       string    `json:"rater_id"`
+	RateeID       string    `json:"ratee_id"`
+	Punctuality   int       `json:"punctuality"`
+	Communication int       `json:"communication"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// RateMemberRequest is the payload for POST /groups/{id}/ratings.
+type RateMemberRequest struct {
+	UserID        string `json:"user_id"`
+	Punctuality   int    `json:"punctuality"`
+	Communication int    `json:"communication"`
+}
+
+// Validate checks that both criteria are between 1 and 5.
+func (r RateMemberRequest) Validate() error {
+	if r.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	if r.Punctuality < 1 || r.Punctuality > 5 || r.Communication < 1 || r.Communication > 5 {
+		return errors.New("punctuality and communication must be between 1 and 5")
+	}
+	return nil
+}
+
+// TrustScore aggregates the ratings a user has received. Score is the mean of
+// the punctuality and communication averages, from 1 to 5.
+type TrustScore struct {
+	Score         float64 `json:"score"`
+	Punctuality   float64 `json:"punctuality"`
+	Communication float64 `json:"communication"`
+	Ratings       int     `json:"ratings"`
+}
+
+// NewTrustScore builds a TrustScore from a user's rating count and averages.
+// It returns nil below MinTrustRatings so that a few ratings cannot define
+// someone.
+func NewTrustScore(ratings int, punctuality, communication float64) *TrustScore {
+	if ratings < MinTrustRatings {
+		return nil
+	}
+	return &TrustScore{
+		Score:         round2((punctuality + communication) / 2),
+		Punctuality:   round2(punctuality),
+		Communication: round2(communication),
+		Ratings:       ratings,
+	}
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
@@ -0,0 +1,106 @@
+package groups
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// GET /groups/{id}/ratings — The ratings the caller has given in this group (members only)
+func (h *Handler) ListMyRatings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can view ratings", http.StatusForbidden)
+		return
+	}
+
+	ratings, err := h.repo.GetRatingsByRater(r.Context(), groupID, userID)
+	if err != nil {
+		http.Error(w, "failed to get ratings", http.StatusInternalServerError)
+		return
+	}
+	if ratings == nil {
+		ratings = []Rating{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ratings)
+}
+
+// POST /groups/{id}/ratings — Rate another member once the trip is completed (members only, once per member)
+func (h *Handler) RateMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, member, ok := h.memberGroup(w, r)
+	if !ok {
+		return
+	}
+	if !member {
+		http.Error(w, "only group members can rate each other", http.StatusForbidden)
+		return
+	}
+
+	group, err := h.repo.GetGroup(r.Context(), groupID)
+	if err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return
+	}
+	if group.Status != StatusCompleted {
+		http.Error(w, "members can only be rated once the trip is completed", http.StatusConflict)
+		return
+	}
+
+	var req RateMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.UserID == userID {
+		http.Error(w, "you cannot rate yourself", http.StatusBadRequest)
+		return
+	}
+	rateeMember, err := h.repo.IsGroupMember(r.Context(), groupID, req.UserID)
+	if err != nil {
+		http.Error(w, "failed to check membership", http.StatusInternalServerError)
+		return
+	}
+	if !rateeMember {
+		http.Error(w, "user is not a member of this group", http.StatusBadRequest)
+		return
+	}
+
+	rating := &Rating{
+		GroupID:       groupID,
+		RaterID:       userID,
+		RateeID:       req.UserID,
+		Punctuality:   req.Punctuality,
+		Communication: req.Communication,
+	}
+	if err := h.repo.CreateRating(r.Context(), rating); err != nil {
+		if errors.Is(err, ErrAlreadyRated) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "failed to save rating", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rating)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -15,16 +17,19 @@ type Repository interface {
 	GetGroup(ctx context.Context, groupID string) (*Group, error)
 	GetGroupThreadID(ctx context.Context, groupID string) (string, error)
 	// JoinGroup is a plain insert used internally (e.g. auto-join on create).
+	// The first member of a group becomes its owner.
 	JoinGroup(ctx context.Context, groupID, userID string) error
 	// JoinGroupChecked atomically checks capacity and joins in one transaction.
 	// Returns true if a request was created, false if direct join.
-	// Returns ErrGroupFull if the group is already at max capacity.
-	JoinGroupChecked(ctx context.Context, groupID, userID string) (bool, error)
+	// Returns ErrGroupFull if the group is already at max capacity; spots held
+	// by live waitlist offers to other users count as taken.
+	// skipApproval joins directly even if the group requires approval (invites).
+	JoinGroupChecked(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error)
 	GetMemberCount(ctx context.Context, groupID string) (int, error)
 	// GetGroupsForEvent is kept for backward compatibility; prefer GetGroupsWithCountsForEvent.
 	GetGroupsForEvent(ctx context.Context, eventID string) ([]Group, error)
-	// GetGroupsWithCountsForEvent returns all groups for an event with live member counts
-	// in a single query (replaces GetGroupsForEvent + per-group GetMemberCount).
+	// GetGroupsWithCountsForEvent returns the open and locked groups for an event with live
+	// member counts in a single query (replaces GetGroupsForEvent + per-group GetMemberCount).
 	GetGroupsWithCountsForEvent(ctx context.Context, eventID string) ([]GroupWithDetails, error)
 	// GetGroupMemberInterestsForEvent returns all member interest lists for every group in
 	// an event in a single query, keyed by group ID. Replaces the per-group loop.
@@ -33,22 +38,137 @@ type Repository interface {
 	// GetUsersForEvent returns users attending an event with their interests in one query.
 	GetUsersForEvent(ctx context.Context, eventID, excludeUserID string) ([]UserWithInterests, error)
 	GetUserInterests(ctx context.Context, userID string) ([]string, error)
+	// GetMatchProfile returns the profile attributes used to score groups for a user.
+	GetMatchProfile(ctx context.Context, userID string) (MatchProfile, error)
+	// GetMatchProfiles is GetMatchProfile for several users in one query, keyed
+	// by user ID. Users without a profile get an empty one.
+	GetMatchProfiles(ctx context.Context, userIDs []string) (map[string]MatchProfile, error)
+	// GetGroupMemberProfilesForEvent returns the match profile of every member of
+	// every group in an event, keyed by group ID.
+	GetGroupMemberProfilesForEvent(ctx context.Context, eventID string) (map[string][]MatchProfile, error)
+	// GetGroupMemberProfiles returns the match profile of every member of one group.
+	GetGroupMemberProfiles(ctx context.Context, groupID string) ([]MatchProfile, error)
 	// Group management
 	GetGroupMembers(ctx context.Context, groupID string) ([]GroupMemberProfile, error)
-	UpdateGroup(ctx context.Context, groupID, name, description, meetingPoint string, departureDate *time.Time) error
+	// UpdateGroup saves the editable fields of group: name, description, departure
+	// date, meeting point, gender preference and route.
+	UpdateGroup(ctx context.Context, group *Group) error
 	DeleteGroup(ctx context.Context, groupID string) error
+	// SetGroupStatus moves the group from one lifecycle state to another.
+	// It returns sql.ErrNoRows if the group is not currently in from.
+	SetGroupStatus(ctx context.Context, groupID, from, to string) error
+	// AdvanceGroupLifecycle applies every transition due at now: open and
+	// locked groups start traveling at departure, groups complete the day after
+	// their event ends, and completed groups are archived after archiveAfter,
+	// which also makes their chat read-only. Waitlists of groups that can no
+	// longer be joined are cleared.
+	AdvanceGroupLifecycle(ctx context.Context, now time.Time, archiveAfter time.Duration) ([]StatusChange, error)
+	// RemoveMember removes a user from the group. If they were the owner, the
+	// longest-standing remaining member becomes owner in the same transaction.
+	// Ownership changes also move the group's created_by to the new owner.
 	RemoveMember(ctx context.Context, groupID, userID string) error
 	IsGroupMember(ctx context.Context, groupID, userID string) (bool, error)
 	GetUserGroups(ctx context.Context, userID string) ([]GroupWithDetails, error)
-	// GetAllGroups returns all travel groups along with member count and whether
-	// the given userID is currently a member of each group.
-	GetAllGroups(ctx context.Context, userID string) ([]GroupWithDetails, error)
-
-	// Join requests
+	// SearchGroups returns travel groups that are not completed or archived and
+	// pass the search filters, with member counts and whether userID is a member
+	// of each. When search.InDatabase() it also applies the sort order and
+	// cursor and returns up to search.Limit+1 groups; otherwise it returns every
+	// matching group, newest first, and leaves the coordinate origin filter,
+	// ordering and paging to the caller.
+	SearchGroups(ctx context.Context, userID string, search GroupSearch) ([]GroupWithDetails, error)
+
+	// Join requests. AcceptJoinRequest, DeclineJoinRequest and CancelJoinRequest
+	// only act on live pending requests and return sql.ErrNoRows otherwise.
+	// AcceptJoinRequest checks the group under the same lock as JoinGroupChecked
+	// and returns ErrGroupClosed or ErrGroupFull.
 	CreateJoinRequest(ctx context.Context, groupID, userID string) error
-	GetJoinRequests(ctx context.Context, groupID string) ([]GroupMemberProfile, error)
+	// GetJoinRequests returns the group's live pending requests, newest first.
+	// MatchScore and CommonInterests are left for the caller to fill in.
+	GetJoinRequests(ctx context.Context, groupID string) ([]JoinRequest, error)
 	AcceptJoinRequest(ctx context.Context, groupID, userID string) error
 	DeclineJoinRequest(ctx context.Context, groupID, userID string) error
+	CancelJoinRequest(ctx context.Context, groupID, userID string) error
+	// GetUserJoinRequests returns every request userID has sent, newest first.
+	GetUserJoinRequests(ctx context.Context, userID string) ([]OutgoingJoinRequest, error)
+	// ExpireJoinRequests marks up to limit pending requests that expired at or
+	// before now as expired and returns them.
+	ExpireJoinRequests(ctx context.Context, now time.Time, limit int) ([]OutgoingJoinRequest, error)
+
+	// Roles. GetMemberRole and SetMemberRole return sql.ErrNoRows if the user is not a member.
+	GetMemberRole(ctx context.Context, groupID, userID string) (string, error)
+	// SetMemberRole switches a member between admin and member; it never touches the owner.
+	SetMemberRole(ctx context.Context, groupID, userID, role string) error
+	// TransferOwnership makes toUserID the owner and demotes the current owner to admin.
+	// It returns sql.ErrNoRows if toUserID is not a member.
+	TransferOwnership(ctx context.Context, groupID, toUserID string) error
+
+	// Itinerary. GetItinerary and DeleteItinerary return sql.ErrNoRows if the group has none.
+	GetItinerary(ctx context.Context, groupID string) (*Itinerary, error)
+	// SaveItinerary creates or replaces the group's itinerary with legs, which must already have ids.
+	SaveItinerary(ctx context.Context, groupID, userID string, legs []ItineraryLeg) (*Itinerary, error)
+	// AddItineraryLeg adds a validated leg, creating the itinerary if needed, and returns
+	// the updated itinerary. Returns ErrTooManyLegs when the itinerary is full.
+	AddItineraryLeg(ctx context.Context, groupID, userID string, leg ItineraryLeg) (*Itinerary, error)
+	// UpdateItineraryLeg replaces the leg with leg.ID. Returns sql.ErrNoRows if there is no such leg.
+	UpdateItineraryLeg(ctx context.Context, groupID, userID string, leg ItineraryLeg) (*Itinerary, error)
+	// DeleteItineraryLeg removes one leg. Returns sql.ErrNoRows if there is no such leg.
+	DeleteItineraryLeg(ctx context.Context, groupID, userID, legID string) (*Itinerary, error)
+	DeleteItinerary(ctx context.Context, groupID string) error
+
+	// Expenses. CreateExpense and CreateSettlement set the ID and timestamp.
+	// CreateSettlement returns ErrSettlementExceedsBalance if the payment is
+	// more than is outstanding between the two users.
+	// GetExpense and DeleteExpense return sql.ErrNoRows if the expense is not in the group.
+	CreateExpense(ctx context.Context, expense *Expense) error
+	GetExpense(ctx context.Context, groupID, expenseID string) (*Expense, error)
+	GetExpenses(ctx context.Context, groupID string) ([]Expense, error)
+	DeleteExpense(ctx context.Context, groupID, expenseID string) error
+	CreateSettlement(ctx context.Context, settlement *Settlement) error
+	GetSettlements(ctx context.Context, groupID string) ([]Settlement, error)
+
+	// Invites. CreateInvite sets the ID and timestamps; a direct invite to a user
+	// who already has an active one refreshes that invite instead.
+	// It returns ErrInviteeNotFound if the invited user does not exist.
+	CreateInvite(ctx context.Context, invite *Invite) error
+	// GetInvites returns the group's active, unexpired invites.
+	GetInvites(ctx context.Context, groupID string) ([]Invite, error)
+	// GetInviteByCode returns sql.ErrNoRows if no invite has the code.
+	GetInviteByCode(ctx context.Context, code string) (*InviteDetails, error)
+	// GetUserInvites returns the active direct invites addressed to userID.
+	GetUserInvites(ctx context.Context, userID string) ([]InviteDetails, error)
+	// UseInvite atomically claims one use of the invite, marking direct invites
+	// accepted. It returns ErrInviteUnavailable if the invite cannot be used.
+	UseInvite(ctx context.Context, inviteID string) error
+	// ReleaseInvite gives back a use claimed by UseInvite when the join failed.
+	// A direct invite is reopened only if it is still accepted.
+	ReleaseInvite(ctx context.Context, inviteID string) error
+	// CloseInvite sets an active invite of the group to revoked or declined.
+	// It returns sql.ErrNoRows if the group has no such active invite.
+	CloseInvite(ctx context.Context, groupID, inviteID, status string) error
+
+	// Waitlist. JoinWaitlist is a no-op if the user is already queued; it
+	// returns the user's 1-based position either way.
+	JoinWaitlist(ctx context.Context, groupID, userID string) (int, error)
+	// GetWaitlist returns the group's queue in order.
+	GetWaitlist(ctx context.Context, groupID string) ([]WaitlistEntry, error)
+	// LeaveWaitlist returns sql.ErrNoRows if the user is not queued.
+	LeaveWaitlist(ctx context.Context, groupID, userID string) error
+	// PromoteFromWaitlist fills the group's open spots from the head of its
+	// waitlist: users join directly, or are offered the spot for
+	// WaitlistOfferTTL if the group requires approval. Expired offers are dropped first.
+	PromoteFromWaitlist(ctx context.Context, groupID string) ([]Promotion, error)
+	// ClaimWaitlistOffer joins the group on a live offer.
+	// It returns ErrNoWaitlistOffer if the user holds none.
+	ClaimWaitlistOffer(ctx context.Context, groupID, userID string) error
+	// ExpireWaitlistOffers removes up to limit offers that expired at or
+	// before now and returns them.
+	ExpireWaitlistOffers(ctx context.Context, now time.Time, limit int) ([]WaitlistEntry, error)
+
+	// Ratings. CreateRating sets the ID and timestamp and returns
+	// ErrAlreadyRated if the rater has already rated the member in this group.
+	CreateRating(ctx context.Context, rating *Rating) error
+	// GetRatingsByRater returns the ratings raterID has given in the group.
+	GetRatingsByRater(ctx context.Context, groupID, raterID string) ([]Rating, error)
 }
 
 // UserWithInterests holds a user's profile data and interests for matching
@@ -68,6 +188,53 @@ func NewRepository(db *sql.DB) Repository {
 	return &PostgresRepository{db: db}
 }
 
+// routeColumns selects a group's origin, destination and transport mode from
+// travel_groups aliased as tg, in the order routeScan.dest expects.
+const routeColumns = `COALESCE(tg.origin_city, ''), tg.origin_lat, tg.origin_lng,
+		        COALESCE(tg.destination_city, ''), tg.destination_lat, tg.destination_lng,
+		        COALESCE(tg.transport_mode, '')`
+
+type routeScan struct {
+	originCity, destinationCity string
+	originLat, originLng        *float64
+	destinationLat              *float64
+	destinationLng              *float64
+	transportMode               string
+}
+
+func (rs *routeScan) dest() []any {
+	return []any{&rs.originCity, &rs.originLat, &rs.originLng,
+		&rs.destinationCity, &rs.destinationLat, &rs.destinationLng, &rs.transportMode}
+}
+
+func (rs *routeScan) apply(g *Group) {
+	if rs.originCity != "" {
+		g.Origin = &Place{City: rs.originCity, Lat: rs.originLat, Lng: rs.originLng}
+	}
+	if rs.destinationCity != "" {
+		g.Destination = &Place{City: rs.destinationCity, Lat: rs.destinationLat, Lng: rs.destinationLng}
+	}
+	g.TransportMode = rs.transportMode
+}
+
+// routeArgs returns the origin, destination and transport mode of g as query
+// arguments matching routeColumns.
+func routeArgs(g *Group) []any {
+	args := make([]any, 0, 7)
+	for _, p := range []*Place{g.Origin, g.Destination} {
+		if p == nil {
+			args = append(args, nil, nil, nil)
+			continue
+		}
+		args = append(args, p.City, p.Lat, p.Lng)
+	}
+	var mode *string
+	if g.TransportMode != "" {
+		mode = &g.TransportMode
+	}
+	return append(args, mode)
+}
+
 func (r *PostgresRepository) CreateGroup(ctx context.Context, group *Group) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -76,11 +243,12 @@ func (r *PostgresRepository) CreateGroup(ctx context.Context, group *Group) erro
 	defer tx.Rollback()
 
 	err = tx.QueryRowContext(ctx,
-		`INSERT INTO travel_groups (event_id, name, description, created_by, max_members, departure_date, meeting_point, requires_approval)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`INSERT INTO travel_groups (event_id, name, description, created_by, max_members, departure_date, meeting_point, requires_approval, gender_preference,
+		                            origin_city, origin_lat, origin_lng, destination_city, destination_lat, destination_lng, transport_mode)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		 RETURNING id`,
-		group.EventID, group.Name, group.Description, group.CreatedBy, group.MaxMembers,
-		group.DepartureDate, group.MeetingPoint, group.RequiresApproval,
+		append([]any{group.EventID, group.Name, group.Description, group.CreatedBy, group.MaxMembers,
+			group.DepartureDate, group.MeetingPoint, group.RequiresApproval, group.GenderPreference}, routeArgs(group)...)...,
 	).Scan(&group.ID)
 	if err != nil {
 		return err
@@ -100,15 +268,18 @@ func (r *PostgresRepository) CreateGroup(ctx context.Context, group *Group) erro
 
 func (r *PostgresRepository) GetGroup(ctx context.Context, groupID string) (*Group, error) {
 	var g Group
+	var route routeScan
 	err := r.db.QueryRowContext(ctx,
 		`SELECT id, event_id, name, COALESCE(description, ''), created_by, max_members, created_at,
-		        departure_date, COALESCE(meeting_point, ''), requires_approval
-		 FROM travel_groups WHERE id = $1`, groupID,
-	).Scan(&g.ID, &g.EventID, &g.Name, &g.Description, &g.CreatedBy, &g.MaxMembers, &g.CreatedAt,
-		&g.DepartureDate, &g.MeetingPoint, &g.RequiresApproval)
+		        departure_date, COALESCE(meeting_point, ''), requires_approval, gender_preference, status,
+		        `+routeColumns+`
+		 FROM travel_groups tg WHERE id = $1`, groupID,
+	).Scan(append([]any{&g.ID, &g.EventID, &g.Name, &g.Description, &g.CreatedBy, &g.MaxMembers, &g.CreatedAt,
+		&g.DepartureDate, &g.MeetingPoint, &g.RequiresApproval, &g.GenderPreference, &g.Status}, route.dest()...)...)
 	if err != nil {
 		return nil, err
 	}
+	route.apply(&g)
 	return &g, nil
 }
 
@@ -145,7 +316,9 @@ func (r *PostgresRepository) JoinGroup(ctx context.Context, groupID, userID stri
 	defer tx.Rollback()
 
 	_, err = tx.ExecContext(ctx,
-		`INSERT INTO group_members (group_id, user_id) VALUES ($1, $2)
+		`INSERT INTO group_members (group_id, user_id, role)
+		 VALUES ($1, $2, CASE WHEN EXISTS (SELECT 1 FROM group_members WHERE group_id = $1 AND role = 'owner')
+		                      THEN 'member' ELSE 'owner' END)
 		 ON CONFLICT DO NOTHING`,
 		groupID, userID)
 	if err != nil {
@@ -172,7 +345,7 @@ func (r *PostgresRepository) JoinGroup(ctx context.Context, groupID, userID stri
 // JoinGroupChecked performs an atomic capacity check + insert inside a transaction.
 // It locks the travel_groups row with SELECT FOR UPDATE, counts current members,
 // and only inserts if the group is not yet full.
-func (r *PostgresRepository) JoinGroupChecked(ctx context.Context, groupID, userID string) (bool, error) {
+func (r *PostgresRepository) JoinGroupChecked(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return false, err
@@ -182,26 +355,36 @@ func (r *PostgresRepository) JoinGroupChecked(ctx context.Context, groupID, user
 	// Retrieve group constraints
 	var maxMembers, memberCount int
 	var requiresApproval bool
+	var status string
 	err = tx.QueryRowContext(ctx,
-		`SELECT g.max_members, g.requires_approval,
-		        (SELECT COUNT(*) FROM group_members WHERE group_id = $1)
-		 FROM travel_groups g WHERE g.id = $1`, groupID,
-	).Scan(&maxMembers, &requiresApproval, &memberCount)
+		`SELECT g.max_members, g.requires_approval, g.status,
+		        (SELECT COUNT(*) FROM group_members WHERE group_id = $1) +
+		        (SELECT COUNT(*) FROM group_waitlist
+		         WHERE group_id = $1 AND user_id <> $2 AND status = 'offered' AND offer_expires_at > NOW())
+		 FROM travel_groups g WHERE g.id = $1 FOR UPDATE`, groupID, userID,
+	).Scan(&maxMembers, &requiresApproval, &status, &memberCount)
 
 	if err != nil {
 		return false, err
 	}
 
+	if status != StatusOpen {
+		return false, ErrGroupClosed
+	}
+
 	if memberCount >= maxMembers {
 		return false, ErrGroupFull
 	}
 
-	if requiresApproval {
-		// Insert into group_join_requests instead
+	if requiresApproval && !skipApproval {
+		// Insert into group_join_requests instead; asking again refreshes the request
 		_, err = tx.ExecContext(ctx,
-			`INSERT INTO group_join_requests (group_id, user_id, status) VALUES ($1, $2, 'pending')
-			 ON CONFLICT (group_id, user_id) DO UPDATE SET status = 'pending'`,
-			groupID, userID,
+			`INSERT INTO group_join_requests (group_id, user_id, status, message, requested_at, expires_at)
+			 VALUES ($1, $2, 'pending', $3, NOW(), $4)
+			 ON CONFLICT (group_id, user_id) DO UPDATE
+			 SET status = 'pending', message = EXCLUDED.message, requested_at = NOW(),
+			     expires_at = EXCLUDED.expires_at, resolved_at = NULL`,
+			groupID, userID, message, time.Now().Add(JoinRequestTTL),
 		)
 		if err != nil {
 			return false, err
@@ -220,6 +403,17 @@ func (r *PostgresRepository) JoinGroupChecked(ctx context.Context, groupID, user
 	if err != nil {
 		return false, err
 	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM group_waitlist WHERE group_id = $1 AND user_id = $2`, groupID, userID)
+	if err != nil {
+		return false, err
+	}
+	// A direct join (e.g. through an invite) settles any request still pending
+	_, err = tx.ExecContext(ctx,
+		`UPDATE group_join_requests SET status = 'accepted', resolved_at = NOW()
+		 WHERE group_id = $1 AND user_id = $2 AND status = 'pending'`, groupID, userID)
+	if err != nil {
+		return false, err
+	}
 
 	var threadID string
 	err = tx.QueryRowContext(ctx, `SELECT id FROM message_threads WHERE group_id = $1 AND type = 'group' LIMIT 1`, groupID).Scan(&threadID)
@@ -235,34 +429,41 @@ func (r *PostgresRepository) JoinGroupChecked(ctx context.Context, groupID, user
 
 func (r *PostgresRepository) CreateJoinRequest(ctx context.Context, groupID, userID string) error {
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO group_join_requests (group_id, user_id, status) VALUES ($1, $2, 'pending')
+		`INSERT INTO group_join_requests (group_id, user_id, status, expires_at) VALUES ($1, $2, 'pending', $3)
 		 ON CONFLICT (group_id, user_id) DO NOTHING`,
-		groupID, userID,
+		groupID, userID, time.Now().Add(JoinRequestTTL),
 	)
 	return err
 }
 
-func (r *PostgresRepository) GetJoinRequests(ctx context.Context, groupID string) ([]GroupMemberProfile, error) {
+func (r *PostgresRepository) GetJoinRequests(ctx context.Context, groupID string) ([]JoinRequest, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT r.user_id, p.full_name, p.college_name, COALESCE(p.profile_photo_url, ''), r.requested_at
+		`SELECT r.user_id, p.full_name, p.college_name, COALESCE(p.profile_photo_url, ''), r.message,
+		        r.requested_at, r.expires_at,
+		        COALESCE(t.ratings, 0), COALESCE(t.punctuality, 0), COALESCE(t.communication, 0)
 		 FROM group_join_requests r
 		 JOIN profiles p ON r.user_id = p.user_id
-		 WHERE r.group_id = $1 AND r.status = 'pending'
+		 LEFT JOIN user_trust_scores t ON t.user_id = r.user_id
+		 WHERE r.group_id = $1 AND r.status = 'pending' AND r.expires_at > NOW()
 		 ORDER BY r.requested_at DESC`, groupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var profiles []GroupMemberProfile
+	var requests []JoinRequest
 	for rows.Next() {
-		var p GroupMemberProfile
-		if err := rows.Scan(&p.UserID, &p.FullName, &p.CollegeName, &p.ProfilePhotoURL, &p.JoinedAt); err != nil {
+		var req JoinRequest
+		var ratings int
+		var punctuality, communication float64
+		if err := rows.Scan(&req.UserID, &req.FullName, &req.CollegeName, &req.ProfilePhotoURL, &req.Message,
+			&req.RequestedAt, &req.ExpiresAt, &ratings, &punctuality, &communication); err != nil {
 			return nil, err
 		}
-		profiles = append(profiles, p)
+		req.TrustScore = NewTrustScore(ratings, punctuality, communication)
+		requests = append(requests, req)
 	}
-	return profiles, nil
+	return requests, rows.Err()
 }
 
 func (r *PostgresRepository) AcceptJoinRequest(ctx context.Context, groupID, userID string) error {
@@ -272,14 +473,36 @@ func (r *PostgresRepository) AcceptJoinRequest(ctx context.Context, groupID, use
 	}
 	defer tx.Rollback()
 
+	// Lock the group so concurrent accepts and joins cannot overfill it
+	var maxMembers, memberCount int
+	var status string
+	err = tx.QueryRowContext(ctx,
+		`SELECT g.max_members, g.status,
+		        (SELECT COUNT(*) FROM group_members WHERE group_id = $1) +
+		        (SELECT COUNT(*) FROM group_waitlist
+		         WHERE group_id = $1 AND user_id <> $2 AND status = 'offered' AND offer_expires_at > NOW())
+		 FROM travel_groups g WHERE g.id = $1 FOR UPDATE`, groupID, userID,
+	).Scan(&maxMembers, &status, &memberCount)
+	if err != nil {
+		return err
+	}
+	if status != StatusOpen {
+		return ErrGroupClosed
+	}
+	if memberCount >= maxMembers {
+		return ErrGroupFull
+	}
+
 	// Update status
-	res, err := tx.ExecContext(ctx, `UPDATE group_join_requests SET status = 'accepted' WHERE group_id = $1 AND user_id = $2`, groupID, userID)
+	res, err := tx.ExecContext(ctx,
+		`UPDATE group_join_requests SET status = 'accepted', resolved_at = NOW()
+		 WHERE group_id = $1 AND user_id = $2 AND status = 'pending' AND expires_at > NOW()`, groupID, userID)
 	if err != nil {
 		return err
 	}
 	rowsAffected, _ := res.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.New("request not found")
+		return sql.ErrNoRows
 	}
 
 	// Insert into members
@@ -287,6 +510,10 @@ func (r *PostgresRepository) AcceptJoinRequest(ctx context.Context, groupID, use
 	if err != nil {
 		return err
 	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM group_waitlist WHERE group_id = $1 AND user_id = $2`, groupID, userID)
+	if err != nil {
+		return err
+	}
 
 	var threadID string
 	err = tx.QueryRowContext(ctx, `SELECT id FROM message_threads WHERE group_id = $1 AND type = 'group' LIMIT 1`, groupID).Scan(&threadID)
@@ -301,8 +528,82 @@ func (r *PostgresRepository) AcceptJoinRequest(ctx context.Context, groupID, use
 }
 
 func (r *PostgresRepository) DeclineJoinRequest(ctx context.Context, groupID, userID string) error {
-	_, err := r.db.ExecContext(ctx, `UPDATE group_join_requests SET status = 'declined' WHERE group_id = $1 AND user_id = $2`, groupID, userID)
-	return err
+	return r.resolveJoinRequest(ctx, groupID, userID, RequestDeclined)
+}
+
+func (r *PostgresRepository) CancelJoinRequest(ctx context.Context, groupID, userID string) error {
+	return r.resolveJoinRequest(ctx, groupID, userID, RequestCancelled)
+}
+
+// resolveJoinRequest moves a live pending request to status.
+func (r *PostgresRepository) resolveJoinRequest(ctx context.Context, groupID, userID, status string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE group_join_requests SET status = $3, resolved_at = NOW()
+		 WHERE group_id = $1 AND user_id = $2 AND status = 'pending' AND expires_at > NOW()`,
+		groupID, userID, status)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetUserJoinRequests reports pending requests past their expiry as expired
+// even before the expirer has caught up with them.
+func (r *PostgresRepository) GetUserJoinRequests(ctx context.Context, userID string) ([]OutgoingJoinRequest, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT r.group_id, tg.name, tg.event_id, r.user_id,
+		        CASE WHEN r.status = 'pending' AND r.expires_at <= NOW() THEN 'expired' ELSE r.status END,
+		        r.message, r.requested_at, r.expires_at, r.resolved_at
+		 FROM group_join_requests r
+		 JOIN travel_groups tg ON tg.id = r.group_id
+		 WHERE r.user_id = $1
+		 ORDER BY r.requested_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanOutgoingJoinRequests(rows)
+}
+
+// ExpireJoinRequests updates the oldest expired requests in one statement.
+// SKIP LOCKED lets several server instances expire requests without blocking each other.
+func (r *PostgresRepository) ExpireJoinRequests(ctx context.Context, now time.Time, limit int) ([]OutgoingJoinRequest, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH expired AS (
+			UPDATE group_join_requests SET status = 'expired', resolved_at = $1
+			WHERE (group_id, user_id) IN (
+				SELECT group_id, user_id FROM group_join_requests
+				WHERE status = 'pending' AND expires_at <= $1
+				ORDER BY expires_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING group_id, user_id, status, message, requested_at, expires_at, resolved_at
+		)
+		SELECT e.group_id, tg.name, tg.event_id, e.user_id, e.status, e.message, e.requested_at, e.expires_at, e.resolved_at
+		FROM expired e
+		JOIN travel_groups tg ON tg.id = e.group_id`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanOutgoingJoinRequests(rows)
+}
+
+func scanOutgoingJoinRequests(rows *sql.Rows) ([]OutgoingJoinRequest, error) {
+	var requests []OutgoingJoinRequest
+	for rows.Next() {
+		var req OutgoingJoinRequest
+		if err := rows.Scan(&req.GroupID, &req.GroupName, &req.EventID, &req.UserID, &req.Status, &req.Message,
+			&req.RequestedAt, &req.ExpiresAt, &req.ResolvedAt); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, rows.Err()
 }
 
 func (r *PostgresRepository) GetMemberCount(ctx context.Context, groupID string) (int, error) {
@@ -343,10 +644,11 @@ func (r *PostgresRepository) GetGroupsWithCountsForEvent(ctx context.Context, ev
 	rows, err := r.db.QueryContext(ctx,
 		`SELECT tg.id, tg.event_id, tg.name, COALESCE(tg.description, ''),
 		        tg.created_by, tg.max_members, tg.created_at,
-		        tg.departure_date, COALESCE(tg.meeting_point, ''),
-		        (SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = tg.id) AS member_count
+		        tg.departure_date, COALESCE(tg.meeting_point, ''), tg.requires_approval, tg.gender_preference, tg.status,
+		        (SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = tg.id) AS member_count,
+		        `+routeColumns+`
 		 FROM travel_groups tg
-		 WHERE tg.event_id = $1
+		 WHERE tg.event_id = $1 AND tg.status IN ('open', 'locked')
 		 ORDER BY tg.created_at DESC`, eventID)
 	if err != nil {
 		return nil, err
@@ -356,13 +658,15 @@ func (r *PostgresRepository) GetGroupsWithCountsForEvent(ctx context.Context, ev
 	var groups []GroupWithDetails
 	for rows.Next() {
 		var g GroupWithDetails
-		if err := rows.Scan(
+		var route routeScan
+		if err := rows.Scan(append([]any{
 			&g.ID, &g.EventID, &g.Name, &g.Description,
 			&g.CreatedBy, &g.MaxMembers, &g.CreatedAt,
-			&g.DepartureDate, &g.MeetingPoint, &g.MemberCount,
-		); err != nil {
+			&g.DepartureDate, &g.MeetingPoint, &g.RequiresApproval, &g.GenderPreference, &g.Status, &g.MemberCount,
+		}, route.dest()...)...); err != nil {
 			return nil, err
 		}
+		route.apply(&g.Group)
 		groups = append(groups, g)
 	}
 	return groups, nil
@@ -494,9 +798,11 @@ func (r *PostgresRepository) GetUsersForEvent(ctx context.Context, eventID, excl
 // GetGroupMembers returns profile details for all members of a group
 func (r *PostgresRepository) GetGroupMembers(ctx context.Context, groupID string) ([]GroupMemberProfile, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT gm.user_id, p.full_name, p.college_name, COALESCE(p.profile_photo_url, ''), gm.joined_at
+		`SELECT gm.user_id, p.full_name, p.college_name, COALESCE(p.profile_photo_url, ''), gm.role, gm.joined_at,
+		        COALESCE(t.ratings, 0), COALESCE(t.punctuality, 0), COALESCE(t.communication, 0)
 		 FROM group_members gm
 		 JOIN profiles p ON gm.user_id = p.user_id
+		 LEFT JOIN user_trust_scores t ON t.user_id = gm.user_id
 		 WHERE gm.group_id = $1
 		 ORDER BY gm.joined_at ASC`, groupID)
 	if err != nil {
@@ -507,26 +813,128 @@ func (r *PostgresRepository) GetGroupMembers(ctx context.Context, groupID string
 	var members []GroupMemberProfile
 	for rows.Next() {
 		var m GroupMemberProfile
-		if err := rows.Scan(&m.UserID, &m.FullName, &m.CollegeName, &m.ProfilePhotoURL, &m.JoinedAt); err != nil {
+		var ratings int
+		var punctuality, communication float64
+		if err := rows.Scan(&m.UserID, &m.FullName, &m.CollegeName, &m.ProfilePhotoURL, &m.Role, &m.JoinedAt,
+			&ratings, &punctuality, &communication); err != nil {
 			return nil, err
 		}
+		m.TrustScore = NewTrustScore(ratings, punctuality, communication)
 		members = append(members, m)
 	}
 	return members, nil
 }
 
-// UpdateGroup updates the name, description, departure_date, and meeting_point of a group
-func (r *PostgresRepository) UpdateGroup(ctx context.Context, groupID, name, description, meetingPoint string, departureDate *time.Time) error {
+// UpdateGroup updates the name, description, departure_date, meeting_point, gender_preference and route of a group
+func (r *PostgresRepository) UpdateGroup(ctx context.Context, group *Group) error {
 	_, err := r.db.ExecContext(ctx,
-		`UPDATE travel_groups SET name = $1, description = $2, departure_date = $3, meeting_point = $4 WHERE id = $5`,
-		name, description, departureDate, meetingPoint, groupID)
+		`UPDATE travel_groups SET name = $1, description = $2, departure_date = $3, meeting_point = $4, gender_preference = $5,
+		        origin_city = $6, origin_lat = $7, origin_lng = $8,
+		        destination_city = $9, destination_lat = $10, destination_lng = $11, transport_mode = $12
+		 WHERE id = $13`,
+		append(append([]any{group.Name, group.Description, group.DepartureDate, group.MeetingPoint, group.GenderPreference},
+			routeArgs(group)...), group.ID)...)
 	return err
 }
 
-// DeleteGroup removes a group and all its members (cascade via FK or manual)
+// DeleteGroup removes a group and all its members (cascade via FK or manual),
+// along with its chat thread, whose link to the group would otherwise only be
+// nulled.
 func (r *PostgresRepository) DeleteGroup(ctx context.Context, groupID string) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM travel_groups WHERE id = $1`, groupID)
-	return err
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM message_threads WHERE group_id = $1`, groupID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM travel_groups WHERE id = $1`, groupID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) SetGroupStatus(ctx context.Context, groupID, from, to string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE travel_groups SET status = $3, status_changed_at = NOW() WHERE id = $1 AND status = $2`,
+		groupID, from, to)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// AdvanceGroupLifecycle runs each transition as one statement in a single
+// transaction, so a group can move several steps in one pass.
+func (r *PostgresRepository) AdvanceGroupLifecycle(ctx context.Context, now time.Time, archiveAfter time.Duration) ([]StatusChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	steps := []struct {
+		status string
+		query  string
+		args   []any
+	}{
+		{StatusTraveling, `
+			UPDATE travel_groups tg SET status = 'traveling', status_changed_at = $1
+			FROM events e
+			WHERE e.id = tg.event_id AND tg.status IN ('open', 'locked')
+			  AND COALESCE(tg.departure_date, e.start_date) <= $1
+			RETURNING tg.id`, []any{now}},
+		{StatusCompleted, `
+			UPDATE travel_groups tg SET status = 'completed', status_changed_at = $1
+			FROM events e
+			WHERE e.id = tg.event_id AND tg.status IN ('open', 'locked', 'traveling')
+			  AND COALESCE(e.end_date, e.start_date) + INTERVAL '1 day' <= $1
+			RETURNING tg.id`, []any{now}},
+		{StatusArchived, `
+			UPDATE travel_groups SET status = 'archived', status_changed_at = $1
+			WHERE status = 'completed' AND status_changed_at <= $2
+			RETURNING id`, []any{now, now.Add(-archiveAfter)}},
+	}
+
+	var changes []StatusChange
+	for _, step := range steps {
+		rows, err := tx.QueryContext(ctx, step.query, step.args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			c := StatusChange{Status: step.status}
+			if err := rows.Scan(&c.GroupID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			changes = append(changes, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE message_threads mt SET read_only = true
+		FROM travel_groups tg
+		WHERE tg.id = mt.group_id AND tg.status = 'archived' AND NOT mt.read_only`); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM group_waitlist w
+		USING travel_groups tg
+		WHERE tg.id = w.group_id AND tg.status NOT IN ('open', 'locked')`); err != nil {
+		return nil, err
+	}
+
+	return changes, tx.Commit()
 }
 
 // RemoveMember removes a single user from a group
@@ -537,12 +945,30 @@ func (r *PostgresRepository) RemoveMember(ctx context.Context, groupID, userID s
 	}
 	defer tx.Rollback()
 
-	_, err = tx.ExecContext(ctx,
-		`DELETE FROM group_members WHERE group_id = $1 AND user_id = $2`,
-		groupID, userID)
-	if err != nil {
+	var role string
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM group_members WHERE group_id = $1 AND user_id = $2 RETURNING role`,
+		groupID, userID).Scan(&role)
+	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
+	if role == RoleOwner {
+		var newOwner string
+		err = tx.QueryRowContext(ctx,
+			`UPDATE group_members SET role = 'owner'
+			 WHERE group_id = $1 AND user_id = (
+			     SELECT user_id FROM group_members WHERE group_id = $1 ORDER BY joined_at, user_id LIMIT 1
+			 )
+			 RETURNING user_id`, groupID).Scan(&newOwner)
+		switch {
+		case err == nil:
+			if err := setGroupOwner(ctx, tx, groupID, newOwner); err != nil {
+				return err
+			}
+		case err != sql.ErrNoRows:
+			return err
+		}
+	}
 
 	var threadID string
 	err = tx.QueryRowContext(ctx, `SELECT id FROM message_threads WHERE group_id = $1 AND type = 'group' LIMIT 1`, groupID).Scan(&threadID)
@@ -560,6 +986,63 @@ func (r *PostgresRepository) RemoveMember(ctx context.Context, groupID, userID s
 	return tx.Commit()
 }
 
+// GetMemberRole returns the user's role in the group.
+func (r *PostgresRepository) GetMemberRole(ctx context.Context, groupID, userID string) (string, error) {
+	var role string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT role FROM group_members WHERE group_id = $1 AND user_id = $2`,
+		groupID, userID).Scan(&role)
+	return role, err
+}
+
+// SetMemberRole promotes a member to admin or demotes an admin to member.
+func (r *PostgresRepository) SetMemberRole(ctx context.Context, groupID, userID, role string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE group_members SET role = $3 WHERE group_id = $1 AND user_id = $2 AND role <> 'owner'`,
+		groupID, userID, role)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TransferOwnership hands the group to another member in one transaction.
+func (r *PostgresRepository) TransferOwnership(ctx context.Context, groupID, toUserID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Demote first so the one-owner-per-group index never sees two owners
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE group_members SET role = 'admin' WHERE group_id = $1 AND role = 'owner'`, groupID); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE group_members SET role = 'owner' WHERE group_id = $1 AND user_id = $2`, groupID, toUserID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if err := setGroupOwner(ctx, tx, groupID, toUserID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setGroupOwner keeps travel_groups.created_by pointing at the current owner,
+// since reports and the group's JSON treat it as the owner.
+func setGroupOwner(ctx context.Context, tx *sql.Tx, groupID, ownerID string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE travel_groups SET created_by = $2 WHERE id = $1`, groupID, ownerID)
+	return err
+}
+
 // IsGroupMember returns true if the user is currently a member of the group
 func (r *PostgresRepository) IsGroupMember(ctx context.Context, groupID, userID string) (bool, error) {
 	var count int
@@ -591,12 +1074,122 @@ func (r *PostgresRepository) GetUserInterests(ctx context.Context, userID string
 	return interests, nil
 }
 
+// GetMatchProfile returns the user's college, home city, gender and interests.
+func (r *PostgresRepository) GetMatchProfile(ctx context.Context, userID string) (MatchProfile, error) {
+	p := MatchProfile{UserID: userID}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(college_name, ''), COALESCE(home_city, ''), COALESCE(gender, '')
+		 FROM profiles WHERE user_id = $1`, userID,
+	).Scan(&p.College, &p.HomeCity, &p.Gender)
+	if err != nil && err != sql.ErrNoRows {
+		return p, err
+	}
+	p.Interests, err = r.GetUserInterests(ctx, userID)
+	return p, err
+}
+
+// GetMatchProfiles loads the match profiles of userIDs in a single query.
+func (r *PostgresRepository) GetMatchProfiles(ctx context.Context, userIDs []string) (map[string]MatchProfile, error) {
+	result := make(map[string]MatchProfile, len(userIDs))
+	for _, id := range userIDs {
+		result[id] = MatchProfile{UserID: id}
+	}
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT u.id, COALESCE(p.college_name, ''), COALESCE(p.home_city, ''), COALESCE(p.gender, ''),
+		        COALESCE(i.name, '')
+		 FROM users u
+		 LEFT JOIN profiles p ON u.id = p.user_id
+		 LEFT JOIN user_interests ui ON u.id = ui.user_id
+		 LEFT JOIN interests i ON ui.interest_id = i.id
+		 WHERE u.id = ANY($1::uuid[])`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p MatchProfile
+		var interest string
+		if err := rows.Scan(&p.UserID, &p.College, &p.HomeCity, &p.Gender, &interest); err != nil {
+			return nil, err
+		}
+		p.Interests = result[p.UserID].Interests
+		if interest != "" {
+			p.Interests = append(p.Interests, interest)
+		}
+		result[p.UserID] = p
+	}
+	return result, rows.Err()
+}
+
+// GetGroupMemberProfilesForEvent loads member profiles and interests for every group
+// in an event in a single query.
+func (r *PostgresRepository) GetGroupMemberProfilesForEvent(ctx context.Context, eventID string) (map[string][]MatchProfile, error) {
+	return r.memberProfiles(ctx, `tg.event_id = $1`, eventID)
+}
+
+// GetGroupMemberProfiles loads the profiles and interests of one group's members.
+func (r *PostgresRepository) GetGroupMemberProfiles(ctx context.Context, groupID string) ([]MatchProfile, error) {
+	profiles, err := r.memberProfiles(ctx, `gm.group_id = $1`, groupID)
+	return profiles[groupID], err
+}
+
+// memberProfiles loads the match profiles of the members of every group that
+// matches filter, keyed by group ID.
+func (r *PostgresRepository) memberProfiles(ctx context.Context, filter string, arg any) (map[string][]MatchProfile, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT gm.group_id, gm.user_id,
+		        COALESCE(p.college_name, ''), COALESCE(p.home_city, ''), COALESCE(p.gender, ''),
+		        COALESCE(t.ratings, 0), COALESCE(t.punctuality, 0), COALESCE(t.communication, 0),
+		        COALESCE(i.name, '')
+		 FROM group_members gm
+		 JOIN travel_groups tg ON gm.group_id = tg.id
+		 LEFT JOIN profiles p ON gm.user_id = p.user_id
+		 LEFT JOIN user_trust_scores t ON gm.user_id = t.user_id
+		 LEFT JOIN user_interests ui ON gm.user_id = ui.user_id
+		 LEFT JOIN interests i ON ui.interest_id = i.id
+		 WHERE `+filter+`
+		 ORDER BY gm.group_id, gm.user_id`, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]MatchProfile)
+	for rows.Next() {
+		var groupID, interest string
+		var p MatchProfile
+		var ratings int
+		var punctuality, communication float64
+		if err := rows.Scan(&groupID, &p.UserID, &p.College, &p.HomeCity, &p.Gender,
+			&ratings, &punctuality, &communication, &interest); err != nil {
+			return nil, err
+		}
+		p.Trust = NewTrustScore(ratings, punctuality, communication)
+		// Rows are ordered by member, so a member's interests arrive together
+		members := result[groupID]
+		if n := len(members); n == 0 || members[n-1].UserID != p.UserID {
+			members = append(members, p)
+		}
+		if interest != "" {
+			members[len(members)-1].Interests = append(members[len(members)-1].Interests, interest)
+		}
+		result[groupID] = members
+	}
+	return result, rows.Err()
+}
+
 // GetUserGroups returns all groups the user is currently a member of, with live member counts.
 func (r *PostgresRepository) GetUserGroups(ctx context.Context, userID string) ([]GroupWithDetails, error) {
 	rows, err := r.db.QueryContext(ctx,
 		`SELECT tg.id, tg.event_id, tg.name, COALESCE(tg.description, ''), tg.created_by, tg.max_members, tg.created_at,
-		        tg.departure_date, COALESCE(tg.meeting_point, ''),
-		        (SELECT COUNT(*) FROM group_members gm2 WHERE gm2.group_id = tg.id) AS member_count
+		        tg.departure_date, COALESCE(tg.meeting_point, ''), tg.requires_approval, tg.gender_preference, tg.status,
+		        (SELECT COUNT(*) FROM group_members gm2 WHERE gm2.group_id = tg.id) AS member_count,
+		        `+routeColumns+`
 		 FROM group_members gm
 		 JOIN travel_groups tg ON gm.group_id = tg.id
 		 WHERE gm.user_id = $1
@@ -609,30 +1202,94 @@ func (r *PostgresRepository) GetUserGroups(ctx context.Context, userID string) (
 	var groups []GroupWithDetails
 	for rows.Next() {
 		var g GroupWithDetails
-		if err := rows.Scan(
+		var route routeScan
+		if err := rows.Scan(append([]any{
 			&g.ID, &g.EventID, &g.Name, &g.Description,
 			&g.CreatedBy, &g.MaxMembers, &g.CreatedAt,
-			&g.DepartureDate, &g.MeetingPoint, &g.MemberCount,
-		); err != nil {
+			&g.DepartureDate, &g.MeetingPoint, &g.RequiresApproval, &g.GenderPreference, &g.Status, &g.MemberCount,
+		}, route.dest()...)...); err != nil {
 			return nil, err
 		}
+		route.apply(&g.Group)
 		g.IsJoined = true // the user is a member of every group returned by this query
 		groups = append(groups, g)
 	}
 	return groups, nil
 }
 
-// GetAllGroups returns all travel groups with live member counts and a flag indicating
-// whether the given user is already a member.
-func (r *PostgresRepository) GetAllGroups(ctx context.Context, userID string) ([]GroupWithDetails, error) {
+// SearchGroups builds its WHERE clause from the filters that are set. The
+// newest and departure orders are served by the keyset indexes from migration
+// 000042, and text queries by the search_vector GIN index.
+func (r *PostgresRepository) SearchGroups(ctx context.Context, userID string, search GroupSearch) ([]GroupWithDetails, error) {
+	args := []any{userID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"tg.status NOT IN ('completed', 'archived')"}
+	if search.EventID != "" {
+		where = append(where, "tg.event_id = "+arg(search.EventID))
+	}
+	if search.DepartureFrom != nil {
+		where = append(where, "tg.departure_date >= "+arg(*search.DepartureFrom))
+	}
+	if search.DepartureTo != nil {
+		where = append(where, "tg.departure_date < "+arg(search.DepartureTo.AddDate(0, 0, 1)))
+	}
+	if search.MinOpenSeats > 0 {
+		where = append(where, "tg.max_members - mc.member_count >= "+arg(search.MinOpenSeats))
+	}
+	if search.RequiresApproval != nil {
+		where = append(where, "tg.requires_approval = "+arg(*search.RequiresApproval))
+	}
+	if search.College != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM group_members gm3
+		        JOIN profiles p ON p.user_id = gm3.user_id
+		        WHERE gm3.group_id = tg.id AND LOWER(p.college_name) = LOWER(`+arg(search.College)+`))`)
+	}
+	if search.Origin != nil && !search.Origin.hasCoords() {
+		where = append(where, "LOWER(tg.origin_city) = LOWER("+arg(search.Origin.Place.City)+")")
+	}
+	if search.Query != "" {
+		where = append(where, "tg.search_vector @@ websearch_to_tsquery('simple', "+arg(search.Query)+")")
+	}
+
+	orderBy := "tg.created_at DESC, tg.id DESC"
+	limit := ""
+	if search.InDatabase() {
+		if search.Sort == SortDeparture {
+			orderBy = "COALESCE(tg.departure_date, 'infinity'::timestamp) ASC, tg.id ASC"
+		}
+		if c := search.After; c != nil {
+			switch c.Sort {
+			case SortNewest:
+				where = append(where, "(tg.created_at, tg.id) < ("+arg(c.Key)+"::timestamp, "+arg(c.ID)+"::uuid)")
+			case SortDeparture:
+				key := c.Key
+				if key == "" {
+					key = "infinity"
+				}
+				where = append(where, "(COALESCE(tg.departure_date, 'infinity'::timestamp), tg.id) > ("+arg(key)+"::timestamp, "+arg(c.ID)+"::uuid)")
+			}
+		}
+		if search.Limit > 0 {
+			limit = "LIMIT " + arg(search.Limit+1)
+		}
+	}
+
 	rows, err := r.db.QueryContext(ctx,
 		`SELECT tg.id, tg.event_id, tg.name, COALESCE(tg.description, ''),
 		        tg.created_by, tg.max_members, tg.created_at,
-		        tg.departure_date, COALESCE(tg.meeting_point, ''),
-		        (SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = tg.id) AS member_count,
-		        EXISTS (SELECT 1 FROM group_members gm2 WHERE gm2.group_id = tg.id AND gm2.user_id = $1) AS is_joined
+		        tg.departure_date, COALESCE(tg.meeting_point, ''), tg.requires_approval, tg.gender_preference, tg.status,
+		        mc.member_count,
+		        EXISTS (SELECT 1 FROM group_members gm2 WHERE gm2.group_id = tg.id AND gm2.user_id = $1) AS is_joined,
+		        `+routeColumns+`
 		 FROM travel_groups tg
-		 ORDER BY tg.created_at DESC`, userID)
+		 CROSS JOIN LATERAL (SELECT COUNT(*) AS member_count FROM group_members gm WHERE gm.group_id = tg.id) mc
+		 WHERE `+strings.Join(where, " AND ")+`
+		 ORDER BY `+orderBy+`
+		 `+limit, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -641,14 +1298,812 @@ func (r *PostgresRepository) GetAllGroups(ctx context.Context, userID string) ([
 	var groups []GroupWithDetails
 	for rows.Next() {
 		var g GroupWithDetails
-		if err := rows.Scan(
+		var route routeScan
+		if err := rows.Scan(append([]any{
 			&g.ID, &g.EventID, &g.Name, &g.Description,
 			&g.CreatedBy, &g.MaxMembers, &g.CreatedAt,
-			&g.DepartureDate, &g.MeetingPoint, &g.MemberCount, &g.IsJoined,
-		); err != nil {
+			&g.DepartureDate, &g.MeetingPoint, &g.RequiresApproval, &g.GenderPreference, &g.Status, &g.MemberCount, &g.IsJoined,
+		}, route.dest()...)...); err != nil {
 			return nil, err
 		}
+		route.apply(&g.Group)
 		groups = append(groups, g)
 	}
-	return groups, nil
+	return groups, rows.Err()
+}
+
+// GetItinerary returns the group's itinerary with its legs in departure order.
+func (r *PostgresRepository) GetItinerary(ctx context.Context, groupID string) (*Itinerary, error) {
+	return getItinerary(ctx, r.db, groupID)
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func getItinerary(ctx context.Context, q querier, groupID string) (*Itinerary, error) {
+	it := Itinerary{GroupID: groupID, Legs: []ItineraryLeg{}}
+	var updatedBy sql.NullString
+	err := q.QueryRowContext(ctx,
+		`SELECT updated_by, updated_at FROM group_itineraries WHERE group_id = $1`, groupID,
+	).Scan(&updatedBy, &it.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	it.UpdatedBy = updatedBy.String
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, COALESCE(mode, ''), COALESCE(carrier, ''), COALESCE(number, ''),
+		        departure_station, departure_time, arrival_station, arrival_time,
+		        COALESCE(booking_reference, '')
+		 FROM group_itinerary_legs
+		 WHERE group_id = $1
+		 ORDER BY departure_time, id`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l ItineraryLeg
+		if err := rows.Scan(&l.ID, &l.Mode, &l.Carrier, &l.Number,
+			&l.DepartureStation, &l.DepartureTime, &l.ArrivalStation, &l.ArrivalTime,
+			&l.BookingReference); err != nil {
+			return nil, err
+		}
+		it.Legs = append(it.Legs, l)
+	}
+	return &it, rows.Err()
+}
+
+// SaveItinerary upserts the itinerary row and replaces its legs in one transaction.
+// The upsert locks the itinerary row, so leg edits wait until the new legs are in.
+func (r *PostgresRepository) SaveItinerary(ctx context.Context, groupID, userID string, legs []ItineraryLeg) (*Itinerary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	it := Itinerary{GroupID: groupID, Legs: legs, UpdatedBy: userID}
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO group_itineraries (group_id, updated_by) VALUES ($1, $2)
+		 ON CONFLICT (group_id) DO UPDATE SET updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		 RETURNING updated_at`,
+		groupID, userID,
+	).Scan(&it.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM group_itinerary_legs WHERE group_id = $1`, groupID); err != nil {
+		return nil, err
+	}
+	for _, l := range legs {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO group_itinerary_legs (id, group_id, mode, carrier, number,
+			                                   departure_station, departure_time, arrival_station, arrival_time, booking_reference)
+			 VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9, NULLIF($10, ''))`,
+			l.ID, groupID, l.Mode, l.Carrier, l.Number,
+			l.DepartureStation, l.DepartureTime, l.ArrivalStation, l.ArrivalTime, l.BookingReference,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
+
+// AddItineraryLeg inserts one leg. The itinerary row is created or locked first
+// so concurrent edits of the same itinerary run one at a time.
+func (r *PostgresRepository) AddItineraryLeg(ctx context.Context, groupID, userID string, leg ItineraryLeg) (*Itinerary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO group_itineraries (group_id, updated_by) VALUES ($1, $2)
+		 ON CONFLICT (group_id) DO UPDATE SET updated_by = EXCLUDED.updated_by, updated_at = NOW()`,
+		groupID, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM group_itinerary_legs WHERE group_id = $1`, groupID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count >= MaxItineraryLegs {
+		return nil, ErrTooManyLegs
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO group_itinerary_legs (id, group_id, mode, carrier, number,
+		                                   departure_station, departure_time, arrival_station, arrival_time, booking_reference)
+		 VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9, NULLIF($10, ''))`,
+		leg.ID, groupID, leg.Mode, leg.Carrier, leg.Number,
+		leg.DepartureStation, leg.DepartureTime, leg.ArrivalStation, leg.ArrivalTime, leg.BookingReference,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return commitItinerary(ctx, tx, groupID)
+}
+
+// UpdateItineraryLeg replaces one leg while holding the itinerary row lock.
+func (r *PostgresRepository) UpdateItineraryLeg(ctx context.Context, groupID, userID string, leg ItineraryLeg) (*Itinerary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockItinerary(ctx, tx, groupID, userID); err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE group_itinerary_legs SET
+			mode = NULLIF($3, ''), carrier = NULLIF($4, ''), number = NULLIF($5, ''),
+			departure_station = $6, departure_time = $7, arrival_station = $8, arrival_time = $9,
+			booking_reference = NULLIF($10, '')
+		 WHERE id = $1 AND group_id = $2`,
+		leg.ID, groupID, leg.Mode, leg.Carrier, leg.Number,
+		leg.DepartureStation, leg.DepartureTime, leg.ArrivalStation, leg.ArrivalTime, leg.BookingReference,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return commitItinerary(ctx, tx, groupID)
+}
+
+// DeleteItineraryLeg removes one leg while holding the itinerary row lock.
+func (r *PostgresRepository) DeleteItineraryLeg(ctx context.Context, groupID, userID, legID string) (*Itinerary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockItinerary(ctx, tx, groupID, userID); err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM group_itinerary_legs WHERE id = $1 AND group_id = $2`, legID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return commitItinerary(ctx, tx, groupID)
+}
+
+// lockItinerary locks the group's itinerary row and records who is editing it.
+// Returns sql.ErrNoRows if the group has no itinerary.
+func lockItinerary(ctx context.Context, tx *sql.Tx, groupID, userID string) error {
+	var locked string
+	return tx.QueryRowContext(ctx,
+		`UPDATE group_itineraries SET updated_by = $2, updated_at = NOW() WHERE group_id = $1 RETURNING group_id`,
+		groupID, userID,
+	).Scan(&locked)
+}
+
+// commitItinerary reads the itinerary as edited in tx and commits.
+func commitItinerary(ctx context.Context, tx *sql.Tx, groupID string) (*Itinerary, error) {
+	it, err := getItinerary(ctx, tx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// DeleteItinerary removes the group's itinerary and, by cascade, its legs.
+func (r *PostgresRepository) DeleteItinerary(ctx context.Context, groupID string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM group_itineraries WHERE group_id = $1`, groupID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreateExpense inserts the expense and its shares in one transaction.
+func (r *PostgresRepository) CreateExpense(ctx context.Context, expense *Expense) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO group_expenses (group_id, description, amount, currency, paid_by, split_type, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING id, created_at`,
+		expense.GroupID, expense.Description, expense.Amount, expense.Currency,
+		expense.PaidBy, expense.SplitType, expense.CreatedBy,
+	).Scan(&expense.ID, &expense.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range expense.Shares {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO group_expense_shares (expense_id, user_id, amount, percent) VALUES ($1, $2, $3, $4)`,
+			expense.ID, s.UserID, s.Amount, s.Percent,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetExpense returns one expense of the group with its shares.
+func (r *PostgresRepository) GetExpense(ctx context.Context, groupID, expenseID string) (*Expense, error) {
+	expenses, err := queryExpenses(ctx, r.db, `AND e.id = $2`, groupID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(expenses) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &expenses[0], nil
+}
+
+// GetExpenses returns every expense of the group with its shares, newest first.
+func (r *PostgresRepository) GetExpenses(ctx context.Context, groupID string) ([]Expense, error) {
+	return queryExpenses(ctx, r.db, ``, groupID)
+}
+
+// queryExpenses loads the group's expenses matching filter and then their shares.
+func queryExpenses(ctx context.Context, q querier, filter string, args ...any) ([]Expense, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT e.id, e.group_id, e.description, e.amount, e.currency, e.paid_by, e.split_type,
+		        COALESCE(e.created_by::text, ''), e.created_at
+		 FROM group_expenses e
+		 WHERE e.group_id = $1 `+filter+`
+		 ORDER BY e.created_at DESC, e.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	expenses := []Expense{}
+	index := make(map[string]int)
+	for rows.Next() {
+		e := Expense{Shares: []ExpenseShare{}}
+		if err := rows.Scan(&e.ID, &e.GroupID, &e.Description, &e.Amount, &e.Currency,
+			&e.PaidBy, &e.SplitType, &e.CreatedBy, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		index[e.ID] = len(expenses)
+		expenses = append(expenses, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(expenses) == 0 {
+		return expenses, nil
+	}
+
+	shareRows, err := q.QueryContext(ctx,
+		`SELECT s.expense_id, s.user_id, s.amount, s.percent::float8
+		 FROM group_expense_shares s
+		 JOIN group_expenses e ON e.id = s.expense_id
+		 WHERE e.group_id = $1 `+filter+`
+		 ORDER BY s.amount DESC, s.user_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer shareRows.Close()
+
+	for shareRows.Next() {
+		var expenseID string
+		var s ExpenseShare
+		var percent sql.NullFloat64
+		if err := shareRows.Scan(&expenseID, &s.UserID, &s.Amount, &percent); err != nil {
+			return nil, err
+		}
+		if percent.Valid {
+			s.Percent = &percent.Float64
+		}
+		if i, ok := index[expenseID]; ok {
+			expenses[i].Shares = append(expenses[i].Shares, s)
+		}
+	}
+	return expenses, shareRows.Err()
+}
+
+// DeleteExpense removes an expense and, by cascade, its shares.
+func (r *PostgresRepository) DeleteExpense(ctx context.Context, groupID, expenseID string) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM group_expenses WHERE id = $1 AND group_id = $2`, expenseID, groupID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreateSettlement records a payment between two members as paid. The
+// balances are recomputed with the group row locked, so concurrent
+// settlements cannot together pay off more than is owed.
+// Returns ErrSettlementExceedsBalance if the amount is more than the payer owes
+// or the recipient is owed.
+func (r *PostgresRepository) CreateSettlement(ctx context.Context, settlement *Settlement) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM travel_groups WHERE id = $1 FOR UPDATE`, settlement.GroupID); err != nil {
+		return err
+	}
+	expenses, err := queryExpenses(ctx, tx, ``, settlement.GroupID)
+	if err != nil {
+		return err
+	}
+	settlements, err := querySettlements(ctx, tx, settlement.GroupID)
+	if err != nil {
+		return err
+	}
+	if !settlement.WithinBalance(ComputeBalances(expenses, settlements)) {
+		return ErrSettlementExceedsBalance
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO group_settlements (group_id, from_user, to_user, amount, currency, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, paid_at`,
+		settlement.GroupID, settlement.FromUser, settlement.ToUser,
+		settlement.Amount, settlement.Currency, settlement.CreatedBy,
+	).Scan(&settlement.ID, &settlement.PaidAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetSettlements returns the group's recorded payments, newest first.
+func (r *PostgresRepository) GetSettlements(ctx context.Context, groupID string) ([]Settlement, error) {
+	return querySettlements(ctx, r.db, groupID)
+}
+
+func querySettlements(ctx context.Context, q querier, groupID string) ([]Settlement, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, group_id, from_user, to_user, amount, currency, COALESCE(created_by::text, ''), paid_at
+		 FROM group_settlements
+		 WHERE group_id = $1
+		 ORDER BY paid_at DESC, id`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settlements := []Settlement{}
+	for rows.Next() {
+		var s Settlement
+		if err := rows.Scan(&s.ID, &s.GroupID, &s.FromUser, &s.ToUser, &s.Amount, &s.Currency,
+			&s.CreatedBy, &s.PaidAt); err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, s)
+	}
+	return settlements, rows.Err()
+}
+
+const inviteColumns = `i.id, i.group_id, i.code, i.kind, COALESCE(i.invitee_id::text, ''), COALESCE(i.created_by::text, ''),
+		        i.max_uses, i.uses, i.expires_at, i.status, i.created_at`
+
+// scanInvite reads the inviteColumns of a row, followed by extra destinations.
+func scanInvite(row interface{ Scan(...any) error }, inv *Invite, extra ...any) error {
+	var maxUses sql.NullInt64
+	dest := append([]any{&inv.ID, &inv.GroupID, &inv.Code, &inv.Kind, &inv.InviteeID, &inv.CreatedBy,
+		&maxUses, &inv.Uses, &inv.ExpiresAt, &inv.Status, &inv.CreatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	if maxUses.Valid {
+		n := int(maxUses.Int64)
+		inv.MaxUses = &n
+	}
+	return nil
+}
+
+// CreateInvite inserts a link or direct invite.
+func (r *PostgresRepository) CreateInvite(ctx context.Context, invite *Invite) error {
+	invitee := sql.NullString{String: invite.InviteeID, Valid: invite.InviteeID != ""}
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO group_invites (group_id, code, kind, invitee_id, created_by, max_uses, expires_at)
+		 SELECT $1::uuid, $2, $3, $4::uuid, $5::uuid, $6::int, $7::timestamptz
+		 WHERE $4::uuid IS NULL OR EXISTS (SELECT 1 FROM users WHERE id = $4::uuid)
+		 ON CONFLICT (group_id, invitee_id) WHERE status = 'active'
+		 DO UPDATE SET expires_at = EXCLUDED.expires_at, created_by = EXCLUDED.created_by
+		 RETURNING id, code, uses, created_at`,
+		invite.GroupID, invite.Code, invite.Kind, invitee, invite.CreatedBy, invite.MaxUses, invite.ExpiresAt,
+	).Scan(&invite.ID, &invite.Code, &invite.Uses, &invite.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInviteeNotFound
+	}
+	return err
+}
+
+func (r *PostgresRepository) GetInvites(ctx context.Context, groupID string) ([]Invite, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+inviteColumns+`
+		 FROM group_invites i
+		 WHERE i.group_id = $1 AND i.status = 'active' AND i.expires_at > NOW()
+		 ORDER BY i.created_at DESC`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invites := []Invite{}
+	for rows.Next() {
+		var inv Invite
+		if err := scanInvite(rows, &inv); err != nil {
+			return nil, err
+		}
+		invites = append(invites, inv)
+	}
+	return invites, rows.Err()
+}
+
+const inviteDetailsQuery = `SELECT ` + inviteColumns + `, g.name, g.event_id, g.max_members,
+		        (SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.id)
+		 FROM group_invites i
+		 JOIN travel_groups g ON g.id = i.group_id`
+
+func (r *PostgresRepository) GetInviteByCode(ctx context.Context, code string) (*InviteDetails, error) {
+	var d InviteDetails
+	row := r.db.QueryRowContext(ctx, inviteDetailsQuery+` WHERE i.code = $1`, code)
+	if err := scanInvite(row, &d.Invite, &d.GroupName, &d.EventID, &d.MaxMembers, &d.MemberCount); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *PostgresRepository) GetUserInvites(ctx context.Context, userID string) ([]InviteDetails, error) {
+	rows, err := r.db.QueryContext(ctx, inviteDetailsQuery+`
+		 WHERE i.invitee_id = $1 AND i.status = 'active' AND i.expires_at > NOW()
+		 ORDER BY i.created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invites := []InviteDetails{}
+	for rows.Next() {
+		var d InviteDetails
+		if err := scanInvite(rows, &d.Invite, &d.GroupName, &d.EventID, &d.MaxMembers, &d.MemberCount); err != nil {
+			return nil, err
+		}
+		invites = append(invites, d)
+	}
+	return invites, rows.Err()
+}
+
+func (r *PostgresRepository) UseInvite(ctx context.Context, inviteID string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE group_invites
+		 SET uses = uses + 1, status = CASE WHEN kind = 'direct' THEN 'accepted' ELSE status END
+		 WHERE id = $1 AND status = 'active' AND expires_at > NOW()
+		   AND (max_uses IS NULL OR uses < max_uses)`, inviteID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrInviteUnavailable
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ReleaseInvite(ctx context.Context, inviteID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE group_invites
+		 SET uses = GREATEST(uses - 1, 0),
+		     status = CASE WHEN kind = 'direct' AND status = 'accepted' THEN 'active' ELSE status END
+		 WHERE id = $1`, inviteID)
+	return err
+}
+
+func (r *PostgresRepository) CloseInvite(ctx context.Context, groupID, inviteID, status string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE group_invites SET status = $3 WHERE id = $1 AND group_id = $2 AND status = 'active'`,
+		inviteID, groupID, status)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// JoinWaitlist queues the user at the back of the group's waitlist.
+func (r *PostgresRepository) JoinWaitlist(ctx context.Context, groupID, userID string) (int, error) {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO group_waitlist (group_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		groupID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var position int
+	err = r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM group_waitlist w
+		 JOIN group_waitlist me ON me.group_id = w.group_id AND me.user_id = $2
+		 WHERE w.group_id = $1 AND (w.created_at, w.user_id) <= (me.created_at, me.user_id)`,
+		groupID, userID).Scan(&position)
+	return position, err
+}
+
+func (r *PostgresRepository) GetWaitlist(ctx context.Context, groupID string) ([]WaitlistEntry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT w.group_id, w.user_id, COALESCE(p.full_name, ''), w.status, w.offer_expires_at, w.created_at
+		 FROM group_waitlist w
+		 LEFT JOIN profiles p ON p.user_id = w.user_id
+		 WHERE w.group_id = $1
+		 ORDER BY w.created_at, w.user_id`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []WaitlistEntry
+	for rows.Next() {
+		e := WaitlistEntry{Position: len(entries) + 1}
+		if err := rows.Scan(&e.GroupID, &e.UserID, &e.FullName, &e.Status, &e.OfferExpiresAt, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *PostgresRepository) LeaveWaitlist(ctx context.Context, groupID, userID string) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM group_waitlist WHERE group_id = $1 AND user_id = $2`, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PromoteFromWaitlist locks the group row so concurrent leaves cannot hand
+// out the same spot twice.
+func (r *PostgresRepository) PromoteFromWaitlist(ctx context.Context, groupID string) ([]Promotion, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var maxMembers int
+	var requiresApproval bool
+	var status string
+	err = tx.QueryRowContext(ctx,
+		`SELECT max_members, requires_approval, status FROM travel_groups WHERE id = $1 FOR UPDATE`, groupID,
+	).Scan(&maxMembers, &requiresApproval, &status)
+	if err != nil {
+		return nil, err
+	}
+	if status != StatusOpen {
+		return nil, nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM group_waitlist WHERE group_id = $1 AND status = 'offered' AND offer_expires_at <= NOW()`,
+		groupID); err != nil {
+		return nil, err
+	}
+
+	var taken int
+	err = tx.QueryRowContext(ctx,
+		`SELECT (SELECT COUNT(*) FROM group_members WHERE group_id = $1) +
+		        (SELECT COUNT(*) FROM group_waitlist WHERE group_id = $1 AND status = 'offered')`, groupID,
+	).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+	if taken >= maxMembers {
+		return nil, nil
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT user_id FROM group_waitlist
+		 WHERE group_id = $1 AND status = 'waiting'
+		 ORDER BY created_at, user_id
+		 LIMIT $2`, groupID, maxMembers-taken)
+	if err != nil {
+		return nil, err
+	}
+	var next []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		next = append(next, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var threadID string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM message_threads WHERE group_id = $1 AND type = 'group' LIMIT 1`, groupID).Scan(&threadID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	promotions := make([]Promotion, 0, len(next))
+	for _, userID := range next {
+		if requiresApproval {
+			p := Promotion{UserID: userID, OfferExpiresAt: time.Now().Add(WaitlistOfferTTL)}
+			_, err = tx.ExecContext(ctx,
+				`UPDATE group_waitlist SET status = 'offered', offer_expires_at = $3
+				 WHERE group_id = $1 AND user_id = $2`,
+				groupID, userID, p.OfferExpiresAt)
+			if err != nil {
+				return nil, err
+			}
+			promotions = append(promotions, p)
+			continue
+		}
+
+		if err := addWaitlistedMember(ctx, tx, groupID, userID, threadID); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, Promotion{UserID: userID, Joined: true})
+	}
+
+	return promotions, tx.Commit()
+}
+
+// addWaitlistedMember moves a user from the waitlist into the group and its chat.
+func addWaitlistedMember(ctx context.Context, tx *sql.Tx, groupID, userID, threadID string) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM group_waitlist WHERE group_id = $1 AND user_id = $2`, groupID, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO group_members (group_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		groupID, userID); err != nil {
+		return err
+	}
+	if threadID == "" {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO thread_participants (thread_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		threadID, userID)
+	return err
+}
+
+func (r *PostgresRepository) ClaimWaitlistOffer(ctx context.Context, groupID, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var held bool
+	var status string
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS (
+		     SELECT 1 FROM group_waitlist
+		     WHERE group_id = $1 AND user_id = $2 AND status = 'offered' AND offer_expires_at > NOW()
+		 ), status FROM travel_groups WHERE id = $1 FOR UPDATE`,
+		groupID, userID).Scan(&held, &status)
+	if err != nil {
+		return err
+	}
+	if !held {
+		return ErrNoWaitlistOffer
+	}
+	if status != StatusOpen {
+		return ErrGroupClosed
+	}
+
+	var threadID string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM message_threads WHERE group_id = $1 AND type = 'group' LIMIT 1`, groupID).Scan(&threadID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err := addWaitlistedMember(ctx, tx, groupID, userID, threadID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ExpireWaitlistOffers deletes the oldest expired offers in one statement.
+// SKIP LOCKED lets several server instances sweep without blocking each other.
+func (r *PostgresRepository) ExpireWaitlistOffers(ctx context.Context, now time.Time, limit int) ([]WaitlistEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		DELETE FROM group_waitlist
+		WHERE (group_id, user_id) IN (
+			SELECT group_id, user_id FROM group_waitlist
+			WHERE status = 'offered' AND offer_expires_at <= $1
+			ORDER BY offer_expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING group_id, user_id, status, offer_expires_at, created_at`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []WaitlistEntry
+	for rows.Next() {
+		var e WaitlistEntry
+		if err := rows.Scan(&e.GroupID, &e.UserID, &e.Status, &e.OfferExpiresAt, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// CreateRating relies on the unique (group, rater, ratee) constraint to
+// enforce one rating per member per trip.
+func (r *PostgresRepository) CreateRating(ctx context.Context, rating *Rating) error {
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO member_ratings (group_id, rater_id, ratee_id, punctuality, communication)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (group_id, rater_id, ratee_id) DO NOTHING
+		 RETURNING id, created_at`,
+		rating.GroupID, rating.RaterID, rating.RateeID, rating.Punctuality, rating.Communication,
+	).Scan(&rating.ID, &rating.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrAlreadyRated
+	}
+	return err
+}
+
+func (r *PostgresRepository) GetRatingsByRater(ctx context.Context, groupID, raterID string) ([]Rating, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, group_id, rater_id, ratee_id, punctuality, communication, created_at
+		 FROM member_ratings
+		 WHERE group_id = $1 AND rater_id = $2
+		 ORDER BY created_at`, groupID, raterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratings []Rating
+	for rows.Next() {
+		var rt Rating
+		if err := rows.Scan(&rt.ID, &rt.GroupID, &rt.RaterID, &rt.RateeID, &rt.Punctuality, &rt.Communication, &rt.CreatedAt); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rt)
+	}
+	return ratings, rows.Err()
 }
@@ -0,0 +1,115 @@
+package groups
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// Join request statuses.
+const (
+	RequestPending   = "pending"
+	RequestAccepted  = "accepted"
+	RequestDeclined  = "declined"
+	RequestCancelled = "cancelled" // withdrawn by the requester
+	RequestExpired   = "expired"   // nobody acted on it within JoinRequestTTL
+)
+
+// JoinRequestTTL is how long a join request stays pending before it expires.
+const JoinRequestTTL = 7 * 24 * time.Hour
+
+// MaxJoinRequestMessage caps the note a requester can attach, in characters.
+const MaxJoinRequestMessage = 500
+
+// JoinGroupRequest is the optional payload for POST /groups/{id}/join. The
+// message is shown to the owner and admins when the group requires approval.
+type JoinGroupRequest struct {
+	Message string `json:"message"`
+}
+
+// JoinRequest is a pending request as seen by the group's owner and admins,
+// with how well the requester matches the current members.
+type JoinRequest struct {
+	UserID          string      `json:"user_id"`
+	FullName        string      `json:"full_name"`
+	CollegeName     string      `json:"college_name"`
+	ProfilePhotoURL string      `json:"profile_photo_url,omitempty"`
+	Message         string      `json:"message"`
+	TrustScore      *TrustScore `json:"trust_score,omitempty"`
+	MatchScore      float64     `json:"match_score"`
+	CommonInterests []string    `json:"common_interests"`
+	RequestedAt     time.Time   `json:"requested_at"`
+	ExpiresAt       time.Time   `json:"expires_at"`
+}
+
+// OutgoingJoinRequest is a request as seen by the user who sent it.
+type OutgoingJoinRequest struct {
+	GroupID     string     `json:"group_id"`
+	GroupName   string     `json:"group_name"`
+	EventID     string     `json:"event_id"`
+	UserID      string     `json:"user_id"`
+	Status      string     `json:"status"`
+	Message     string     `json:"message"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+// JoinRequestExpirer periodically expires join requests that nobody acted on
+// within JoinRequestTTL and lets the requesters know.
+type JoinRequestExpirer struct {
+	h         *Handler
+	interval  time.Duration
+	batchSize int
+}
+
+// NewJoinRequestExpirer creates a JoinRequestExpirer that runs every 15 minutes.
+func NewJoinRequestExpirer(repo Repository, hub *messages.Hub) *JoinRequestExpirer {
+	return &JoinRequestExpirer{
+		h:         NewHandler(repo, hub, nil, nil),
+		interval:  15 * time.Minute,
+		batchSize: 100,
+	}
+}
+
+// Run expires requests until ctx is cancelled. Start it as a goroutine.
+func (e *JoinRequestExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.ExpireRequests(ctx)
+		}
+	}
+}
+
+// ExpireRequests marks every request that has expired by now and notifies
+// the requesters and the groups' managers. It returns how many requests expired.
+func (e *JoinRequestExpirer) ExpireRequests(ctx context.Context) int {
+	now := time.Now()
+	expired := 0
+	for {
+		requests, err := e.h.repo.ExpireJoinRequests(ctx, now, e.batchSize)
+		if err != nil {
+			log.Printf("[JoinRequests] Failed to expire requests: %v", err)
+			return expired
+		}
+
+		for _, req := range requests {
+			e.h.notifyUser(ctx, req.UserID, "Request Expired", "Your request to join "+req.GroupName+" expired",
+				map[string]string{"type": "notification", "group_id": req.GroupID})
+			e.h.joinRequestResolved(ctx, req.GroupID, req.UserID, RequestExpired, "")
+		}
+		expired += len(requests)
+
+		if len(requests) < e.batchSize || ctx.Err() != nil {
+			return expired
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package groups
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// GET /groups/{id}/requests — Get pending join requests with each requester's match score
+func (h *Handler) GetJoinRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	groupID := parts[1]
+
+	group, err := h.repo.GetGroup(r.Context(), groupID)
+	if err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return
+	}
+	role, ok := h.memberRole(w, r, groupID, user.ID)
+	if !ok {
+		return
+	}
+	if !CanManage(role) {
+		http.Error(w, "forbidden: must be a group admin to view requests", http.StatusForbidden)
+		return
+	}
+
+	requests, err := h.repo.GetJoinRequests(r.Context(), groupID)
+	if err != nil {
+		http.Error(w, "failed to get requests", http.StatusInternalServerError)
+		return
+	}
+	if err := h.scoreJoinRequests(r.Context(), group, requests); err != nil {
+		http.Error(w, "failed to get requests", http.StatusInternalServerError)
+		return
+	}
+	if requests == nil {
+		requests = []JoinRequest{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// scoreJoinRequests fills in how well each requester matches the group's
+// current members, using the same scorer as GET /groups/suggested.
+func (h *Handler) scoreJoinRequests(ctx context.Context, group *Group, requests []JoinRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	members, err := h.repo.GetGroupMemberProfiles(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	details := GroupWithDetails{Group: *group, MemberCount: len(members)}
+
+	userIDs := make([]string, len(requests))
+	for i := range requests {
+		userIDs[i] = requests[i].UserID
+	}
+	profiles, err := h.repo.GetMatchProfiles(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range requests {
+		profile := profiles[requests[i].UserID]
+		if profile.HomeCity != "" {
+			profile.Origin = &Place{City: profile.HomeCity}
+		}
+		match := h.scorer.Score(profile, details, members)
+		requests[i].MatchScore = match.Score
+		requests[i].CommonInterests = match.SharedInterests
+		if requests[i].CommonInterests == nil {
+			requests[i].CommonInterests = []string{}
+		}
+	}
+	return nil
+}
+
+// POST /groups/{id}/requests/{userId}/accept
+func (h *Handler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
+	h.handleRequestAction(w, r, "accept")
+}
+
+// POST /groups/{id}/requests/{userId}/decline
+func (h *Handler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
+	h.handleRequestAction(w, r, "decline")
+}
+
+func (h *Handler) handleRequestAction(w http.ResponseWriter, r *http.Request, action string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) < 5 { // groups/{id}/requests/{userId}/accept
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	groupID := parts[1]
+	targetUserID := parts[3]
+
+	group, err := h.repo.GetGroup(r.Context(), groupID)
+	if err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return
+	}
+	role, ok := h.memberRole(w, r, groupID, user.ID)
+	if !ok {
+		return
+	}
+	if !CanManage(role) {
+		http.Error(w, "forbidden: must be a group admin to manage requests", http.StatusForbidden)
+		return
+	}
+
+	if action == "accept" {
+		if group.Status != StatusOpen {
+			http.Error(w, ErrGroupClosed.Error(), http.StatusConflict)
+			return
+		}
+		err = h.repo.AcceptJoinRequest(r.Context(), groupID, targetUserID)
+		if err == nil {
+			h.notifyUser(r.Context(), targetUserID, "Request Accepted!", "You've been added to "+group.Name, map[string]string{"type": "notification", "group_id": groupID})
+			h.postSystemMessage(r.Context(), groupID, targetUserID, messages.KindMemberJoined, map[string]string{"user_id": targetUserID, "approved_by": user.ID})
+			h.joinRequestResolved(r.Context(), groupID, targetUserID, RequestAccepted, user.ID)
+			h.memberJoined(r.Context(), groupID, targetUserID, messages.JoinedByRequest, user.ID)
+		}
+	} else {
+		err = h.repo.DeclineJoinRequest(r.Context(), groupID, targetUserID)
+		if err == nil {
+			h.joinRequestResolved(r.Context(), groupID, targetUserID, RequestDeclined, user.ID)
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "no pending request from this user", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrGroupClosed) || errors.Is(err, ErrGroupFull) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "failed to process request", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "request " + action + "ed"})
+}
+
+// DELETE /groups/{id}/join — Cancel the caller's pending join request
+func (h *Handler) CancelJoinRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	groupID := parts[1]
+
+	if err := h.repo.CancelJoinRequest(r.Context(), groupID, user.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "no pending request to cancel", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to cancel request", http.StatusInternalServerError)
+		return
+	}
+	h.joinRequestResolved(r.Context(), groupID, user.ID, RequestCancelled, "")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "request cancelled"})
+}
+
+// GET /me/group-requests — List the join requests the caller has sent and their status
+func (h *Handler) GetMyJoinRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.repo.GetUserJoinRequests(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "failed to get requests", http.StatusInternalServerError)
+		return
+	}
+	if requests == nil {
+		requests = []OutgoingJoinRequest{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
@@ -0,0 +1,28 @@
+package groups
+
+// Member roles. Each group has exactly one owner; admins share the owner's
+// day-to-day powers but cannot delete the group or change roles.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// CanManage reports whether a member with role may update the group, manage
+// join requests and invites, and remove plain members.
+func CanManage(role string) bool {
+	return role == RoleOwner || role == RoleAdmin
+}
+
+// CanRemove reports whether a member with role may kick a member with target's role.
+// Only the owner can remove admins, and nobody can remove the owner.
+func CanRemove(role, target string) bool {
+	switch target {
+	case RoleOwner:
+		return false
+	case RoleAdmin:
+		return role == RoleOwner
+	default:
+		return CanManage(role)
+	}
+}
@@ -0,0 +1,143 @@
+package groups
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// ownedGroup resolves the group in /groups/{id}/... and checks that the user owns it.
+// It writes the error response and returns ok=false on failure.
+func (h *Handler) ownedGroup(w http.ResponseWriter, r *http.Request) (groupID, userID string, ok bool) {
+	user, authed := auth.UserFromContext(r.Context())
+	if !authed {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return "", "", false
+	}
+	groupID = parts[1]
+
+	if _, err := h.repo.GetGroup(r.Context(), groupID); err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return "", "", false
+	}
+	role, ok := h.memberRole(w, r, groupID, user.ID)
+	if !ok {
+		return "", "", false
+	}
+	if role != RoleOwner {
+		http.Error(w, "only the group owner can change roles", http.StatusForbidden)
+		return "", "", false
+	}
+	return groupID, user.ID, true
+}
+
+// POST /groups/{id}/members/{userId}/promote — Make a member an admin (owner only)
+func (h *Handler) PromoteMember(w http.ResponseWriter, r *http.Request) {
+	h.setMemberRole(w, r, RoleAdmin)
+}
+
+// POST /groups/{id}/members/{userId}/demote — Make an admin a plain member (owner only)
+func (h *Handler) DemoteMember(w http.ResponseWriter, r *http.Request) {
+	h.setMemberRole(w, r, RoleMember)
+}
+
+func (h *Handler) setMemberRole(w http.ResponseWriter, r *http.Request, role string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, ok := h.ownedGroup(w, r)
+	if !ok {
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 { // groups/{id}/members/{userId}/promote
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	targetID := parts[3]
+	if targetID == userID {
+		http.Error(w, "transfer ownership instead of changing your own role", http.StatusBadRequest)
+		return
+	}
+
+	current, ok := h.memberRole(w, r, groupID, targetID)
+	if !ok {
+		return
+	}
+	if current == "" {
+		http.Error(w, "user is not a member of this group", http.StatusNotFound)
+		return
+	}
+
+	if current != role {
+		if err := h.repo.SetMemberRole(r.Context(), groupID, targetID, role); err != nil {
+			http.Error(w, "failed to change role", http.StatusInternalServerError)
+			return
+		}
+		h.postSystemMessage(r.Context(), groupID, userID, messages.KindRoleChanged, map[string]string{
+			"user_id": targetID,
+			"role":    role,
+		})
+		h.groupUpdated(r.Context(), groupID, userID, messages.GroupChangeRoles)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"user_id": targetID, "role": role})
+}
+
+// POST /groups/{id}/transfer — Hand ownership to another member (owner only).
+// The previous owner stays on as an admin.
+func (h *Handler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupID, userID, ok := h.ownedGroup(w, r)
+	if !ok {
+		return
+	}
+
+	var req TransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.UserID == userID {
+		http.Error(w, "you already own this group", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.TransferOwnership(r.Context(), groupID, req.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "user is not a member of this group", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to transfer ownership", http.StatusInternalServerError)
+		return
+	}
+
+	h.postSystemMessage(r.Context(), groupID, userID, messages.KindRoleChanged, map[string]string{
+		"user_id":        req.UserID,
+		"role":           RoleOwner,
+		"previous_owner": userID,
+	})
+	h.groupUpdated(r.Context(), groupID, userID, messages.GroupChangeRoles)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "ownership transferred"})
+}
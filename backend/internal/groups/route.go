@@ -0,0 +1,158 @@
+package groups
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Transport modes a group can travel by.
+const (
+	TransportTrain   = "train"
+	TransportBus     = "bus"
+	TransportFlight  = "flight"
+	TransportCarpool = "carpool"
+)
+
+// ValidTransportMode reports whether m can be stored on a group.
+func ValidTransportMode(m string) bool {
+	return m == TransportTrain || m == TransportBus || m == TransportFlight || m == TransportCarpool
+}
+
+// DefaultOriginRadiusKm is how far from the requested origin a group may start
+// when filtering by coordinates without an explicit radius_km.
+const DefaultOriginRadiusKm = 50.0
+
+const earthRadiusKm = 6371.0
+
+// Place is a city with optional coordinates.
+type Place struct {
+	City string   `json:"city"`
+	Lat  *float64 `json:"lat,omitempty"`
+	Lng  *float64 `json:"lng,omitempty"`
+}
+
+// HasCoords reports whether the place can be used for distance calculations.
+func (p *Place) HasCoords() bool {
+	return p != nil && p.Lat != nil && p.Lng != nil
+}
+
+// Validate checks that a city is set and coordinates, if any, are complete and in range.
+func (p *Place) Validate() error {
+	p.City = strings.TrimSpace(p.City)
+	if p.City == "" {
+		return errors.New("city is required")
+	}
+	if len(p.City) > 100 {
+		return errors.New("city too long (max 100 chars)")
+	}
+	if (p.Lat == nil) != (p.Lng == nil) {
+		return errors.New("lat and lng must be set together")
+	}
+	if p.HasCoords() && (math.Abs(*p.Lat) > 90 || math.Abs(*p.Lng) > 180) {
+		return errors.New("lat must be within ±90 and lng within ±180")
+	}
+	return nil
+}
+
+// SameCity reports whether both places name the same city, ignoring case.
+func (p *Place) SameCity(o *Place) bool {
+	return p != nil && o != nil && p.City != "" && strings.EqualFold(strings.TrimSpace(p.City), strings.TrimSpace(o.City))
+}
+
+// DistanceKm returns the great-circle distance between two places with coordinates.
+func (p *Place) DistanceKm(o *Place) float64 {
+	return HaversineKm(*p.Lat, *p.Lng, *o.Lat, *o.Lng)
+}
+
+// HaversineKm returns the great-circle distance in kilometres between two points.
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// validateRoute checks the optional origin, destination and transport mode of a group.
+func validateRoute(origin, destination *Place, mode string) error {
+	if origin != nil {
+		if err := origin.Validate(); err != nil {
+			return errors.New("origin: " + err.Error())
+		}
+	}
+	if destination != nil {
+		if err := destination.Validate(); err != nil {
+			return errors.New("destination: " + err.Error())
+		}
+	}
+	if mode != "" && !ValidTransportMode(mode) {
+		return errors.New("transport_mode must be one of: train, bus, flight, carpool")
+	}
+	return nil
+}
+
+// OriginFilter narrows a group listing to groups leaving from near a place.
+// A group matches if its origin city equals Place.City, or if both have
+// coordinates and the group starts within RadiusKm.
+type OriginFilter struct {
+	Place    *Place
+	RadiusKm float64
+}
+
+// ParseOriginFilter reads origin, origin_lat, origin_lng and radius_km from a query string.
+// It returns a nil filter when no origin was requested.
+func ParseOriginFilter(q url.Values) (*OriginFilter, error) {
+	city := strings.TrimSpace(q.Get("origin"))
+	lat, lng := q.Get("origin_lat"), q.Get("origin_lng")
+	if city == "" && lat == "" && lng == "" {
+		if q.Get("radius_km") != "" {
+			return nil, errors.New("radius_km requires origin_lat and origin_lng")
+		}
+		return nil, nil
+	}
+
+	f := &OriginFilter{Place: &Place{City: city}, RadiusKm: DefaultOriginRadiusKm}
+	if lat != "" || lng != "" {
+		la, errLat := strconv.ParseFloat(lat, 64)
+		ln, errLng := strconv.ParseFloat(lng, 64)
+		if errLat != nil || errLng != nil {
+			return nil, errors.New("origin_lat and origin_lng must both be numbers")
+		}
+		f.Place.Lat, f.Place.Lng = &la, &ln
+		if math.Abs(la) > 90 || math.Abs(ln) > 180 {
+			return nil, errors.New("origin_lat must be within ±90 and origin_lng within ±180")
+		}
+	}
+	if r := q.Get("radius_km"); r != "" {
+		if !f.Place.HasCoords() {
+			return nil, errors.New("radius_km requires origin_lat and origin_lng")
+		}
+		radius, err := strconv.ParseFloat(r, 64)
+		if err != nil || radius <= 0 {
+			return nil, errors.New("radius_km must be a positive number")
+		}
+		f.RadiusKm = radius
+	}
+	return f, nil
+}
+
+// Apply drops groups that do not match and sets DistanceKm on those with coordinates.
+func (f *OriginFilter) Apply(groups []GroupWithDetails) []GroupWithDetails {
+	out := groups[:0]
+	for _, g := range groups {
+		match := f.Place.SameCity(g.Origin)
+		if f.Place.HasCoords() && g.Origin.HasCoords() {
+			d := f.Place.DistanceKm(g.Origin)
+			g.DistanceKm = &d
+			match = match || d <= f.RadiusKm
+		}
+		if match {
+			out = append(out, g)
+		}
+	}
+	return out
+}
@@ -0,0 +1,307 @@
+package groups
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Factor names one component of a group match score.
+type Factor string
+
+const (
+	FactorInterests Factor = "interests" // overlap with members' interests
+	FactorCollege   Factor = "college"   // members from the user's college
+	FactorOrigin    Factor = "origin"    // group starts near the user, or members from the user's city
+	FactorDeparture Factor = "departure" // group departure date vs. the user's
+	FactorFill      Factor = "fill"      // how close the group is to forming
+	FactorTrust     Factor = "trust"     // members' trust scores from past trips
+)
+
+var factors = []Factor{FactorInterests, FactorCollege, FactorOrigin, FactorDeparture, FactorFill, FactorTrust}
+
+// Gender preferences a group can restrict itself to.
+const (
+	GenderAny       = "any"
+	GenderWomenOnly = "women_only"
+	GenderMenOnly   = "men_only"
+)
+
+// ValidGenderPreference reports whether p can be stored on a group.
+func ValidGenderPreference(p string) bool {
+	return p == GenderAny || p == GenderWomenOnly || p == GenderMenOnly
+}
+
+// GenderAllowed reports whether a user with the given profile gender may join a
+// group with preference p. Users who have not set a gender only match open groups.
+func GenderAllowed(p, gender string) bool {
+	switch p {
+	case GenderWomenOnly:
+		return gender == "female"
+	case GenderMenOnly:
+		return gender == "male"
+	}
+	return true
+}
+
+// Weights sets how much each factor counts towards the final score. They need
+// not sum to 1: only the factors with data for a given group are used, and
+// their weights are normalised over that subset.
+type Weights map[Factor]float64
+
+// DefaultWeights is used unless MATCH_WEIGHTS overrides it.
+var DefaultWeights = Weights{
+	FactorInterests: 0.35,
+	FactorCollege:   0.15,
+	FactorOrigin:    0.2,
+	FactorDeparture: 0.2,
+	FactorFill:      0.1,
+	FactorTrust:     0.1,
+}
+
+// ParseWeights reads "interests=0.5,fill=0" style overrides on top of DefaultWeights.
+func ParseWeights(s string) (Weights, error) {
+	w := make(Weights, len(DefaultWeights))
+	for f, v := range DefaultWeights {
+		w[f] = v
+	}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid weight %q: want factor=value", part)
+		}
+		f := Factor(strings.TrimSpace(name))
+		if _, known := DefaultWeights[f]; !known {
+			return nil, fmt.Errorf("unknown match factor %q", f)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, fmt.Errorf("invalid weight for %s: %q", f, value)
+		}
+		w[f] = v
+	}
+	return w, nil
+}
+
+// MatchProfile is the subset of a user's profile that group matching looks at.
+type MatchProfile struct {
+	UserID    string
+	College   string
+	HomeCity  string
+	Gender    string
+	Interests []string
+	// DepartureDate and Origin are when and where the user wants to leave from;
+	// only set for the user being matched.
+	DepartureDate *time.Time
+	Origin        *Place
+	// Trust is nil until the user has MinTrustRatings ratings.
+	Trust *TrustScore
+}
+
+// FactorScore explains one factor's part in a Match.
+type FactorScore struct {
+	Factor       Factor  `json:"factor"`
+	Applied      bool    `json:"applied"` // false when there was no data to score it on
+	Weight       float64 `json:"weight"`  // normalised over the applied factors
+	Score        float64 `json:"score"`   // 0..1
+	Contribution float64 `json:"contribution"`
+	Detail       string  `json:"detail"`
+}
+
+// Match is the result of scoring one group for one user.
+type Match struct {
+	Score           float64
+	Eligible        bool
+	Reason          string // why the group is not eligible
+	Breakdown       []FactorScore
+	SharedInterests []string
+}
+
+// Scorer ranks a group for a user given the group's current members.
+type Scorer interface {
+	Score(user MatchProfile, group GroupWithDetails, members []MatchProfile) Match
+}
+
+// WeightedScorer combines the per-factor scores as a weighted average.
+type WeightedScorer struct {
+	Weights Weights
+	// DepartureWindow is how far apart departure dates can be before the
+	// departure factor drops to zero.
+	DepartureWindow time.Duration
+	// OriginRadiusKm is how far from the user a group can start before the
+	// origin factor drops to zero.
+	OriginRadiusKm float64
+}
+
+// NewWeightedScorer returns a WeightedScorer with a three day departure window
+// and a 100 km origin radius.
+func NewWeightedScorer(w Weights) *WeightedScorer {
+	return &WeightedScorer{Weights: w, DepartureWindow: 3 * 24 * time.Hour, OriginRadiusKm: 100}
+}
+
+func (s *WeightedScorer) Score(user MatchProfile, group GroupWithDetails, members []MatchProfile) Match {
+	m := Match{Eligible: true}
+	if group.Status == StatusLocked {
+		m.Eligible, m.Reason = false, "group is locked"
+	} else if group.MaxMembers > 0 && group.MemberCount >= group.MaxMembers {
+		m.Eligible, m.Reason = false, "group is full"
+	} else if !GenderAllowed(group.GenderPreference, user.Gender) {
+		m.Eligible, m.Reason = false, "group is limited to "+strings.TrimSuffix(group.GenderPreference, "_only")
+	}
+
+	others := make([]MatchProfile, 0, len(members))
+	for _, member := range members {
+		if member.UserID != user.UserID {
+			others = append(others, member)
+		}
+	}
+	members = others
+
+	raw := map[Factor]FactorScore{
+		FactorInterests: scoreInterests(user, members, &m),
+		FactorCollege:   scoreSame(user.College, members, func(p MatchProfile) string { return p.College }, "your college"),
+		FactorOrigin:    s.scoreOrigin(user, group, members),
+		FactorDeparture: s.scoreDeparture(user.DepartureDate, group.DepartureDate),
+		FactorFill:      scoreFill(group),
+		FactorTrust:     scoreTrust(members),
+	}
+
+	total := 0.0
+	for _, f := range factors {
+		if raw[f].Applied {
+			total += s.Weights[f]
+		}
+	}
+	for _, f := range factors {
+		fs := raw[f]
+		fs.Factor = f
+		if fs.Applied && total > 0 {
+			fs.Weight = s.Weights[f] / total
+			fs.Contribution = fs.Weight * fs.Score
+			m.Score += fs.Contribution
+		}
+		m.Breakdown = append(m.Breakdown, fs)
+	}
+	return m
+}
+
+func scoreInterests(user MatchProfile, members []MatchProfile, m *Match) FactorScore {
+	shared := make(map[string]bool)
+	sum, n := 0.0, 0
+	for _, member := range members {
+		if len(member.Interests) == 0 {
+			continue
+		}
+		sum += CalculateSimilarity(user.Interests, member.Interests)
+		n++
+		for _, i := range FindCommonInterests(user.Interests, member.Interests) {
+			shared[i] = true
+		}
+	}
+	if len(user.Interests) == 0 || n == 0 {
+		return FactorScore{Detail: "no interests to compare"}
+	}
+	for i := range shared {
+		m.SharedInterests = append(m.SharedInterests, i)
+	}
+	sort.Strings(m.SharedInterests)
+	return FactorScore{Applied: true, Score: sum / float64(n), Detail: fmt.Sprintf("%d shared interests", len(shared))}
+}
+
+// scoreSame scores the share of members whose attribute matches the user's.
+func scoreSame(value string, members []MatchProfile, attr func(MatchProfile) string, label string) FactorScore {
+	value = strings.TrimSpace(value)
+	if value == "" || len(members) == 0 {
+		return FactorScore{Detail: "not enough profile data"}
+	}
+	same := 0
+	for _, member := range members {
+		if strings.EqualFold(strings.TrimSpace(attr(member)), value) {
+			same++
+		}
+	}
+	return FactorScore{
+		Applied: true,
+		Score:   float64(same) / float64(len(members)),
+		Detail:  fmt.Sprintf("%d of %d members from %s", same, len(members), label),
+	}
+}
+
+// scoreOrigin prefers the group's own origin and falls back to where its members live.
+func (s *WeightedScorer) scoreOrigin(user MatchProfile, group GroupWithDetails, members []MatchProfile) FactorScore {
+	if user.Origin == nil || group.Origin == nil {
+		return scoreSame(user.HomeCity, members, func(p MatchProfile) string { return p.HomeCity }, "your city")
+	}
+	if user.Origin.HasCoords() && group.Origin.HasCoords() {
+		d := user.Origin.DistanceKm(group.Origin)
+		score := 0.0
+		if s.OriginRadiusKm > 0 {
+			score = math.Max(0, 1-d/s.OriginRadiusKm)
+		}
+		return FactorScore{Applied: true, Score: score, Detail: fmt.Sprintf("starts %.0f km from you", d)}
+	}
+	if user.Origin.City == "" {
+		return FactorScore{Detail: "not enough profile data"}
+	}
+	if user.Origin.SameCity(group.Origin) {
+		return FactorScore{Applied: true, Score: 1, Detail: "leaves from " + group.Origin.City}
+	}
+	return FactorScore{Applied: true, Detail: "leaves from " + group.Origin.City}
+}
+
+func (s *WeightedScorer) scoreDeparture(want, departs *time.Time) FactorScore {
+	if want == nil || departs == nil {
+		return FactorScore{Detail: "no departure date to compare"}
+	}
+	days := math.Abs(departs.Truncate(24*time.Hour).Sub(want.Truncate(24*time.Hour)).Hours() / 24)
+	window := s.DepartureWindow.Hours() / 24
+	score := 0.0
+	if window > 0 {
+		score = math.Max(0, 1-days/window)
+	} else if days == 0 {
+		score = 1
+	}
+	detail := "departs the same day"
+	if days > 0 {
+		detail = fmt.Sprintf("departs %.0f days apart", days)
+	}
+	return FactorScore{Applied: true, Score: score, Detail: detail}
+}
+
+func scoreFill(group GroupWithDetails) FactorScore {
+	if group.MaxMembers <= 0 {
+		return FactorScore{Detail: "no member limit"}
+	}
+	return FactorScore{
+		Applied: true,
+		Score:   math.Min(1, float64(group.MemberCount)/float64(group.MaxMembers)),
+		Detail:  fmt.Sprintf("%d of %d spots taken", group.MemberCount, group.MaxMembers),
+	}
+}
+
+// scoreTrust averages the trust scores of the members who have one, mapping 1..5 onto 0..1.
+func scoreTrust(members []MatchProfile) FactorScore {
+	sum, n := 0.0, 0
+	for _, member := range members {
+		if member.Trust != nil {
+			sum += member.Trust.Score
+			n++
+		}
+	}
+	if n == 0 {
+		return FactorScore{Detail: "no trust scores yet"}
+	}
+	avg := sum / float64(n)
+	return FactorScore{
+		Applied: true,
+		Score:   math.Max(0, math.Min(1, (avg-1)/4)),
+		Detail:  fmt.Sprintf("average trust score %.1f from %d members", avg, n),
+	}
+}
@@ -0,0 +1,310 @@
+package groups
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Orders GET /groups can return groups in.
+const (
+	SortNewest    = "newest"    // most recently created first
+	SortDeparture = "departure" // soonest departure first; groups without a date last
+	SortMatch     = "match"     // best match score for the caller first
+	SortDistance  = "distance"  // nearest origin first; needs origin_lat and origin_lng
+)
+
+// Page sizes for GET /groups.
+const (
+	DefaultGroupPageSize = 20
+	MaxGroupPageSize     = 50
+)
+
+// ErrInvalidCursor is returned when a GET /groups cursor cannot be decoded or
+// was issued for a different sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// GroupSearch selects one page of GET /groups. Zero values mean "no filter".
+type GroupSearch struct {
+	EventID          string
+	DepartureFrom    *time.Time // inclusive
+	DepartureTo      *time.Time // inclusive, to the end of the day
+	MinOpenSeats     int
+	RequiresApproval *bool
+	College          string // at least one member from this college
+	Origin           *OriginFilter
+	Query            string // full-text match on name and description
+	Sort             string
+	After            *GroupCursor
+	Limit            int
+}
+
+// InDatabase reports whether the repository can apply the sort order and
+// cursor itself. Match and distance sorts, and coordinate origin filters, are
+// applied in Go over every group that passes the other filters.
+func (s GroupSearch) InDatabase() bool {
+	return (s.Sort == SortNewest || s.Sort == SortDeparture) && !s.Origin.hasCoords()
+}
+
+func (f *OriginFilter) hasCoords() bool { return f != nil && f.Place.HasCoords() }
+
+// GroupCursor is a keyset position in one sort order: the sort key of the last
+// group on a page and its ID to break ties.
+type GroupCursor struct {
+	Sort string
+	Key  string
+	ID   string
+}
+
+// Encode returns the opaque string form of the cursor handed out to clients.
+func (c GroupCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Sort + "|" + c.Key + "|" + c.ID))
+}
+
+// DecodeGroupCursor parses a cursor previously produced by GroupCursor.Encode
+// for the given sort order.
+func DecodeGroupCursor(s, sortBy string) (*GroupCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[0] != sortBy {
+		return nil, ErrInvalidCursor
+	}
+	c := &GroupCursor{Sort: parts[0], Key: parts[1], ID: parts[2]}
+	if _, err := uuid.Parse(c.ID); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := c.pivot(); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// cursorFor returns the cursor pointing just after g.
+func cursorFor(g GroupWithDetails, sortBy string) GroupCursor {
+	c := GroupCursor{Sort: sortBy, ID: g.ID}
+	switch sortBy {
+	case SortNewest:
+		c.Key = g.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortDeparture:
+		if g.DepartureDate != nil {
+			c.Key = g.DepartureDate.UTC().Format(time.RFC3339Nano)
+		}
+	case SortMatch:
+		c.Key = strconv.FormatFloat(g.MatchScore, 'g', -1, 64)
+	case SortDistance:
+		if g.DistanceKm != nil {
+			c.Key = strconv.FormatFloat(*g.DistanceKm, 'g', -1, 64)
+		}
+	}
+	return c
+}
+
+// pivot rebuilds just enough of a group from the cursor to compare against.
+// An empty key stands for a missing departure date or distance.
+func (c GroupCursor) pivot() (GroupWithDetails, error) {
+	g := GroupWithDetails{Group: Group{ID: c.ID}}
+	switch c.Sort {
+	case SortNewest, SortDeparture:
+		if c.Key == "" && c.Sort == SortDeparture {
+			return g, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, c.Key)
+		if err != nil {
+			return g, err
+		}
+		if c.Sort == SortNewest {
+			g.CreatedAt = t
+		} else {
+			g.DepartureDate = &t
+		}
+	case SortMatch, SortDistance:
+		if c.Key == "" && c.Sort == SortDistance {
+			return g, nil
+		}
+		v, err := strconv.ParseFloat(c.Key, 64)
+		if err != nil || math.IsNaN(v) {
+			return g, ErrInvalidCursor
+		}
+		if c.Sort == SortMatch {
+			g.MatchScore = v
+		} else {
+			g.DistanceKm = &v
+		}
+	default:
+		return g, ErrInvalidCursor
+	}
+	return g, nil
+}
+
+// groupLess orders groups for sortBy, breaking ties on ID so that every
+// group has a unique position for cursors to point at.
+func groupLess(a, b GroupWithDetails, sortBy string) bool {
+	switch sortBy {
+	case SortNewest:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	case SortDeparture:
+		if c := compareOptional(timeKey(a.DepartureDate), timeKey(b.DepartureDate)); c != 0 {
+			return c < 0
+		}
+	case SortMatch:
+		if a.MatchScore != b.MatchScore {
+			return a.MatchScore > b.MatchScore
+		}
+	case SortDistance:
+		if c := compareOptional(a.DistanceKm, b.DistanceKm); c != 0 {
+			return c < 0
+		}
+	}
+	return a.ID < b.ID
+}
+
+func timeKey(t *time.Time) *float64 {
+	if t == nil {
+		return nil
+	}
+	v := float64(t.UnixNano())
+	return &v
+}
+
+// compareOptional sorts missing values after present ones.
+func compareOptional(a, b *float64) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	case *a < *b:
+		return -1
+	case *a > *b:
+		return 1
+	}
+	return 0
+}
+
+// paginate sorts groups by s.Sort and returns the page after s.After, with at
+// most s.Limit groups, and the cursor for the next page if there is one.
+func (s GroupSearch) paginate(groups []GroupWithDetails) ([]GroupWithDetails, *GroupCursor) {
+	sort.SliceStable(groups, func(i, j int) bool { return groupLess(groups[i], groups[j], s.Sort) })
+	if s.After != nil {
+		pivot, _ := s.After.pivot()
+		start := sort.Search(len(groups), func(i int) bool { return groupLess(pivot, groups[i], s.Sort) })
+		groups = groups[start:]
+	}
+	return s.page(groups)
+}
+
+// page trims groups that are already in order to s.Limit.
+func (s GroupSearch) page(groups []GroupWithDetails) ([]GroupWithDetails, *GroupCursor) {
+	if s.Limit <= 0 || len(groups) <= s.Limit {
+		return groups, nil
+	}
+	groups = groups[:s.Limit]
+	next := cursorFor(groups[s.Limit-1], s.Sort)
+	return groups, &next
+}
+
+// GroupPage is returned by GET /groups.
+type GroupPage struct {
+	Groups     []GroupWithDetails `json:"groups"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	HasMore    bool               `json:"has_more"`
+}
+
+// ParseGroupSearch reads the GET /groups query string.
+func ParseGroupSearch(q url.Values) (GroupSearch, error) {
+	s := GroupSearch{
+		EventID: strings.TrimSpace(q.Get("event_id")),
+		College: strings.TrimSpace(q.Get("college")),
+		Query:   strings.TrimSpace(q.Get("q")),
+		Limit:   DefaultGroupPageSize,
+	}
+	if len(s.Query) > 200 {
+		return s, errors.New("q too long (max 200 chars)")
+	}
+	if s.EventID != "" {
+		if _, err := uuid.Parse(s.EventID); err != nil {
+			return s, errors.New("invalid event_id")
+		}
+	}
+
+	var err error
+	if s.Origin, err = ParseOriginFilter(q); err != nil {
+		return s, err
+	}
+	for name, dst := range map[string]**time.Time{"departure_from": &s.DepartureFrom, "departure_to": &s.DepartureTo} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return s, fmt.Errorf("invalid %s format, use YYYY-MM-DD", name)
+			}
+			*dst = &t
+		}
+	}
+	if s.DepartureFrom != nil && s.DepartureTo != nil && s.DepartureTo.Before(*s.DepartureFrom) {
+		return s, errors.New("departure_to must not be before departure_from")
+	}
+	if v := q.Get("open_seats"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return s, errors.New("open_seats must be a positive integer")
+		}
+		s.MinOpenSeats = n
+	}
+	if v := q.Get("requires_approval"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return s, errors.New("requires_approval must be true or false")
+		}
+		s.RequiresApproval = &b
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxGroupPageSize {
+			return s, fmt.Errorf("limit must be between 1 and %d", MaxGroupPageSize)
+		}
+		s.Limit = n
+	}
+
+	s.Sort = q.Get("sort")
+	switch s.Sort {
+	case "":
+		s.Sort = SortNewest
+		if s.Origin.hasCoords() {
+			s.Sort = SortDistance
+		}
+	case SortNewest, SortDeparture:
+	case SortMatch:
+		if s.EventID == "" {
+			return s, errors.New("sort=match requires event_id")
+		}
+	case SortDistance:
+		if !s.Origin.hasCoords() {
+			return s, errors.New("sort=distance requires origin_lat and origin_lng")
+		}
+	default:
+		return s, errors.New("sort must be one of: newest, departure, match, distance")
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		if s.After, err = DecodeGroupCursor(v, s.Sort); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,110 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// Waitlist entry statuses.
+const (
+	WaitlistWaiting = "waiting"
+	WaitlistOffered = "offered" // a spot is held until OfferExpiresAt
+)
+
+// WaitlistOfferTTL is how long a waitlisted user has to claim a spot in a
+// group that requires approval before it passes to the next in line.
+const WaitlistOfferTTL = 24 * time.Hour
+
+// ErrNoWaitlistOffer is returned by ClaimWaitlistOffer when the user holds no
+// live offer for the group.
+var ErrNoWaitlistOffer = errors.New("no spot is being held for you")
+
+// WaitlistEntry is a user queued for a full group. Position is 1-based.
+type WaitlistEntry struct {
+	GroupID        string     `json:"group_id"`
+	UserID         string     `json:"user_id"`
+	FullName       string     `json:"full_name,omitempty"`
+	Position       int        `json:"position"`
+	Status         string     `json:"status"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Waitlist is the response for GET /groups/{id}/waitlist. Entries holds the
+// whole queue for the owner and admins, and only the caller's own entry otherwise.
+type Waitlist struct {
+	Count   int             `json:"count"`
+	Entries []WaitlistEntry `json:"entries"`
+}
+
+// Promotion is what happened to a waitlisted user when a spot opened.
+type Promotion struct {
+	UserID string
+	// Joined is false when the group requires approval and the user was
+	// offered the spot until OfferExpiresAt instead.
+	Joined         bool
+	OfferExpiresAt time.Time
+}
+
+// WaitlistSweeper periodically drops waitlist offers that were not claimed in
+// time and passes each freed spot to the next user in line.
+type WaitlistSweeper struct {
+	h         *Handler
+	interval  time.Duration
+	batchSize int
+}
+
+// NewWaitlistSweeper creates a WaitlistSweeper that runs once a minute.
+func NewWaitlistSweeper(repo Repository, hub *messages.Hub) *WaitlistSweeper {
+	return &WaitlistSweeper{
+		h:         NewHandler(repo, hub, nil, nil),
+		interval:  time.Minute,
+		batchSize: 100,
+	}
+}
+
+// Run sweeps expired offers until ctx is cancelled. Start it as a goroutine.
+func (s *WaitlistSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.SweepExpiredOffers(ctx)
+		}
+	}
+}
+
+// SweepExpiredOffers removes every offer that has expired by now and fills
+// the affected groups from their waitlists. It returns how many offers expired.
+func (s *WaitlistSweeper) SweepExpiredOffers(ctx context.Context) int {
+	now := time.Now()
+	expired := 0
+	for {
+		entries, err := s.h.repo.ExpireWaitlistOffers(ctx, now, s.batchSize)
+		if err != nil {
+			log.Printf("[Waitlist] Failed to expire offers: %v", err)
+			return expired
+		}
+
+		seen := make(map[string]bool)
+		for _, e := range entries {
+			if !seen[e.GroupID] {
+				seen[e.GroupID] = true
+				s.h.fillFromWaitlist(ctx, e.GroupID)
+			}
+		}
+		expired += len(entries)
+
+		if len(entries) < s.batchSize || ctx.Err() != nil {
+			return expired
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package groups
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// joinWaitlist queues a user who tried to join a full group.
+func (h *Handler) joinWaitlist(w http.ResponseWriter, r *http.Request, groupID, userID string) {
+	member, err := h.repo.IsGroupMember(r.Context(), groupID, userID)
+	if err != nil {
+		http.Error(w, "failed to check membership", http.StatusInternalServerError)
+		return
+	}
+	if member {
+		http.Error(w, "you are already a member of this group", http.StatusConflict)
+		return
+	}
+
+	position, err := h.repo.JoinWaitlist(r.Context(), groupID, userID)
+	if err != nil {
+		http.Error(w, "failed to join waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": "group is full — added to waitlist", "position": position})
+}
+
+// fillFromWaitlist hands the group's open spots to the head of its waitlist
+// and tells each user. Failures are logged; the change that opened the spot
+// has already been committed.
+func (h *Handler) fillFromWaitlist(ctx context.Context, groupID string) {
+	promotions, err := h.repo.PromoteFromWaitlist(ctx, groupID)
+	if err != nil {
+		log.Printf("[Groups] Failed to promote waitlist of group %s: %v", groupID, err)
+		return
+	}
+	if len(promotions) == 0 {
+		return
+	}
+
+	group, err := h.repo.GetGroup(ctx, groupID)
+	if err != nil {
+		log.Printf("[Groups] Failed to load group %s after promoting its waitlist: %v", groupID, err)
+		return
+	}
+	for _, p := range promotions {
+		data := map[string]string{"type": "notification", "group_id": groupID}
+		if p.Joined {
+			h.notifyUser(ctx, p.UserID, "You're In!", "A spot opened up and you've been added to "+group.Name, data)
+			h.postSystemMessage(ctx, groupID, p.UserID, messages.KindMemberJoined, map[string]string{"user_id": p.UserID, "from_waitlist": "true"})
+			h.memberJoined(ctx, groupID, p.UserID, messages.JoinedFromWaitlist, "")
+			continue
+		}
+		hours := strconv.Itoa(int(WaitlistOfferTTL.Hours()))
+		h.notifyUser(ctx, p.UserID, "A Spot Opened Up", "Claim your spot in "+group.Name+" within "+hours+" hours", data)
+	}
+}
+
+// GET /groups/{id}/waitlist — The whole queue for the owner and admins, your own place otherwise
+func (h *Handler) GetWaitlist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 { // groups/{id}/waitlist
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	groupID := parts[1]
+
+	if _, err := h.repo.GetGroup(r.Context(), groupID); err != nil {
+		http.Error(w, "group not found", http.StatusNotFound)
+		return
+	}
+	role, ok := h.memberRole(w, r, groupID, user.ID)
+	if !ok {
+		return
+	}
+
+	entries, err := h.repo.GetWaitlist(r.Context(), groupID)
+	if err != nil {
+		http.Error(w, "failed to get waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Waitlist{Count: len(entries), Entries: entries}
+	if !CanManage(role) {
+		resp.Entries = []WaitlistEntry{}
+		for _, e := range entries {
+			if e.UserID == user.ID {
+				resp.Entries = append(resp.Entries, e)
+			}
+		}
+	}
+	if resp.Entries == nil {
+		resp.Entries = []WaitlistEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DELETE /groups/{id}/waitlist — Leave the waitlist, giving up any spot held for you
+func (h *Handler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	groupID := parts[1]
+
+	if err := h.repo.LeaveWaitlist(r.Context(), groupID, user.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "you are not on the waitlist", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to leave waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	// A spot held for this user is free again
+	h.fillFromWaitlist(r.Context(), groupID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "left waitlist"})
+}
+
+// POST /groups/{id}/waitlist/claim — Take the spot held for you before the offer expires
+func (h *Handler) ClaimWaitlistSpot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 { // groups/{id}/waitlist/claim
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	groupID := parts[1]
+
+	if err := h.repo.ClaimWaitlistOffer(r.Context(), groupID, user.ID); err != nil {
+		if errors.Is(err, ErrNoWaitlistOffer) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		}
+		if errors.Is(err, ErrGroupClosed) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "group not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to join group", http.StatusInternalServerError)
+		return
+	}
+
+	h.postSystemMessage(r.Context(), groupID, user.ID, messages.KindMemberJoined, map[string]string{"user_id": user.ID, "from_waitlist": "true"})
+	h.memberJoined(r.Context(), groupID, user.ID, messages.JoinedFromWaitlist, "")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "joined group"})
+}
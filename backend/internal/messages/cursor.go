@@ -0,0 +1,115 @@
+package messages
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a keyset position over (created_at, id). Messages are ordered by
+// created_at and then by id so that rows sharing a timestamp are never skipped.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the opaque string form of the cursor handed out to clients.
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor previously produced by Cursor.Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: t, ID: id}, nil
+}
+
+// Page sizes for GET /messages/{threadId}.
+const (
+	DefaultMessagePageSize = 50
+	MaxMessagePageSize     = 100
+)
+
+// MessageQuery selects one page of a thread's history. Before and After are
+// exclusive bounds and at most one may be set; with neither, the newest page
+// is returned. Inclusive makes Before include the message at the cursor.
+type MessageQuery struct {
+	Before    *Cursor
+	After     *Cursor
+	Inclusive bool
+	Limit     int
+}
+
+// parseLimit reads the "limit" query parameter, falling back to defaultLimit.
+func parseLimit(r *http.Request, defaultLimit, maxLimit int) (int, error) {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return defaultLimit, nil
+	}
+	n, err := strconv.Atoi(limitStr)
+	if err != nil || n < 1 || n > maxLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	return n, nil
+}
+
+// parseCursor decodes the named query parameter; it returns nil if the parameter is absent.
+func parseCursor(r *http.Request, name string) (*Cursor, error) {
+	cursorStr := r.URL.Query().Get(name)
+	if cursorStr == "" {
+		return nil, nil
+	}
+	c, err := DecodeCursor(cursorStr)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// parseBefore reads the "before" query parameter. Besides a cursor it accepts
+// the RFC3339 timestamp sent by older clients, meaning every message before it.
+func parseBefore(r *http.Request) (*Cursor, error) {
+	if t, err := time.Parse(time.RFC3339, r.URL.Query().Get("before")); err == nil {
+		// The nil UUID sorts first, so the bound excludes every message at t
+		return &Cursor{CreatedAt: t, ID: uuid.Nil.String()}, nil
+	}
+	return parseCursor(r, "before")
+}
+
+// parsePage reads the "limit" and "cursor" query parameters shared by the
+// cursor-paginated endpoints.
+func parsePage(r *http.Request, defaultLimit, maxLimit int) (int, *Cursor, error) {
+	limit, err := parseLimit(r, defaultLimit, maxLimit)
+	if err != nil {
+		return 0, nil, err
+	}
+	after, err := parseCursor(r, "cursor")
+	if err != nil {
+		return 0, nil, err
+	}
+	return limit, after, nil
+}
@@ -57,7 +57,7 @@ func (h *Handler) ListThreads(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(threads)
 }
 
-// GET /messages/{threadId} — Get paginated messages for a thread.
+// GET /messages/{threadId}?before=|after=|around=&limit= — Page through a thread's messages.
 func (h *Handler) GetMessages(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -85,28 +85,215 @@ func (h *Handler) GetMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse pagination params
-	before := time.Now()
-	if beforeStr := r.URL.Query().Get("before"); beforeStr != "" {
-		if t, err := time.Parse(time.RFC3339, beforeStr); err == nil {
-			before = t
-		}
+	limit, err := parseLimit(r, DefaultMessagePageSize, MaxMessagePageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	limit := 50
-
-	msgs, err := h.repo.GetMessages(r.Context(), threadID, user.ID, before, limit)
+	before, err := parseBefore(r)
+	if err != nil {
+		http.Error(w, "invalid before cursor", http.StatusBadRequest)
+		return
+	}
+	after, err := parseCursor(r, "after")
 	if err != nil {
-		http.Error(w, "failed to get messages", http.StatusInternalServerError)
+		http.Error(w, "invalid after cursor", http.StatusBadRequest)
 		return
 	}
-	if msgs == nil {
+	aroundID := r.URL.Query().Get("around")
+	modes := 0
+	for _, set := range []bool{before != nil, after != nil, aroundID != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes > 1 {
+		http.Error(w, "only one of before, after or around may be set", http.StatusBadRequest)
+		return
+	}
+
+	// Each query fetches one extra row to learn whether another page exists.
+	var page MessagePage
+	var msgs []Message
+	switch {
+	case aroundID != "":
+		target, err := h.repo.GetMessage(r.Context(), aroundID, user.ID)
+		if err == sql.ErrNoRows || (err == nil && target.ThreadID != threadID) {
+			http.Error(w, "message not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to get messages", http.StatusInternalServerError)
+			return
+		}
+
+		// The target plus older messages fill the first half of the page, newer ones the rest
+		at := Cursor{CreatedAt: target.CreatedAt, ID: target.ID}
+		newerLimit := limit / 2
+		olderLimit := limit - newerLimit
+		older, err := h.repo.GetMessages(r.Context(), threadID, user.ID, MessageQuery{Before: &at, Inclusive: true, Limit: olderLimit + 1})
+		if err != nil {
+			http.Error(w, "failed to get messages", http.StatusInternalServerError)
+			return
+		}
+		newer, err := h.repo.GetMessages(r.Context(), threadID, user.ID, MessageQuery{After: &at, Limit: newerLimit + 1})
+		if err != nil {
+			http.Error(w, "failed to get messages", http.StatusInternalServerError)
+			return
+		}
+		if len(older) > olderLimit {
+			older = older[:olderLimit]
+			page.HasMoreBefore = true
+		}
+		if len(newer) > newerLimit {
+			newer = newer[len(newer)-newerLimit:]
+			page.HasMoreAfter = true
+		}
+		msgs = append(newer, older...)
+
+	case after != nil:
+		msgs, err = h.repo.GetMessages(r.Context(), threadID, user.ID, MessageQuery{After: after, Limit: limit + 1})
+		if err != nil {
+			http.Error(w, "failed to get messages", http.StatusInternalServerError)
+			return
+		}
+		if len(msgs) > limit {
+			msgs = msgs[len(msgs)-limit:]
+			page.HasMoreAfter = true
+		}
+		// The cursor came from an older message
+		page.HasMoreBefore = true
+
+	default:
+		msgs, err = h.repo.GetMessages(r.Context(), threadID, user.ID, MessageQuery{Before: before, Limit: limit + 1})
+		if err != nil {
+			http.Error(w, "failed to get messages", http.StatusInternalServerError)
+			return
+		}
+		if len(msgs) > limit {
+			msgs = msgs[:limit]
+			page.HasMoreBefore = true
+		}
+		page.HasMoreAfter = before != nil
+	}
+
+	if len(msgs) > 0 {
+		newest, oldest := msgs[0], msgs[len(msgs)-1]
+		page.AfterCursor = Cursor{CreatedAt: newest.CreatedAt, ID: newest.ID}.Encode()
+		page.BeforeCursor = Cursor{CreatedAt: oldest.CreatedAt, ID: oldest.ID}.Encode()
+	} else {
+		// Nothing new yet: hand the cursor back so the client can keep polling from it
+		if after != nil {
+			page.AfterCursor = after.Encode()
+		}
+		if before != nil {
+			page.BeforeCursor = before.Encode()
+		}
 		msgs = []Message{}
 	}
+	page.Messages = msgs
 
 	w.Header().Set("Content-Type", "application/json")
+	// Older clients expect a bare array; the page with its cursors is opt-in
+	if r.URL.Query().Get("format") == "page" {
+		json.NewEncoder(w).Encode(page)
+		return
+	}
 	json.NewEncoder(w).Encode(msgs)
 }
 
+// GET /messages/search?q=...&cursor=...&limit=... — Full-text search across the user's threads.
+func (h *Handler) SearchMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(query) < 2 {
+		http.Error(w, "q must be at least 2 characters", http.StatusBadRequest)
+		return
+	}
+	if len(query) > 200 {
+		http.Error(w, "q too long (max 200 chars)", http.StatusBadRequest)
+		return
+	}
+
+	limit, after, err := parsePage(r, 20, 50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	results, err := h.repo.SearchMessages(r.Context(), user.ID, query, after, limit+1)
+	if err != nil {
+		http.Error(w, "failed to search messages", http.StatusInternalServerError)
+		return
+	}
+
+	resp := SearchResponse{Results: results}
+	if len(results) > limit {
+		resp.Results = results[:limit]
+		resp.HasMore = true
+		last := resp.Results[limit-1]
+		resp.NextCursor = Cursor{CreatedAt: last.CreatedAt, ID: last.MessageID}.Encode()
+	}
+	if resp.Results == nil {
+		resp.Results = []SearchResult{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GET /me/mentions?cursor=...&limit=... — Messages that @mention the authenticated user.
+func (h *Handler) ListMentions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, after, err := parsePage(r, 20, 50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	msgs, err := h.repo.ListMentions(r.Context(), user.ID, after, limit+1)
+	if err != nil {
+		http.Error(w, "failed to list mentions", http.StatusInternalServerError)
+		return
+	}
+
+	resp := MentionsResponse{Messages: msgs}
+	if len(msgs) > limit {
+		resp.Messages = msgs[:limit]
+		resp.HasMore = true
+		last := resp.Messages[limit-1]
+		resp.NextCursor = Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	if resp.Messages == nil {
+		resp.Messages = []MentionedMessage{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
 // POST /messages — Send a message (HTTP fallback when WS is unavailable).
 func (h *Handler) SendMessage(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -126,47 +313,106 @@ func (h *Handler) SendMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ValidateContent(req.Content); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	msg, err := h.hub.DeliverMessage(r.Context(), user.ID, req)
+	if err != nil {
+		switch err {
+		case ErrContentEmpty, ErrContentTooLong, ErrContentRejected:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case ErrNotParticipant:
+			http.Error(w, "not a participant", http.StatusForbidden)
+		case ErrBlocked:
+			http.Error(w, "cannot send message to this user", http.StatusForbidden)
+		case ErrThreadReadOnly:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case ErrRequestLimitReached:
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			http.Error(w, "failed to send message", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	// Check participation
-	ok, err := h.repo.IsParticipant(r.Context(), req.ThreadID, user.ID)
-	if err != nil || !ok {
-		http.Error(w, "not a participant", http.StatusForbidden)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(msg)
+}
+
+// POST /messages/forward — Forward a message the caller can read into other threads.
+func (h *Handler) ForwardMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Check blocks
-	participants, err := h.repo.GetParticipantIDs(r.Context(), req.ThreadID)
-	if err != nil {
-		http.Error(w, "internal error", http.StatusInternalServerError)
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
-	for _, pid := range participants {
-		if pid == user.ID {
-			continue
-		}
-		if blocked, _ := h.repo.IsBlocked(r.Context(), user.ID, pid); blocked {
-			http.Error(w, "cannot send message to this user", http.StatusForbidden)
-			return
-		}
+
+	var req ForwardMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MessageID == "" {
+		http.Error(w, "message_id is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.ThreadIDs) == 0 || len(req.ThreadIDs) > MaxForwardTargets {
+		http.Error(w, "thread_ids must contain between 1 and 5 threads", http.StatusBadRequest)
+		return
 	}
 
-	msg, err := h.repo.CreateMessage(r.Context(), req.ThreadID, user.ID, req.Content, req.ReplyToID, req.IsForwarded)
+	// The caller must be able to read the source message
+	src, err := h.repo.GetMessage(r.Context(), req.MessageID, user.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			http.Error(w, "request message limit reached (10 messages)", http.StatusTooManyRequests)
+			http.Error(w, "message not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, "failed to send message", http.StatusInternalServerError)
+		http.Error(w, "failed to get message", http.StatusInternalServerError)
+		return
+	}
+
+	if src.IsSystem() {
+		http.Error(w, "system messages cannot be forwarded", http.StatusBadRequest)
+		return
+	}
+
+	srcThread, err := h.repo.GetThread(r.Context(), src.ThreadID)
+	if err != nil {
+		http.Error(w, "failed to get thread", http.StatusInternalServerError)
+		return
+	}
+	if srcThread.NoForward {
+		http.Error(w, ErrForwardingDisabled.Error(), http.StatusForbidden)
 		return
 	}
 
+	resp := ForwardResponse{Forwarded: []Message{}, Failed: []ForwardFailure{}}
+	seen := make(map[string]bool, len(req.ThreadIDs))
+	for _, threadID := range req.ThreadIDs {
+		if threadID == "" || seen[threadID] {
+			continue
+		}
+		seen[threadID] = true
+
+		msg, err := h.hub.ForwardMessage(r.Context(), user.ID, src, threadID)
+		if err != nil {
+			reason := "failed to forward message"
+			switch err {
+			case ErrNotParticipant:
+				reason = "not a participant"
+			case ErrBlocked:
+				reason = "cannot send message to this user"
+			case ErrRequestLimitReached, ErrContentRejected, ErrThreadReadOnly:
+				reason = err.Error()
+			}
+			resp.Failed = append(resp.Failed, ForwardFailure{ThreadID: threadID, Error: reason})
+			continue
+		}
+		resp.Forwarded = append(resp.Forwarded, msg)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(msg)
+	json.NewEncoder(w).Encode(resp)
 }
 
 // POST /messages/thread/direct — Get or create a 1:1 direct thread.
@@ -230,6 +476,19 @@ func (h *Handler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
 	}
 	messageID := parts[1]
 
+	msg, err := h.repo.GetMessage(r.Context(), messageID, user.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "message not found or not yours", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to delete message", http.StatusInternalServerError)
+		return
+	}
+	if !h.threadWritable(w, r, msg.ThreadID) {
+		return
+	}
+
 	threadID, err := h.repo.DeleteMessage(r.Context(), messageID, user.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -344,6 +603,57 @@ func (h *Handler) RegisterDeviceToken(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "token registered"})
 }
 
+// GET /messages/requests — List pending message requests and the user's request quota.
+// Incoming requests are returned by default; ?direction=sent lists outgoing ones.
+func (h *Handler) ListRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var sent bool
+	switch r.URL.Query().Get("direction") {
+	case "", "received":
+	case "sent":
+		sent = true
+	default:
+		http.Error(w, "direction must be received or sent", http.StatusBadRequest)
+		return
+	}
+
+	threads, err := h.repo.ListUserThreads(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "failed to list requests", http.StatusInternalServerError)
+		return
+	}
+
+	stats, err := h.repo.GetRequestStats(r.Context(), user.ID, time.Now())
+	if err != nil {
+		http.Error(w, "failed to load request quota", http.StatusInternalServerError)
+		return
+	}
+
+	requests := []ThreadSummary{}
+	for _, t := range threads {
+		if !t.IsRequest || t.IsRequester != sent {
+			continue
+		}
+		if t.OtherUserID != nil {
+			t.IsOnline = h.hub.IsOnline(*t.OtherUserID)
+		}
+		requests = append(requests, t)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RequestInbox{Requests: requests, Quota: stats.Quota()})
+}
+
 // POST /messages/threads/{id}/accept — Accept a request thread.
 func (h *Handler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -369,6 +679,9 @@ func (h *Handler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "not a participant", http.StatusForbidden)
 		return
 	}
+	if !h.requestRecipient(w, r, threadID, user.ID) {
+		return
+	}
 
 	if err := h.repo.AcceptRequest(r.Context(), threadID, user.ID); err != nil {
 		http.Error(w, "failed to accept request", http.StatusInternalServerError)
@@ -378,6 +691,22 @@ func (h *Handler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// requestRecipient checks that the user received the thread's pending
+// request and writes the error response if not. Only the recipient may accept
+// or decline, so a sender cannot clear their own request to dodge the throttle.
+func (h *Handler) requestRecipient(w http.ResponseWriter, r *http.Request, threadID, userID string) bool {
+	ok, err := h.repo.IsRequestRecipient(r.Context(), threadID, userID)
+	if err != nil {
+		http.Error(w, "failed to load request", http.StatusInternalServerError)
+		return false
+	}
+	if !ok {
+		http.Error(w, "only the recipient can respond to this request", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
 // POST /messages/threads/{id}/decline — Decline a request thread.
 func (h *Handler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -403,8 +732,20 @@ func (h *Handler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "not a participant", http.StatusForbidden)
 		return
 	}
+	if !h.requestRecipient(w, r, threadID, user.ID) {
+		return
+	}
+
+	// Body is optional; {"block": true} also blocks the sender.
+	var req DeclineRequestBody
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
 
-	if err := h.repo.DeclineRequest(r.Context(), threadID, user.ID); err != nil {
+	if err := h.repo.DeclineRequest(r.Context(), threadID, user.ID, req.Block); err != nil {
 		http.Error(w, "failed to decline request", http.StatusInternalServerError)
 		return
 	}
@@ -412,3 +753,465 @@ func (h *Handler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// GET /messages/{threadId}/pins — List pinned messages in a thread.
+func (h *Handler) ListPins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// /messages/{threadId}/pins
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	threadID := parts[1]
+
+	ok, err := h.repo.IsParticipant(r.Context(), threadID, user.ID)
+	if err != nil || !ok {
+		http.Error(w, "not a participant", http.StatusForbidden)
+		return
+	}
+
+	pins, err := h.repo.GetPinnedMessages(r.Context(), threadID)
+	if err != nil {
+		http.Error(w, "failed to get pinned messages", http.StatusInternalServerError)
+		return
+	}
+	if pins == nil {
+		pins = []PinnedMessage{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pins)
+}
+
+// POST /messages/{threadId}/pins — Pin a message (group admin only).
+func (h *Handler) PinMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	threadID := parts[1]
+
+	var req PinMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MessageID == "" {
+		http.Error(w, "message_id is required", http.StatusBadRequest)
+		return
+	}
+
+	ok, err := h.repo.CanManageThread(r.Context(), threadID, user.ID)
+	if err != nil || !ok {
+		http.Error(w, "only the group admin can pin messages", http.StatusForbidden)
+		return
+	}
+	if !h.threadWritable(w, r, threadID) {
+		return
+	}
+
+	if err := h.repo.PinMessage(r.Context(), threadID, req.MessageID, user.ID); err != nil {
+		switch err {
+		case ErrMessageNotInThread:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case ErrPinLimitReached:
+			http.Error(w, err.Error(), http.StatusConflict)
+		case ErrThreadReadOnly:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "failed to pin message", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	pins, err := h.repo.GetPinnedMessages(r.Context(), threadID)
+	if err != nil {
+		http.Error(w, "failed to get pinned messages", http.StatusInternalServerError)
+		return
+	}
+	if pins == nil {
+		pins = []PinnedMessage{}
+	}
+	h.hub.BroadcastPinsUpdated(r.Context(), threadID, pins)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pins)
+}
+
+// DELETE /messages/{threadId}/pins/{messageId} — Unpin a message (group admin only).
+func (h *Handler) UnpinMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// /messages/{threadId}/pins/{messageId}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	threadID, messageID := parts[1], parts[3]
+
+	ok, err := h.repo.CanManageThread(r.Context(), threadID, user.ID)
+	if err != nil || !ok {
+		http.Error(w, "only the group admin can unpin messages", http.StatusForbidden)
+		return
+	}
+	if !h.threadWritable(w, r, threadID) {
+		return
+	}
+
+	if err := h.repo.UnpinMessage(r.Context(), threadID, messageID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "message is not pinned", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to unpin message", http.StatusInternalServerError)
+		return
+	}
+
+	pins, err := h.repo.GetPinnedMessages(r.Context(), threadID)
+	if err == nil {
+		if pins == nil {
+			pins = []PinnedMessage{}
+		}
+		h.hub.BroadcastPinsUpdated(r.Context(), threadID, pins)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// threadWritable writes a 403 and returns false if the thread is read-only.
+func (h *Handler) threadWritable(w http.ResponseWriter, r *http.Request, threadID string) bool {
+	if _, err := writableThread(r.Context(), h.repo, threadID); err != nil {
+		if err == ErrThreadReadOnly {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return false
+		}
+		http.Error(w, "failed to get thread", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// PUT /messages/{threadId}/settings — Update the caller's notification settings for a thread.
+func (h *Handler) UpdateThreadSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// /messages/{threadId}/settings
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	threadID := parts[1]
+
+	var req UpdateThreadSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.repo.GetThreadSettings(r.Context(), threadID, user.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "not a participant", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "failed to get thread settings", http.StatusInternalServerError)
+		return
+	}
+
+	if req.MuteHours != nil && (req.Muted == nil || !*req.Muted) {
+		http.Error(w, "mute_hours requires muted=true", http.StatusBadRequest)
+		return
+	}
+	if req.Muted != nil {
+		settings.IsMuted = *req.Muted
+		settings.MutedUntil = nil
+		if req.MuteHours != nil {
+			if *req.MuteHours < 1 || *req.MuteHours > MaxMuteHours {
+				http.Error(w, "mute_hours must be between 1 and 8760", http.StatusBadRequest)
+				return
+			}
+			until := time.Now().Add(time.Duration(*req.MuteHours) * time.Hour)
+			settings.MutedUntil = &until
+		}
+	}
+
+	if req.NotificationLevel != nil {
+		switch *req.NotificationLevel {
+		case NotifyAll:
+		case NotifyMentions:
+			thread, err := h.repo.GetThread(r.Context(), threadID)
+			if err != nil {
+				http.Error(w, "failed to get thread", http.StatusInternalServerError)
+				return
+			}
+			if thread.Type != "group" {
+				http.Error(w, "mentions-only mode is only available in group chats", http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(w, "notification_level must be 'all' or 'mentions'", http.StatusBadRequest)
+			return
+		}
+		settings.NotificationLevel = *req.NotificationLevel
+	}
+
+	if req.NoForward != nil {
+		ok, err := h.repo.CanManageThread(r.Context(), threadID, user.ID)
+		if err != nil || !ok {
+			http.Error(w, "only the group admin can change forwarding", http.StatusForbidden)
+			return
+		}
+	}
+
+	ttlSeconds := settings.MessageTTLSeconds
+	if req.MessageTTL != nil {
+		var ok bool
+		if ttlSeconds, ok = MessageTTLOptions[*req.MessageTTL]; !ok {
+			http.Error(w, "message_ttl must be '24h', '7d' or 'off'", http.StatusBadRequest)
+			return
+		}
+		// Either side of a direct chat may set the timer; in groups only the admin
+		thread, err := h.repo.GetThread(r.Context(), threadID)
+		if err != nil {
+			http.Error(w, "failed to get thread", http.StatusInternalServerError)
+			return
+		}
+		if thread.Type == "group" {
+			ok, err := h.repo.CanManageThread(r.Context(), threadID, user.ID)
+			if err != nil || !ok {
+				http.Error(w, "only the group admin can change disappearing messages", http.StatusForbidden)
+				return
+			}
+		}
+	}
+
+	// Muting stays available in a read-only chat; thread-wide settings do not
+	if (req.NoForward != nil || req.MessageTTL != nil) && !h.threadWritable(w, r, threadID) {
+		return
+	}
+
+	if err := h.repo.UpdateThreadSettings(r.Context(), threadID, user.ID, settings); err != nil {
+		http.Error(w, "failed to update thread settings", http.StatusInternalServerError)
+		return
+	}
+	if req.NoForward != nil {
+		if err := h.repo.SetThreadNoForward(r.Context(), threadID, *req.NoForward); err != nil {
+			http.Error(w, "failed to update thread settings", http.StatusInternalServerError)
+			return
+		}
+		settings.NoForward = *req.NoForward
+	}
+	if req.MessageTTL != nil {
+		if err := h.repo.SetThreadMessageTTL(r.Context(), threadID, ttlSeconds); err != nil {
+			http.Error(w, "failed to update thread settings", http.StatusInternalServerError)
+			return
+		}
+		settings.MessageTTLSeconds = ttlSeconds
+	}
+
+	// Report the effective state so an already expired mute reads as unmuted
+	if !settings.MutedAt(time.Now()) {
+		settings.IsMuted = false
+		settings.MutedUntil = nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// GET /messages/{threadId}/{messageId}/replies — A message and its full reply subtree.
+func (h *Handler) GetReplies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// /messages/{threadId}/{messageId}/replies
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+	threadID, messageID := parts[1], parts[2]
+
+	ok, err := h.repo.IsParticipant(r.Context(), threadID, user.ID)
+	if err != nil || !ok {
+		http.Error(w, "not a participant", http.StatusForbidden)
+		return
+	}
+
+	msgs, err := h.repo.GetReplyTree(r.Context(), threadID, messageID, user.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "message not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to get replies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReplyTree{Root: msgs[0], Replies: append([]Message{}, msgs[1:]...)})
+}
+
+// POST /messages/schedule — Queue a message to be sent at send_at.
+func (h *Handler) ScheduleMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req ScheduleMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ThreadID == "" {
+		http.Error(w, "thread_id and send_at are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := ValidateContent(req.Content); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	if !req.SendAt.After(now) {
+		http.Error(w, "send_at must be in the future", http.StatusBadRequest)
+		return
+	}
+	if req.SendAt.After(now.Add(MaxScheduleAhead)) {
+		http.Error(w, "send_at must be within 30 days", http.StatusBadRequest)
+		return
+	}
+
+	ok, err := h.repo.IsParticipant(r.Context(), req.ThreadID, user.ID)
+	if err != nil || !ok {
+		http.Error(w, "not a participant", http.StatusForbidden)
+		return
+	}
+	if !h.threadWritable(w, r, req.ThreadID) {
+		return
+	}
+
+	sm, err := h.repo.CreateScheduledMessage(r.Context(), ScheduledMessage{
+		ThreadID:  req.ThreadID,
+		SenderID:  user.ID,
+		Content:   req.Content,
+		ReplyToID: req.ReplyToID,
+		SendAt:    req.SendAt,
+	})
+	if err != nil {
+		http.Error(w, "failed to schedule message", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sm)
+}
+
+// GET /messages/scheduled — List the user's pending scheduled messages.
+func (h *Handler) ListScheduledMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	scheduled, err := h.repo.ListScheduledMessages(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "failed to list scheduled messages", http.StatusInternalServerError)
+		return
+	}
+	if scheduled == nil {
+		scheduled = []ScheduledMessage{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scheduled)
+}
+
+// DELETE /messages/scheduled/{id} — Cancel a pending scheduled message.
+func (h *Handler) CancelScheduledMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// /messages/scheduled/{id}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		http.Error(w, "invalid URL", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.CancelScheduledMessage(r.Context(), parts[2], user.ID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "scheduled message not found or already sent", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to cancel scheduled message", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/muskan953/college-Hop/internal/moderation"
 	"github.com/muskan953/college-Hop/pkg/notify"
 )
 
@@ -25,9 +26,10 @@ type Hub struct {
 	// Unregister requests from clients.
 	unregister chan *Client
 
-	mu       sync.RWMutex
-	repo     Repository
-	notifier *notify.Notifier
+	mu        sync.RWMutex
+	repo      Repository
+	notifier  *notify.Notifier
+	moderator *moderation.Moderator
 }
 
 // broadcastMsg carries a message plus sender context through the hub.
@@ -36,8 +38,11 @@ type broadcastMsg struct {
 	incoming WSIncoming
 }
 
-// NewHub creates a new Hub.
-func NewHub(repo Repository, notifier *notify.Notifier) *Hub {
+// NewHub creates a new Hub. A nil moderator applies only the built-in rules.
+func NewHub(repo Repository, notifier *notify.Notifier, moderator *moderation.Moderator) *Hub {
+	if moderator == nil {
+		moderator = moderation.New()
+	}
 	return &Hub{
 		clients:    make(map[string]*Client),
 		broadcast:  make(chan *broadcastMsg, 256),
@@ -45,6 +50,7 @@ func NewHub(repo Repository, notifier *notify.Notifier) *Hub {
 		unregister: make(chan *Client),
 		repo:       repo,
 		notifier:   notifier,
+		moderator:  moderator,
 	}
 }
 
@@ -97,6 +103,19 @@ func (h *Hub) Run() {
 	}
 }
 
+// Disconnect closes the user's WebSocket connection, if any. The client's
+// read pump then unregisters it as usual. Used when an account is suspended
+// or blocked so an open socket cannot keep sending.
+func (h *Hub) Disconnect(userID string) {
+	h.mu.RLock()
+	client, ok := h.clients[userID]
+	h.mu.RUnlock()
+	if ok {
+		client.conn.Close()
+		log.Printf("[Hub] Disconnected user %s", userID)
+	}
+}
+
 // IsOnline checks if a user has an active WebSocket connection.
 func (h *Hub) IsOnline(userID string) bool {
 	h.mu.RLock()
@@ -119,30 +138,149 @@ func (h *Hub) handleBroadcast(bMsg *broadcastMsg) {
 	}
 }
 
-// handleMessage persists a message and delivers it to thread participants.
+// handleMessage delivers a message received over WebSocket and confirms it to the sender.
 func (h *Hub) handleMessage(ctx context.Context, bMsg *broadcastMsg) {
-	threadID := bMsg.incoming.ThreadID
 	senderID := bMsg.senderID
-	content := bMsg.incoming.Content
 
-	// Validate content
-	if err := ValidateContent(content); err != nil {
-		h.sendError(senderID, err.Error())
+	msg, err := h.DeliverMessage(ctx, senderID, SendMessageRequest{
+		ThreadID:  bMsg.incoming.ThreadID,
+		Content:   bMsg.incoming.Content,
+		ReplyToID: bMsg.incoming.ReplyToID,
+	})
+	if err != nil {
+		switch err {
+		case ErrContentEmpty, ErrContentTooLong, ErrRequestLimitReached, ErrContentRejected, ErrThreadReadOnly:
+			h.sendError(senderID, err.Error())
+		case ErrNotParticipant:
+			h.sendError(senderID, "not a participant of this thread")
+		case ErrBlocked:
+			h.sendError(senderID, "cannot send message to this user")
+		default:
+			log.Printf("[Hub] Failed to deliver message: %v", err)
+			h.sendError(senderID, "failed to send message")
+		}
 		return
 	}
 
-	// Check participation
+	// Send confirmation to sender
+	h.SendToUser(senderID, WSOutgoing{
+		Type:    "message_sent",
+		Payload: WSMessageSent{MessageID: msg.ID, ThreadID: msg.ThreadID},
+	})
+}
+
+// DeliverMessage validates, persists and fans out a message. Both the WebSocket
+// and the HTTP send paths go through here so they behave identically.
+func (h *Hub) DeliverMessage(ctx context.Context, senderID string, req SendMessageRequest) (Message, error) {
+	if err := ValidateContent(req.Content); err != nil {
+		return Message{}, err
+	}
+
+	participants, err := h.authorizeSend(ctx, req.ThreadID, senderID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	thread, err := writableThread(ctx, h.repo, req.ThreadID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	verdict := h.moderator.Check(moderation.Input{
+		Field:         moderation.FieldMessage,
+		Text:          req.Content,
+		RequestThread: thread.IsRequest,
+	})
+	if verdict.Rejected() {
+		return Message{}, ErrContentRejected
+	}
+
+	// Only ForwardMessage marks a message as forwarded, from a real source
+	msg, err := h.repo.CreateMessage(ctx, req.ThreadID, senderID, verdict.Text, req.ReplyToID, false)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Message{}, ErrRequestLimitReached
+		}
+		return Message{}, err
+	}
+
+	h.moderator.FlagForReview(ctx, verdict, moderation.Flag{
+		TargetType: "message",
+		TargetID:   msg.ID,
+		UserID:     senderID,
+		ThreadID:   &msg.ThreadID,
+		Content:    msg.Content,
+	})
+
+	// Mentions are only parsed in group threads
+	if thread.Type == "group" {
+		msg.Mentions = h.saveMentions(ctx, msg)
+	}
+
+	h.fanOut(ctx, msg, participants)
+	return msg, nil
+}
+
+// ForwardMessage copies src into threadID on behalf of senderID and fans it out.
+// The caller is responsible for checking that src may be forwarded at all.
+func (h *Hub) ForwardMessage(ctx context.Context, senderID string, src Message, threadID string) (Message, error) {
+	participants, err := h.authorizeSend(ctx, threadID, senderID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	// Re-check the content: the target may be a request thread, or the rules may have changed
+	thread, err := writableThread(ctx, h.repo, threadID)
+	if err != nil {
+		return Message{}, err
+	}
+	verdict := h.moderator.Check(moderation.Input{
+		Field:         moderation.FieldMessage,
+		Text:          src.Content,
+		RequestThread: thread.IsRequest,
+	})
+	if verdict.Rejected() {
+		return Message{}, ErrContentRejected
+	}
+	src.Content = verdict.Text
+
+	msg, err := h.repo.CreateForwardedMessage(ctx, threadID, senderID, src)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Message{}, ErrRequestLimitReached
+		}
+		return Message{}, err
+	}
+
+	h.fanOut(ctx, msg, participants)
+	return msg, nil
+}
+
+// writableThread loads a thread that is about to change, or returns
+// ErrThreadReadOnly for a read-only chat. Sends, forwards, scheduled sends,
+// pins, deletes and thread-wide settings all check it; system messages posted
+// by the server do not, so a group's lifecycle can still be announced.
+func writableThread(ctx context.Context, repo Repository, threadID string) (Thread, error) {
+	thread, err := repo.GetThread(ctx, threadID)
+	if err != nil {
+		return Thread{}, err
+	}
+	if thread.ReadOnly {
+		return Thread{}, ErrThreadReadOnly
+	}
+	return thread, nil
+}
+
+// authorizeSend checks that the sender may post in the thread and returns its participants.
+func (h *Hub) authorizeSend(ctx context.Context, threadID, senderID string) ([]string, error) {
 	ok, err := h.repo.IsParticipant(ctx, threadID, senderID)
 	if err != nil || !ok {
-		h.sendError(senderID, "not a participant of this thread")
-		return
+		return nil, ErrNotParticipant
 	}
 
-	// Get participants to check blocks
 	participants, err := h.repo.GetParticipantIDs(ctx, threadID)
 	if err != nil {
-		h.sendError(senderID, "internal error")
-		return
+		return nil, err
 	}
 
 	// Check if sender is blocked by any participant (for direct chats)
@@ -150,45 +288,59 @@ func (h *Hub) handleMessage(ctx context.Context, bMsg *broadcastMsg) {
 		if pid == senderID {
 			continue
 		}
-		blocked, _ := h.repo.IsBlocked(ctx, senderID, pid)
-		if blocked {
-			h.sendError(senderID, "cannot send message to this user")
-			return
+		if blocked, _ := h.repo.IsBlocked(ctx, senderID, pid); blocked {
+			return nil, ErrBlocked
 		}
 	}
+	return participants, nil
+}
 
-	// Persist the message
-	msg, err := h.repo.CreateMessage(ctx, threadID, senderID, content, bMsg.incoming.ReplyToID, bMsg.incoming.IsForwarded)
+// saveMentions parses @mentions of other participants and stores them.
+// Mentions are best-effort: a failure here never blocks the message itself.
+func (h *Hub) saveMentions(ctx context.Context, msg Message) []Mention {
+	members, err := h.repo.GetParticipants(ctx, msg.ThreadID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			h.sendError(senderID, "request message limit reached (10 messages)")
-			return
+		return nil
+	}
+	others := members[:0]
+	for _, p := range members {
+		if p.UserID != msg.SenderID {
+			others = append(others, p)
 		}
-		log.Printf("[Hub] Failed to persist message: %v", err)
-		h.sendError(senderID, "failed to send message")
-		return
 	}
 
-	// Send confirmation to sender
-	h.SendToUser(senderID, WSOutgoing{
-		Type:    "message_sent",
-		Payload: WSMessageSent{MessageID: msg.ID, ThreadID: msg.ThreadID},
-	})
+	mentions := ParseMentions(msg.Content, others)
+	if err := h.repo.SaveMentions(ctx, msg.ID, mentions); err != nil {
+		log.Printf("[Hub] Failed to save mentions for message %s: %v", msg.ID, err)
+		return nil
+	}
+	return mentions
+}
+
+// fanOut delivers a persisted message to every other participant:
+// online users over WebSocket, offline users via push.
+func (h *Hub) fanOut(ctx context.Context, msg Message, participants []string) {
+	mentioned := make(map[string]bool, len(msg.Mentions))
+	for _, mn := range msg.Mentions {
+		mentioned[mn.UserID] = true
+	}
+
+	// Pushes outlive the request that triggered them
+	pushCtx := context.WithoutCancel(ctx)
 
-	// Deliver to all other participants (online via WS, offline via push)
 	newMsgPayload := WSOutgoing{
 		Type:    "new_message",
 		Payload: WSNewMessage{Message: msg},
 	}
 	for _, pid := range participants {
-		if pid == senderID {
+		if pid == msg.SenderID {
 			continue
 		}
 		if h.IsOnline(pid) {
 			h.SendToUser(pid, newMsgPayload)
 		} else {
 			// User is offline — send push notification
-			go h.sendPushNotification(ctx, pid, msg)
+			go h.sendPushNotification(pushCtx, pid, msg, mentioned[pid])
 		}
 	}
 }
@@ -244,6 +396,18 @@ func (h *Hub) SendToUser(userID string, msg WSOutgoing) {
 	}
 }
 
+// SendToUsers sends a WSOutgoing message to each of the given users that is
+// online. Duplicate IDs receive it once.
+func (h *Hub) SendToUsers(userIDs []string, msg WSOutgoing) {
+	seen := make(map[string]bool, len(userIDs))
+	for _, uid := range userIDs {
+		if !seen[uid] {
+			seen[uid] = true
+			h.SendToUser(uid, msg)
+		}
+	}
+}
+
 // sendError sends an error message to a specific user.
 func (h *Hub) sendError(userID string, errMsg string) {
 	h.SendToUser(userID, WSOutgoing{
@@ -253,11 +417,22 @@ func (h *Hub) sendError(userID string, errMsg string) {
 }
 
 // sendPushNotification sends a push notification to an offline user.
-func (h *Hub) sendPushNotification(ctx context.Context, userID string, msg Message) {
+// Mentions break through thread mute and mentions-only mode, but never
+// through the user's account-wide push preferences.
+func (h *Hub) sendPushNotification(ctx context.Context, userID string, msg Message, mentioned bool) {
 	if h.notifier == nil {
 		return
 	}
 
+	prefs, err := h.repo.GetPushPreferences(ctx, userID)
+	if err != nil || !prefs.AllowsMessagePush() {
+		return
+	}
+	settings, err := h.repo.GetThreadSettings(ctx, msg.ThreadID, userID)
+	if err != nil || !settings.AllowsPush(time.Now(), mentioned) {
+		return
+	}
+
 	tokens, err := h.repo.GetDeviceTokens(ctx, userID)
 	if err != nil || len(tokens) == 0 {
 		return
@@ -269,22 +444,29 @@ func (h *Hub) sendPushNotification(ctx context.Context, userID string, msg Messa
 		body = body[:97] + "..."
 	}
 
+	title := msg.SenderName
 	data := map[string]string{
 		"type":      "new_message",
 		"thread_id": msg.ThreadID,
 		"sender_id": msg.SenderID,
 	}
+	if mentioned {
+		title = msg.SenderName + " mentioned you"
+		data["mentioned"] = "true"
+	}
 
-	h.notifier.SendToMany(ctx, tokens, msg.SenderName, body, data)
+	h.notifier.SendToMany(ctx, tokens, title, body, data)
 }
 
 // SendNotification dispatches an alert via WebSocket (for in-app UI) AND FCM (for system drops).
 func (h *Hub) SendNotification(ctx context.Context, userID, title, body string, data map[string]string) {
-	// 1. Unconditionally dispatch raw FCM to guarantee background delivery
+	// 1. Dispatch raw FCM to guarantee background delivery, unless the user turned push off
 	if h.notifier != nil {
-		tokens, err := h.repo.GetDeviceTokens(ctx, userID)
-		if err == nil && len(tokens) > 0 {
-			h.notifier.SendToMany(ctx, tokens, title, body, data)
+		if prefs, err := h.repo.GetPushPreferences(ctx, userID); err == nil && prefs.PushNotifications {
+			tokens, err := h.repo.GetDeviceTokens(ctx, userID)
+			if err == nil && len(tokens) > 0 {
+				h.notifier.SendToMany(ctx, tokens, title, body, data)
+			}
 		}
 	}
 
@@ -304,6 +486,29 @@ func (h *Hub) SendNotification(ctx context.Context, userID, title, body string,
 	}
 }
 
+// PostSystemMessage stores a system message in a group thread and delivers it to
+// online participants. System messages don't trigger push notifications.
+func (h *Hub) PostSystemMessage(ctx context.Context, threadID, actorID, kind string, metadata map[string]string) (Message, error) {
+	msg, err := h.repo.CreateSystemMessage(ctx, threadID, actorID, kind, metadata)
+	if err != nil {
+		return Message{}, err
+	}
+
+	participants, err := h.repo.GetParticipantIDs(ctx, threadID)
+	if err != nil {
+		return msg, nil
+	}
+
+	payload := WSOutgoing{
+		Type:    "new_message",
+		Payload: WSNewMessage{Message: msg},
+	}
+	for _, pid := range participants {
+		h.SendToUser(pid, payload)
+	}
+	return msg, nil
+}
+
 // BroadcastMessageDeleted notifies thread participants that a message was deleted.
 func (h *Hub) BroadcastMessageDeleted(ctx context.Context, threadID, messageID string) {
 	participants, err := h.repo.GetParticipantIDs(ctx, threadID)
@@ -324,6 +529,23 @@ func (h *Hub) BroadcastMessageDeleted(ctx context.Context, threadID, messageID s
 	}
 }
 
+// BroadcastPinsUpdated sends the thread's current pin list to every participant.
+func (h *Hub) BroadcastPinsUpdated(ctx context.Context, threadID string, pins []PinnedMessage) {
+	participants, err := h.repo.GetParticipantIDs(ctx, threadID)
+	if err != nil {
+		return
+	}
+
+	payload := WSOutgoing{
+		Type:    "pins_updated",
+		Payload: WSPinsUpdated{ThreadID: threadID, Pins: pins},
+	}
+
+	for _, pid := range participants {
+		h.SendToUser(pid, payload)
+	}
+}
+
 // BroadcastUserPresence notifies all users that share a thread with the given user about their status change.
 func (h *Hub) BroadcastUserPresence(userID string, isOnline bool) {
 	ctx := context.Background()
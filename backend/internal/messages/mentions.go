@@ -0,0 +1,123 @@
+package messages
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Mention marks an "@Name" span in a message's content that refers to a thread participant.
+// Offset and Length are measured in UTF-16 code units (not bytes) so the Flutter
+// client can index its Dart strings directly. The span includes the "@".
+type Mention struct {
+	UserID string `json:"user_id"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+}
+
+// Participant is a thread member that can be mentioned by name.
+type Participant struct {
+	UserID string
+	Name   string
+}
+
+// ParseMentions finds "@Full Name" references to the given participants.
+// The longest full name wins ("@Riya Sharma" over "@Riya"); a bare first name
+// only counts when exactly one participant has it. Matching is case-insensitive
+// and an "@" preceded by a letter or digit (e.g. an email address) is ignored.
+func ParseMentions(content string, participants []Participant) []Mention {
+	var mentions []Mention
+	offset := 0 // UTF-16 offset of content[i]
+	prev := rune(-1)
+
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		if r == '@' && !isNameRune(prev) {
+			if userID, n := matchParticipant(content[i+size:], participants); n > 0 {
+				matched := content[i : i+size+n]
+				length := utf16Len(matched)
+				mentions = append(mentions, Mention{UserID: userID, Offset: offset, Length: length})
+				lastRune, _ := utf8.DecodeLastRuneInString(matched)
+				i += size + n
+				offset += length
+				prev = lastRune
+				continue
+			}
+		}
+		offset += utf16.RuneLen(r)
+		prev = r
+		i += size
+	}
+	return mentions
+}
+
+// matchParticipant returns the participant referenced at the start of s and the
+// number of bytes of s the reference consumes.
+func matchParticipant(s string, participants []Participant) (string, int) {
+	bestID, bestLen := "", 0
+	for _, p := range participants {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			continue
+		}
+		if n := prefixFold(s, name); n > bestLen {
+			bestID, bestLen = p.UserID, n
+		}
+	}
+	if bestLen > 0 {
+		return bestID, bestLen
+	}
+
+	// Fall back to a unique first name
+	var firstID string
+	firstLen, matches := 0, 0
+	for _, p := range participants {
+		first, _, _ := strings.Cut(strings.TrimSpace(p.Name), " ")
+		if first == "" {
+			continue
+		}
+		if n := prefixFold(s, first); n > 0 {
+			matches++
+			firstID, firstLen = p.UserID, n
+		}
+	}
+	if matches == 1 {
+		return firstID, firstLen
+	}
+	return "", 0
+}
+
+// prefixFold reports how many bytes of s match prefix case-insensitively,
+// or 0 if s does not start with prefix followed by a word boundary.
+func prefixFold(s, prefix string) int {
+	i := 0
+	for _, pr := range prefix {
+		if i >= len(s) {
+			return 0
+		}
+		sr, size := utf8.DecodeRuneInString(s[i:])
+		if sr != pr && unicode.ToLower(sr) != unicode.ToLower(pr) {
+			return 0
+		}
+		i += size
+	}
+	if i < len(s) {
+		if next, _ := utf8.DecodeRuneInString(s[i:]); isNameRune(next) {
+			return 0
+		}
+	}
+	return i
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
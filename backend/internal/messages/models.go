@@ -10,6 +10,9 @@ type Thread struct {
 	CreatedAt           time.Time `json:"created_at"`
 	IsRequest           bool      `json:"is_request"`
 	RequestMessageCount int       `json:"request_message_count"`
+	NoForward           bool      `json:"no_forward"`
+	MessageTTLSeconds   int       `json:"message_ttl_seconds"` // 0 when disappearing messages are off
+	ReadOnly            bool      `json:"read_only"`           // set when an archived group's chat is closed
 }
 
 // ThreadSummary is returned by ListUserThreads for the thread list screen.
@@ -29,6 +32,17 @@ type ThreadSummary struct {
 	IsRequest           bool      `json:"is_request"`
 	RequestMessageCount int       `json:"request_message_count"`
 	IsRequester         bool      `json:"is_requester"`
+	// Most recently pinned message, if any (group threads only).
+	PinnedMessageID      *string `json:"pinned_message_id"`
+	PinnedMessageContent *string `json:"pinned_message_content"`
+	// Caller's notification settings for this thread
+	IsMuted           bool       `json:"is_muted"`
+	MutedUntil        *time.Time `json:"muted_until"`
+	NotificationLevel string     `json:"notification_level"`
+	// Thread-wide options
+	NoForward         bool `json:"no_forward"`
+	MessageTTLSeconds int  `json:"message_ttl_seconds"` // disappearing message timer; 0 when off
+	ReadOnly          bool `json:"read_only"`           // no new messages; the group was archived
 }
 
 // Message represents a single chat message.
@@ -43,6 +57,103 @@ type Message struct {
 	IsForwarded     bool      `json:"is_forwarded"`
 	ReplyToContent  *string   `json:"reply_to_content,omitempty"`
 	ReplyToSender   *string   `json:"reply_to_sender,omitempty"`
+	Mentions        []Mention `json:"mentions,omitempty"`
+	ReplyCount      int       `json:"reply_count"` // direct replies; populated by GetMessages
+	// Provenance of a forwarded message; always points at the first message in a forward chain
+	OriginalMessageID  *string `json:"original_message_id,omitempty"`
+	OriginalSenderID   *string `json:"original_sender_id,omitempty"`
+	OriginalSenderName *string `json:"original_sender_name,omitempty"`
+	// Set when the thread had disappearing messages on at send time
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// "text" for user messages, otherwise a system message kind (see system.go)
+	Kind     string            `json:"kind"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ExpiredMessage identifies a disappearing message removed by the Sweeper.
+type ExpiredMessage struct {
+	ID       string
+	ThreadID string
+}
+
+// PinnedMessage is a message pinned to the top of a group thread.
+type PinnedMessage struct {
+	Message
+	PinnedBy string    `json:"pinned_by"`
+	PinnedAt time.Time `json:"pinned_at"`
+}
+
+// Scheduled message statuses.
+const (
+	ScheduledPending   = "pending"
+	ScheduledSending   = "sending" // claimed by the dispatcher
+	ScheduledSent      = "sent"
+	ScheduledCancelled = "cancelled"
+	ScheduledFailed    = "failed"
+)
+
+// ScheduledMessage is a message queued to be sent at SendAt.
+type ScheduledMessage struct {
+	ID        string    `json:"id"`
+	ThreadID  string    `json:"thread_id"`
+	SenderID  string    `json:"sender_id"`
+	Content   string    `json:"content"`
+	ReplyToID *string   `json:"reply_to_id,omitempty"`
+	SendAt    time.Time `json:"send_at"`
+	Status    string    `json:"status"`
+	MessageID *string   `json:"message_id,omitempty"` // set once sent
+	Error     *string   `json:"error,omitempty"`      // set if delivery failed
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReplyTree is returned by GET /messages/{threadId}/{messageId}/replies.
+// Replies are flattened oldest first; rebuild nesting from reply_to_id.
+type ReplyTree struct {
+	Root    Message   `json:"root"`
+	Replies []Message `json:"replies"`
+}
+
+// MentionedMessage is an entry in the GET /me/mentions inbox.
+type MentionedMessage struct {
+	Message
+	ThreadName string `json:"thread_name"`
+}
+
+// MentionsResponse is returned by GET /me/mentions.
+type MentionsResponse struct {
+	Messages   []MentionedMessage `json:"messages"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	HasMore    bool               `json:"has_more"`
+}
+
+// SearchResult is a single hit returned by GET /messages/search.
+type SearchResult struct {
+	MessageID  string    `json:"message_id"`
+	ThreadID   string    `json:"thread_id"`
+	ThreadType string    `json:"thread_type"`
+	ThreadName string    `json:"thread_name"`
+	SenderID   string    `json:"sender_id"`
+	SenderName string    `json:"sender_name"`
+	Snippet    string    `json:"snippet"` // matched terms are wrapped in <mark></mark>
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// MessagePage is returned by GET /messages/{threadId}?format=page. Messages are newest first.
+// BeforeCursor/AfterCursor point at the oldest/newest message on the page and are
+// passed back as before= or after= to keep paging in that direction.
+type MessagePage struct {
+	Messages      []Message `json:"messages"`
+	BeforeCursor  string    `json:"before_cursor,omitempty"`
+	AfterCursor   string    `json:"after_cursor,omitempty"`
+	HasMoreBefore bool      `json:"has_more_before"`
+	HasMoreAfter  bool      `json:"has_more_after"`
+}
+
+// SearchResponse is returned by GET /messages/search.
+type SearchResponse struct {
+	Results    []SearchResult `json:"results"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	HasMore    bool           `json:"has_more"`
 }
 
 // --- Request DTOs ---
@@ -52,7 +163,15 @@ type SendMessageRequest struct {
 	ThreadID    string  `json:"thread_id"`
 	Content     string  `json:"content"`
 	ReplyToID   *string `json:"reply_to_id,omitempty"`
-	IsForwarded bool    `json:"is_forwarded"`
+	IsForwarded bool    `json:"is_forwarded"` // ignored; forwards go through POST /messages/forward
+}
+
+// ScheduleMessageRequest is the payload for POST /messages/schedule.
+type ScheduleMessageRequest struct {
+	ThreadID  string    `json:"thread_id"`
+	Content   string    `json:"content"`
+	ReplyToID *string   `json:"reply_to_id,omitempty"`
+	SendAt    time.Time `json:"send_at"`
 }
 
 // CreateDirectThreadRequest is the payload for POST /messages/thread/direct.
@@ -60,6 +179,39 @@ type CreateDirectThreadRequest struct {
 	UserID string `json:"user_id"`
 }
 
+// PinMessageRequest is the payload for POST /messages/{threadId}/pins.
+type PinMessageRequest struct {
+	MessageID string `json:"message_id"`
+}
+
+// UpdateThreadSettingsRequest is the payload for PUT /messages/{threadId}/settings.
+// Omitted fields are left unchanged.
+type UpdateThreadSettingsRequest struct {
+	Muted             *bool   `json:"muted,omitempty"`
+	MuteHours         *int    `json:"mute_hours,omitempty"` // only with muted=true; omit to mute until unmuted
+	NotificationLevel *string `json:"notification_level,omitempty"`
+	NoForward         *bool   `json:"no_forward,omitempty"` // thread-wide; group admin only
+	MessageTTL        *string `json:"message_ttl,omitempty"` // thread-wide: "24h", "7d" or "off"
+}
+
+// ForwardMessageRequest is the payload for POST /messages/forward.
+type ForwardMessageRequest struct {
+	MessageID string   `json:"message_id"`
+	ThreadIDs []string `json:"thread_ids"`
+}
+
+// ForwardFailure explains why a message could not be forwarded to one thread.
+type ForwardFailure struct {
+	ThreadID string `json:"thread_id"`
+	Error    string `json:"error"`
+}
+
+// ForwardResponse is returned by POST /messages/forward.
+type ForwardResponse struct {
+	Forwarded []Message        `json:"forwarded"`
+	Failed    []ForwardFailure `json:"failed"`
+}
+
 // --- WebSocket Protocol ---
 
 // WSIncoming represents a message received from a client over WebSocket.
@@ -68,7 +220,7 @@ type WSIncoming struct {
 	ThreadID    string  `json:"thread_id"` // target thread
 	Content     string  `json:"content"`   // message body (for "message" type)
 	ReplyToID   *string `json:"reply_to_id,omitempty"`
-	IsForwarded bool    `json:"is_forwarded"`
+	IsForwarded bool    `json:"is_forwarded"` // ignored; forwards go through POST /messages/forward
 }
 
 // WSOutgoing represents a message sent to a client over WebSocket.
@@ -94,3 +246,81 @@ type WSUserTyping struct {
 	UserID   string `json:"user_id"`
 	UserName string `json:"user_name"`
 }
+
+// WSPinsUpdated is the payload for "pins_updated" events.
+type WSPinsUpdated struct {
+	ThreadID string          `json:"thread_id"`
+	Pins     []PinnedMessage `json:"pins"`
+}
+
+// Live group events. They are pushed to the members of a travel group, and for
+// join requests to the requester, whenever the group changes, so that open
+// group screens can update without polling GET /groups/{id}.
+const (
+	EventGroupUpdated        = "group_updated"
+	EventMemberJoined        = "member_joined"
+	EventMemberLeft          = "member_left"
+	EventJoinRequestReceived = "join_request_received"
+	EventJoinRequestResolved = "join_request_resolved"
+)
+
+// What changed in a "group_updated" event.
+const (
+	GroupChangeDetails   = "details"   // name, description, dates, route or meeting point
+	GroupChangeStatus    = "status"    // locked or unlocked; see Status
+	GroupChangeRoles     = "roles"     // a member was promoted or demoted, or ownership moved
+	GroupChangeItinerary = "itinerary" // legs were added, edited or removed
+	GroupChangeExpenses  = "expenses"  // an expense or settlement was recorded or deleted
+	GroupChangeDeleted   = "deleted"   // the group no longer exists
+)
+
+// How a member got into the group, in "member_joined" events.
+const (
+	JoinedDirectly     = "join"     // joined an open group without approval
+	JoinedByRequest    = "request"  // an admin accepted their join request
+	JoinedByInvite     = "invite"   // redeemed an invite
+	JoinedFromWaitlist = "waitlist" // was promoted from, or claimed a spot on, the waitlist
+)
+
+// WSGroupUpdated is the payload for "group_updated" events, sent to every
+// member. Clients refetch whatever the change affects.
+type WSGroupUpdated struct {
+	GroupID string `json:"group_id"`
+	Change  string `json:"change"`
+	ActorID string `json:"actor_id"`
+	Status  string `json:"status,omitempty"` // the new status, for GroupChangeStatus
+}
+
+// WSMemberJoined is the payload for "member_joined" events, sent to every
+// member including the one who joined.
+type WSMemberJoined struct {
+	GroupID    string `json:"group_id"`
+	UserID     string `json:"user_id"`
+	Via        string `json:"via"`
+	ApprovedBy string `json:"approved_by,omitempty"` // for JoinedByRequest
+}
+
+// WSMemberLeft is the payload for "member_left" events, sent to the remaining
+// members and to the user who left or was removed.
+type WSMemberLeft struct {
+	GroupID   string `json:"group_id"`
+	UserID    string `json:"user_id"`
+	RemovedBy string `json:"removed_by,omitempty"` // set when an admin removed the member
+}
+
+// WSJoinRequestReceived is the payload for "join_request_received" events,
+// sent to the group's owner and admins.
+type WSJoinRequestReceived struct {
+	GroupID string `json:"group_id"`
+	UserID  string `json:"user_id"`
+	Message string `json:"message"`
+}
+
+// WSJoinRequestResolved is the payload for "join_request_resolved" events,
+// sent to the requester and to the group's owner and admins.
+type WSJoinRequestResolved struct {
+	GroupID    string `json:"group_id"`
+	UserID     string `json:"user_id"`               // the requester
+	Status     string `json:"status"`                // accepted, declined, cancelled or expired
+	ResolvedBy string `json:"resolved_by,omitempty"` // the admin who accepted or declined
+}
@@ -3,6 +3,8 @@ package messages
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"slices"
 	"time"
 )
 
@@ -14,19 +16,84 @@ type Repository interface {
 	ListUserThreads(ctx context.Context, userID string) ([]ThreadSummary, error)
 
 	// Messages
-	GetMessages(ctx context.Context, threadID, userID string, before time.Time, limit int) ([]Message, error)
+	// GetMessages returns one page of a thread's visible history, newest first.
+	GetMessages(ctx context.Context, threadID, userID string, q MessageQuery) ([]Message, error)
 	CreateMessage(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (Message, error)
+	// CreateForwardedMessage copies src into threadID as a forward by senderID, keeping provenance.
+	CreateForwardedMessage(ctx context.Context, threadID, senderID string, src Message) (Message, error)
+	// CreateSystemMessage posts a system message of the given kind into a group thread.
+	CreateSystemMessage(ctx context.Context, threadID, actorID, kind string, metadata map[string]string) (Message, error)
+	// GetMessage returns a single message if it is visible to the user.
+	GetMessage(ctx context.Context, messageID, userID string) (Message, error)
 	DeleteMessage(ctx context.Context, messageID, userID string) (string, error)
+	// GetReplyTree returns a message followed by every reply beneath it, oldest first.
+	// Returns sql.ErrNoRows if the root is not visible to the user.
+	GetReplyTree(ctx context.Context, threadID, messageID, userID string) ([]Message, error)
+	// SearchMessages runs a full-text query over every thread the user participates in.
+	// Results are newest first; pass the last result's cursor to fetch the next page.
+	SearchMessages(ctx context.Context, userID, query string, after *Cursor, limit int) ([]SearchResult, error)
+	// DeleteExpiredMessages hard-deletes up to limit disappearing messages that
+	// expired at or before now and returns what was removed.
+	DeleteExpiredMessages(ctx context.Context, now time.Time, limit int) ([]ExpiredMessage, error)
+
+	// Scheduled messages
+	CreateScheduledMessage(ctx context.Context, sm ScheduledMessage) (ScheduledMessage, error)
+	// ListScheduledMessages returns the user's pending scheduled messages, soonest first.
+	ListScheduledMessages(ctx context.Context, userID string) ([]ScheduledMessage, error)
+	// CancelScheduledMessage cancels a pending message owned by the user.
+	// Returns sql.ErrNoRows if there is no such pending message.
+	CancelScheduledMessage(ctx context.Context, id, userID string) error
+	// ClaimDueScheduledMessages atomically moves up to limit due messages from
+	// pending to sending and returns them. Messages left in sending for longer
+	// than ScheduledClaimTimeout are claimed again.
+	ClaimDueScheduledMessages(ctx context.Context, now time.Time, limit int) ([]ScheduledMessage, error)
+	// CompleteScheduledMessage records the outcome of a claimed message.
+	CompleteScheduledMessage(ctx context.Context, id string, messageID *string, deliveryErr error) error
+
+	// Mentions
+	SaveMentions(ctx context.Context, messageID string, mentions []Mention) error
+	// ListMentions returns messages that mention the user, newest first.
+	ListMentions(ctx context.Context, userID string, after *Cursor, limit int) ([]MentionedMessage, error)
 
 	// Thread management
 	ClearThread(ctx context.Context, threadID, userID string) error
 	MarkThreadAsRead(ctx context.Context, threadID, userID string) error
 	AcceptRequest(ctx context.Context, threadID, userID string) error
-	DeclineRequest(ctx context.Context, threadID, userID string) error
+	// DeclineRequest deletes the request thread; with block the sender is also blocked.
+	DeclineRequest(ctx context.Context, threadID, userID string, block bool) error
+
+	// Message requests
+	// OpenMessageRequest finds or creates the request thread from sender to
+	// recipient and logs the request so quotas survive a declined thread.
+	// Nothing is created if it returns ErrRequestQuotaExceeded.
+	OpenMessageRequest(ctx context.Context, senderID, recipientID string) (Thread, error)
+	// IsRequestRecipient reports whether the user received the thread's pending request.
+	IsRequestRecipient(ctx context.Context, threadID, userID string) (bool, error)
+	// GetRequestStats counts the sender's recent requests and how recipients answered.
+	GetRequestStats(ctx context.Context, senderID string, now time.Time) (RequestStats, error)
+
+	// Notification settings
+	GetThread(ctx context.Context, threadID string) (Thread, error)
+	GetThreadSettings(ctx context.Context, threadID, userID string) (ThreadSettings, error)
+	UpdateThreadSettings(ctx context.Context, threadID, userID string, settings ThreadSettings) error
+	SetThreadNoForward(ctx context.Context, threadID string, noForward bool) error
+	// SetThreadMessageTTL sets the disappearing message timer; 0 turns it off.
+	// Only messages sent afterwards are affected.
+	SetThreadMessageTTL(ctx context.Context, threadID string, ttlSeconds int) error
+	GetPushPreferences(ctx context.Context, userID string) (PushPreferences, error)
+
+	// Pins
+	// CanManageThread reports whether the user may administer a group thread
+	// (the owner or an admin of the underlying travel group).
+	CanManageThread(ctx context.Context, threadID, userID string) (bool, error)
+	PinMessage(ctx context.Context, threadID, messageID, userID string) error
+	UnpinMessage(ctx context.Context, threadID, messageID string) error
+	GetPinnedMessages(ctx context.Context, threadID string) ([]PinnedMessage, error)
 
 	// Membership
 	IsParticipant(ctx context.Context, threadID, userID string) (bool, error)
 	GetParticipantIDs(ctx context.Context, threadID string) ([]string, error)
+	GetParticipants(ctx context.Context, threadID string) ([]Participant, error)
 
 	// Device tokens (push notifications)
 	UpsertDeviceToken(ctx context.Context, userID, token, platform string) error
@@ -50,18 +117,7 @@ func NewRepository(db *sql.DB) Repository {
 // GetOrCreateDirectThread finds or creates a 1:1 thread between two users.
 func (r *PostgresRepository) GetOrCreateDirectThread(ctx context.Context, userID1, userID2 string, isRequest bool) (Thread, error) {
 	// Check if a direct thread already exists between these two users
-	var t Thread
-	err := r.db.QueryRowContext(ctx, `
-		SELECT mt.id, 
-			mt.type,
-			mt.group_id, mt.created_at, mt.is_request, mt.request_message_count
-		FROM message_threads mt
-		JOIN thread_participants tp1 ON tp1.thread_id = mt.id AND tp1.user_id = $1
-		JOIN thread_participants tp2 ON tp2.thread_id = mt.id AND tp2.user_id = $2
-		WHERE mt.type = 'direct' AND mt.is_request = $3
-		LIMIT 1
-	`, userID1, userID2, isRequest).Scan(&t.ID, &t.Type, &t.GroupID, &t.CreatedAt, &t.IsRequest, &t.RequestMessageCount)
-
+	t, err := findDirectThread(ctx, r.db, userID1, userID2, isRequest)
 	if err == nil {
 		return t, nil // existing thread found
 	}
@@ -76,7 +132,31 @@ func (r *PostgresRepository) GetOrCreateDirectThread(ctx context.Context, userID
 	}
 	defer tx.Rollback()
 
-	err = tx.QueryRowContext(ctx, `
+	t, err = createDirectThread(ctx, tx, userID1, userID2, isRequest)
+	if err != nil {
+		return Thread{}, err
+	}
+	return t, tx.Commit()
+}
+
+func findDirectThread(ctx context.Context, q queryRower, userID1, userID2 string, isRequest bool) (Thread, error) {
+	var t Thread
+	err := q.QueryRowContext(ctx, `
+		SELECT mt.id, 
+			mt.type,
+			mt.group_id, mt.created_at, mt.is_request, mt.request_message_count
+		FROM message_threads mt
+		JOIN thread_participants tp1 ON tp1.thread_id = mt.id AND tp1.user_id = $1
+		JOIN thread_participants tp2 ON tp2.thread_id = mt.id AND tp2.user_id = $2
+		WHERE mt.type = 'direct' AND mt.is_request = $3
+		LIMIT 1
+	`, userID1, userID2, isRequest).Scan(&t.ID, &t.Type, &t.GroupID, &t.CreatedAt, &t.IsRequest, &t.RequestMessageCount)
+	return t, err
+}
+
+func createDirectThread(ctx context.Context, tx *sql.Tx, userID1, userID2 string, isRequest bool) (Thread, error) {
+	var t Thread
+	err := tx.QueryRowContext(ctx, `
 		INSERT INTO message_threads (type, is_request) VALUES ('direct', $1)
 		RETURNING id, type, group_id, created_at, is_request, request_message_count
 	`, isRequest).Scan(&t.ID, &t.Type, &t.GroupID, &t.CreatedAt, &t.IsRequest, &t.RequestMessageCount)
@@ -90,8 +170,7 @@ func (r *PostgresRepository) GetOrCreateDirectThread(ctx context.Context, userID
 	if err != nil {
 		return Thread{}, err
 	}
-
-	return t, tx.Commit()
+	return t, nil
 }
 
 // CreateGroupThread creates a group chat thread linked to a travel_group.
@@ -148,10 +227,19 @@ func (r *PostgresRepository) ListUserThreads(ctx context.Context, userID string)
 				  AND m2.sender_id != $1
 				  AND m2.created_at > COALESCE(tp.last_read_at, '1970-01-01'::timestamptz)
 				  AND m2.created_at > COALESCE(tp.cleared_at, '1970-01-01'::timestamptz)
+				  AND (m2.expires_at IS NULL OR m2.expires_at > NOW())
 			) AS unread_count,
 			mt.is_request,
 			mt.request_message_count,
-			COALESCE(c.requester_id = $1, false) AS is_requester
+			COALESCE(c.requester_id = $1, false) AS is_requester,
+			lp.message_id AS pinned_message_id,
+			lp.content AS pinned_message_content,
+			(tp.is_muted AND (tp.muted_until IS NULL OR tp.muted_until > NOW())) AS is_muted,
+			CASE WHEN tp.is_muted AND tp.muted_until > NOW() THEN tp.muted_until END AS muted_until,
+			tp.notification_level,
+			mt.no_forward,
+			COALESCE(mt.message_ttl_seconds, 0) AS message_ttl_seconds,
+			mt.read_only
 		FROM thread_participants tp
 		JOIN message_threads mt ON mt.id = tp.thread_id
 		-- For direct chats: get the OTHER participant's name
@@ -172,8 +260,17 @@ func (r *PostgresRepository) ListUserThreads(ctx context.Context, userID string)
 			SELECT content, created_at FROM messages
 			WHERE thread_id = mt.id
 			  AND created_at > COALESCE(tp.cleared_at, '1970-01-01'::timestamptz)
+			  AND (expires_at IS NULL OR expires_at > NOW())
 			ORDER BY created_at DESC LIMIT 1
 		) lm ON true
+		-- Latest pinned message
+		LEFT JOIN LATERAL (
+			SELECT pm.message_id, pmm.content FROM pinned_messages pm
+			JOIN messages pmm ON pmm.id = pm.message_id
+			WHERE pm.thread_id = mt.id
+			  AND (pmm.expires_at IS NULL OR pmm.expires_at > NOW())
+			ORDER BY pm.pinned_at DESC LIMIT 1
+		) lp ON true
 		WHERE tp.user_id = $1
 		  -- Only exclude if the other user IS identified and has blocked
 		  AND NOT EXISTS (
@@ -197,8 +294,11 @@ func (r *PostgresRepository) ListUserThreads(ctx context.Context, userID string)
 		var avatarURL sql.NullString
 		var otherUserID sql.NullString
 		var isRequester sql.NullBool
+		var pinnedID, pinnedContent sql.NullString
 		if err := rows.Scan(&ts.ID, &ts.Type, &groupID, &ts.Name, &ts.LastMessage,
-			&ts.LastMessageTime, &avatarURL, &otherUserID, &ts.UnreadCount, &ts.IsRequest, &ts.RequestMessageCount, &isRequester); err != nil {
+			&ts.LastMessageTime, &avatarURL, &otherUserID, &ts.UnreadCount, &ts.IsRequest, &ts.RequestMessageCount, &isRequester,
+			&pinnedID, &pinnedContent, &ts.IsMuted, &ts.MutedUntil, &ts.NotificationLevel, &ts.NoForward, &ts.MessageTTLSeconds,
+			&ts.ReadOnly); err != nil {
 			return nil, err
 		}
 		if avatarURL.Valid {
@@ -213,6 +313,10 @@ func (r *PostgresRepository) ListUserThreads(ctx context.Context, userID string)
 		if isRequester.Valid {
 			ts.IsRequester = isRequester.Bool
 		}
+		if pinnedID.Valid {
+			ts.PinnedMessageID = &pinnedID.String
+			ts.PinnedMessageContent = &pinnedContent.String
+		}
 		// Mirror Name to OtherUserName so both JSON fields are populated
 		ts.OtherUserName = ts.Name
 		threads = append(threads, ts)
@@ -220,28 +324,62 @@ func (r *PostgresRepository) ListUserThreads(ctx context.Context, userID string)
 	return threads, rows.Err()
 }
 
-// GetMessages returns paginated messages for a thread, respecting cleared_at.
-func (r *PostgresRepository) GetMessages(ctx context.Context, threadID, userID string, before time.Time, limit int) ([]Message, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 50
+// mentionsColumn selects a message's mentions as a JSON array, ordered by position.
+// It expects the message to be aliased as m.
+const mentionsColumn = `COALESCE((
+				SELECT json_agg(json_build_object('user_id', mm.user_id, 'offset', mm.start_offset, 'length', mm.length) ORDER BY mm.start_offset)
+				FROM message_mentions mm WHERE mm.message_id = m.id
+			), '[]'::json) AS mentions`
+
+// GetMessages returns a page of messages for a thread, respecting cleared_at.
+// Keyset pagination over (created_at, id) so rows sharing a timestamp are never skipped.
+func (r *PostgresRepository) GetMessages(ctx context.Context, threadID, userID string, q MessageQuery) ([]Message, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultMessagePageSize
+	}
+
+	// Paging forward walks up from the cursor; the page is flipped to newest first below.
+	order, cmp := "DESC", "<"
+	var cursor *Cursor
+	switch {
+	case q.After != nil:
+		order, cmp, cursor = "ASC", ">", q.After
+	case q.Before != nil:
+		cursor = q.Before
+		if q.Inclusive {
+			cmp = "<="
+		}
+	}
+	var cursorTime *time.Time
+	var cursorID *string
+	if cursor != nil {
+		cursorTime, cursorID = &cursor.CreatedAt, &cursor.ID
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT 
 			m.id, m.thread_id, COALESCE(m.sender_id::text, ''), COALESCE(p.full_name, 'Deleted User'), 
 			m.content, m.created_at, m.reply_to_id, m.is_forwarded,
-			rm.content AS reply_to_content, COALESCE(rp.full_name, 'Deleted User') AS reply_to_sender
+			rm.content AS reply_to_content, COALESCE(rp.full_name, 'Deleted User') AS reply_to_sender,
+			`+mentionsColumn+`,
+			(SELECT COUNT(*) FROM messages r WHERE r.reply_to_id = m.id AND (r.expires_at IS NULL OR r.expires_at > NOW())) AS reply_count,
+			m.original_message_id, m.original_sender_id::text, op.full_name AS original_sender_name,
+			m.expires_at, m.kind, m.metadata
 		FROM messages m
 		LEFT JOIN profiles p ON p.user_id = m.sender_id
-		LEFT JOIN messages rm ON rm.id = m.reply_to_id
+		LEFT JOIN messages rm ON rm.id = m.reply_to_id AND (rm.expires_at IS NULL OR rm.expires_at > NOW())
 		LEFT JOIN profiles rp ON rp.user_id = rm.sender_id
-		JOIN thread_participants tp ON tp.thread_id = m.thread_id AND tp.user_id = $3
+		LEFT JOIN profiles op ON op.user_id = m.original_sender_id
+		JOIN thread_participants tp ON tp.thread_id = m.thread_id AND tp.user_id = $2
 		WHERE m.thread_id = $1
-		  AND m.created_at < $2
 		  AND m.created_at > COALESCE(tp.cleared_at, '1970-01-01'::timestamptz)
-		ORDER BY m.created_at DESC
-		LIMIT $4
-	`, threadID, before, userID, limit)
+		  -- Expired messages stay hidden until the sweeper removes them
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		  AND ($3::timestamptz IS NULL OR (m.created_at, m.id) `+cmp+` ($3::timestamptz, $4::uuid))
+		ORDER BY m.created_at `+order+`, m.id `+order+`
+		LIMIT $5
+	`, threadID, userID, cursorTime, cursorID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -250,15 +388,119 @@ func (r *PostgresRepository) GetMessages(ctx context.Context, threadID, userID s
 	var msgs []Message
 	for rows.Next() {
 		var m Message
-		if err := rows.Scan(&m.ID, &m.ThreadID, &m.SenderID, &m.SenderName, &m.Content, &m.CreatedAt, &m.ReplyToID, &m.IsForwarded, &m.ReplyToContent, &m.ReplyToSender); err != nil {
+		var mentionsJSON, metadataJSON []byte
+		if err := rows.Scan(&m.ID, &m.ThreadID, &m.SenderID, &m.SenderName, &m.Content, &m.CreatedAt, &m.ReplyToID, &m.IsForwarded, &m.ReplyToContent, &m.ReplyToSender, &mentionsJSON, &m.ReplyCount,
+			&m.OriginalMessageID, &m.OriginalSenderID, &m.OriginalSenderName, &m.ExpiresAt, &m.Kind, &metadataJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(mentionsJSON, &m.Mentions); err != nil {
 			return nil, err
 		}
+		if metadataJSON != nil {
+			if err := json.Unmarshal(metadataJSON, &m.Metadata); err != nil {
+				return nil, err
+			}
+		}
+		msgs = append(msgs, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if order == "ASC" {
+		slices.Reverse(msgs)
+	}
+	return msgs, nil
+}
+
+// maxReplyDepth bounds the reply-chain walk in GetReplyTree.
+const maxReplyDepth = 100
+
+// GetReplyTree walks reply_to_id links downward from messageID with a recursive CTE.
+// The root comes first, then all descendants ordered by created_at.
+func (r *PostgresRepository) GetReplyTree(ctx context.Context, threadID, messageID, userID string) ([]Message, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH RECURSIVE tree AS (
+			SELECT m.id, 0 AS depth
+			FROM messages m
+			JOIN thread_participants tp ON tp.thread_id = m.thread_id AND tp.user_id = $3
+			WHERE m.id = $2 AND m.thread_id = $1
+			  AND m.created_at > COALESCE(tp.cleared_at, '1970-01-01'::timestamptz)
+			  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+			UNION ALL
+			SELECT c.id, t.depth + 1
+			FROM messages c
+			JOIN tree t ON c.reply_to_id = t.id
+			WHERE c.thread_id = $1 AND t.depth < $4
+		)
+		SELECT
+			m.id, m.thread_id, COALESCE(m.sender_id::text, ''), COALESCE(p.full_name, 'Deleted User'),
+			m.content, m.created_at, m.reply_to_id, m.is_forwarded,
+			rm.content AS reply_to_content, COALESCE(rp.full_name, 'Deleted User') AS reply_to_sender,
+			`+mentionsColumn+`,
+			(SELECT COUNT(*) FROM messages r WHERE r.reply_to_id = m.id AND (r.expires_at IS NULL OR r.expires_at > NOW())) AS reply_count,
+			m.original_message_id, m.original_sender_id::text, op.full_name AS original_sender_name,
+			m.expires_at, m.kind, m.metadata
+		FROM tree t
+		JOIN messages m ON m.id = t.id
+		LEFT JOIN profiles p ON p.user_id = m.sender_id
+		LEFT JOIN messages rm ON rm.id = m.reply_to_id AND (rm.expires_at IS NULL OR rm.expires_at > NOW())
+		LEFT JOIN profiles rp ON rp.user_id = rm.sender_id
+		LEFT JOIN profiles op ON op.user_id = m.original_sender_id
+		-- Replies below an expired message are still shown
+		WHERE m.expires_at IS NULL OR m.expires_at > NOW()
+		ORDER BY t.depth = 0 DESC, m.created_at ASC, m.id ASC
+	`, threadID, messageID, userID, maxReplyDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var msgs []Message
+	for rows.Next() {
+		var m Message
+		var mentionsJSON, metadataJSON []byte
+		if err := rows.Scan(&m.ID, &m.ThreadID, &m.SenderID, &m.SenderName, &m.Content, &m.CreatedAt, &m.ReplyToID, &m.IsForwarded, &m.ReplyToContent, &m.ReplyToSender, &mentionsJSON, &m.ReplyCount,
+			&m.OriginalMessageID, &m.OriginalSenderID, &m.OriginalSenderName, &m.ExpiresAt, &m.Kind, &metadataJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(mentionsJSON, &m.Mentions); err != nil {
+			return nil, err
+		}
+		if metadataJSON != nil {
+			if err := json.Unmarshal(metadataJSON, &m.Metadata); err != nil {
+				return nil, err
+			}
+		}
 		msgs = append(msgs, m)
 	}
-	return msgs, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return msgs, nil
 }
 
+// CreateMessage inserts a message, enforcing the request-thread message limit.
 func (r *PostgresRepository) CreateMessage(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (Message, error) {
+	return r.insertMessage(ctx, threadID, senderID, content, replyToID, isForwarded, nil, nil)
+}
+
+// CreateForwardedMessage forwards src into another thread. Forwarding a forward keeps
+// pointing at the first message in the chain.
+func (r *PostgresRepository) CreateForwardedMessage(ctx context.Context, threadID, senderID string, src Message) (Message, error) {
+	originalID, originalSender := src.OriginalMessageID, src.OriginalSenderID
+	if originalID == nil {
+		originalID = &src.ID
+		if src.SenderID != "" {
+			originalSender = &src.SenderID
+		}
+	}
+	return r.insertMessage(ctx, threadID, senderID, src.Content, nil, true, originalID, originalSender)
+}
+
+func (r *PostgresRepository) insertMessage(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool, originalID, originalSender *string) (Message, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return Message{}, err
@@ -290,31 +532,88 @@ func (r *PostgresRepository) CreateMessage(ctx context.Context, threadID, sender
 	var m Message
 	err = tx.QueryRowContext(ctx, `
 		WITH inserted AS (
-			INSERT INTO messages (thread_id, sender_id, content, reply_to_id, is_forwarded)
-			VALUES ($1, $2, $3, $4, $5)
-			RETURNING id, thread_id, sender_id, content, created_at, reply_to_id, is_forwarded
+			INSERT INTO messages (thread_id, sender_id, content, reply_to_id, is_forwarded, original_message_id, original_sender_id, expires_at)
+			SELECT $1, $2, $3, $4, $5, $6, $7, NOW() + make_interval(secs => mt.message_ttl_seconds)
+			FROM message_threads mt WHERE mt.id = $1
+			RETURNING id, thread_id, sender_id, content, created_at, reply_to_id, is_forwarded, original_message_id, original_sender_id, expires_at
 		)
 		SELECT 
 			i.id, i.thread_id, COALESCE(i.sender_id::text, ''), COALESCE(p.full_name, 'Deleted User'), 
 			i.content, i.created_at, i.reply_to_id, i.is_forwarded,
-			rm.content AS reply_to_content, COALESCE(rp.full_name, 'Deleted User') AS reply_to_sender
+			rm.content AS reply_to_content, COALESCE(rp.full_name, 'Deleted User') AS reply_to_sender,
+			i.original_message_id, i.original_sender_id::text, op.full_name AS original_sender_name,
+			i.expires_at
 		FROM inserted i
 		LEFT JOIN profiles p ON p.user_id = i.sender_id
-		LEFT JOIN messages rm ON rm.id = i.reply_to_id
+		LEFT JOIN messages rm ON rm.id = i.reply_to_id AND (rm.expires_at IS NULL OR rm.expires_at > NOW())
 		LEFT JOIN profiles rp ON rp.user_id = rm.sender_id
-	`, threadID, senderID, content, replyToID, isForwarded).Scan(&m.ID, &m.ThreadID, &m.SenderID, &m.SenderName, &m.Content, &m.CreatedAt, &m.ReplyToID, &m.IsForwarded, &m.ReplyToContent, &m.ReplyToSender)
+		LEFT JOIN profiles op ON op.user_id = i.original_sender_id
+	`, threadID, senderID, content, replyToID, isForwarded, originalID, originalSender).Scan(&m.ID, &m.ThreadID, &m.SenderID, &m.SenderName, &m.Content, &m.CreatedAt, &m.ReplyToID, &m.IsForwarded, &m.ReplyToContent, &m.ReplyToSender,
+		&m.OriginalMessageID, &m.OriginalSenderID, &m.OriginalSenderName, &m.ExpiresAt)
 	if err != nil {
 		return Message{}, err
 	}
+	m.Kind = KindText
 
 	return m, tx.Commit()
 }
 
+// CreateSystemMessage posts a system message into a group thread on behalf of actorID.
+// It skips the request-thread limit, which only applies to direct requests.
+func (r *PostgresRepository) CreateSystemMessage(ctx context.Context, threadID, actorID, kind string, metadata map[string]string) (Message, error) {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var m Message
+	err = r.db.QueryRowContext(ctx, `
+		WITH inserted AS (
+			INSERT INTO messages (thread_id, sender_id, content, kind, metadata, expires_at)
+			SELECT $1, $2, $3, $4, $5, NOW() + make_interval(secs => mt.message_ttl_seconds)
+			FROM message_threads mt WHERE mt.id = $1
+			RETURNING id, thread_id, sender_id, content, created_at, kind, expires_at
+		)
+		SELECT
+			i.id, i.thread_id, COALESCE(i.sender_id::text, ''), COALESCE(p.full_name, 'Deleted User'),
+			i.content, i.created_at, i.kind, i.expires_at
+		FROM inserted i
+		LEFT JOIN profiles p ON p.user_id = i.sender_id
+	`, threadID, actorID, systemContent[kind], kind, metadataJSON).Scan(&m.ID, &m.ThreadID, &m.SenderID, &m.SenderName,
+		&m.Content, &m.CreatedAt, &m.Kind, &m.ExpiresAt)
+	if err != nil {
+		return Message{}, err
+	}
+	m.Metadata = metadata
+	return m, nil
+}
+
+// GetMessage returns a message from a thread the user participates in,
+// unless the user has cleared the chat since it was sent or it has expired.
+func (r *PostgresRepository) GetMessage(ctx context.Context, messageID, userID string) (Message, error) {
+	var m Message
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			m.id, m.thread_id, COALESCE(m.sender_id::text, ''), COALESCE(p.full_name, 'Deleted User'),
+			m.content, m.created_at, m.reply_to_id, m.is_forwarded,
+			m.original_message_id, m.original_sender_id::text, m.kind
+		FROM messages m
+		JOIN thread_participants tp ON tp.thread_id = m.thread_id AND tp.user_id = $2
+		LEFT JOIN profiles p ON p.user_id = m.sender_id
+		WHERE m.id = $1
+		  AND m.created_at > COALESCE(tp.cleared_at, '1970-01-01'::timestamptz)
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+	`, messageID, userID).Scan(&m.ID, &m.ThreadID, &m.SenderID, &m.SenderName, &m.Content, &m.CreatedAt,
+		&m.ReplyToID, &m.IsForwarded, &m.OriginalMessageID, &m.OriginalSenderID, &m.Kind)
+	return m, err
+}
+
 // DeleteMessage removes a message if it belongs to the requesting user and returns its thread ID.
+// System messages cannot be deleted.
 func (r *PostgresRepository) DeleteMessage(ctx context.Context, messageID, userID string) (string, error) {
 	var threadID string
 	err := r.db.QueryRowContext(ctx, `
-		DELETE FROM messages WHERE id = $1 AND sender_id = $2
+		DELETE FROM messages WHERE id = $1 AND sender_id = $2 AND kind = 'text'
 		RETURNING thread_id
 	`, messageID, userID).Scan(&threadID)
 	
@@ -328,6 +627,280 @@ func (r *PostgresRepository) DeleteMessage(ctx context.Context, messageID, userI
 	return threadID, nil
 }
 
+// SearchMessages matches the query against messages.search_vector, limited to threads
+// the user participates in and to messages newer than the user's cleared_at.
+func (r *PostgresRepository) SearchMessages(ctx context.Context, userID, query string, after *Cursor, limit int) ([]SearchResult, error) {
+	var afterTime *time.Time
+	var afterID *string
+	if after != nil {
+		afterTime = &after.CreatedAt
+		afterID = &after.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			m.id,
+			m.thread_id,
+			mt.type,
+			COALESCE(
+				CASE WHEN mt.type = 'group' THEN tg.name
+				     ELSE COALESCE(op.full_name, ou.email)
+				END,
+				'Unknown'
+			) AS thread_name,
+			COALESCE(m.sender_id::text, ''),
+			COALESCE(p.full_name, 'Deleted User'),
+			ts_headline('simple', m.content, websearch_to_tsquery('simple', $2),
+				'StartSel=<mark>, StopSel=</mark>, MaxWords=24, MinWords=8, MaxFragments=2, FragmentDelimiter=" … "'),
+			m.created_at
+		FROM messages m
+		JOIN thread_participants tp ON tp.thread_id = m.thread_id AND tp.user_id = $1
+		JOIN message_threads mt ON mt.id = m.thread_id
+		LEFT JOIN travel_groups tg ON tg.id = mt.group_id
+		-- For direct chats: name the thread after the other participant
+		LEFT JOIN thread_participants tp_other
+			ON tp_other.thread_id = mt.id AND tp_other.user_id != $1 AND mt.type = 'direct'
+		LEFT JOIN profiles op ON op.user_id = tp_other.user_id
+		LEFT JOIN users ou ON ou.id = tp_other.user_id
+		LEFT JOIN profiles p ON p.user_id = m.sender_id
+		WHERE m.search_vector @@ websearch_to_tsquery('simple', $2)
+		  AND m.kind = 'text'
+		  AND m.created_at > COALESCE(tp.cleared_at, '1970-01-01'::timestamptz)
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		  AND ($3::timestamptz IS NULL OR (m.created_at, m.id) < ($3::timestamptz, $4::uuid))
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $5
+	`, userID, query, afterTime, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var sr SearchResult
+		if err := rows.Scan(&sr.MessageID, &sr.ThreadID, &sr.ThreadType, &sr.ThreadName,
+			&sr.SenderID, &sr.SenderName, &sr.Snippet, &sr.CreatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, sr)
+	}
+	return results, rows.Err()
+}
+
+// scheduledColumns is the column list scanned by scanScheduledMessages.
+const scheduledColumns = `id, thread_id, sender_id, content, reply_to_id, send_at, status, message_id, error, created_at`
+
+func scanScheduledMessages(rows *sql.Rows) ([]ScheduledMessage, error) {
+	defer rows.Close()
+	var out []ScheduledMessage
+	for rows.Next() {
+		var sm ScheduledMessage
+		if err := rows.Scan(&sm.ID, &sm.ThreadID, &sm.SenderID, &sm.Content, &sm.ReplyToID, &sm.SendAt,
+			&sm.Status, &sm.MessageID, &sm.Error, &sm.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, sm)
+	}
+	return out, rows.Err()
+}
+
+// DeleteExpiredMessages removes the oldest expired messages in one statement.
+// SKIP LOCKED lets several server instances sweep without blocking each other.
+func (r *PostgresRepository) DeleteExpiredMessages(ctx context.Context, now time.Time, limit int) ([]ExpiredMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		DELETE FROM messages
+		WHERE id IN (
+			SELECT id FROM messages
+			WHERE expires_at <= $1
+			ORDER BY expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, thread_id
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expired []ExpiredMessage
+	for rows.Next() {
+		var e ExpiredMessage
+		if err := rows.Scan(&e.ID, &e.ThreadID); err != nil {
+			return nil, err
+		}
+		expired = append(expired, e)
+	}
+	return expired, rows.Err()
+}
+
+// CreateScheduledMessage queues a message for later delivery.
+func (r *PostgresRepository) CreateScheduledMessage(ctx context.Context, sm ScheduledMessage) (ScheduledMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		INSERT INTO scheduled_messages (thread_id, sender_id, content, reply_to_id, send_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+scheduledColumns,
+		sm.ThreadID, sm.SenderID, sm.Content, sm.ReplyToID, sm.SendAt)
+	if err != nil {
+		return ScheduledMessage{}, err
+	}
+	out, err := scanScheduledMessages(rows)
+	if err != nil {
+		return ScheduledMessage{}, err
+	}
+	if len(out) == 0 {
+		return ScheduledMessage{}, sql.ErrNoRows
+	}
+	return out[0], nil
+}
+
+// ListScheduledMessages returns the user's pending scheduled messages.
+func (r *PostgresRepository) ListScheduledMessages(ctx context.Context, userID string) ([]ScheduledMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+scheduledColumns+`
+		FROM scheduled_messages
+		WHERE sender_id = $1 AND status = 'pending'
+		ORDER BY send_at ASC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanScheduledMessages(rows)
+}
+
+// CancelScheduledMessage cancels a pending scheduled message.
+func (r *PostgresRepository) CancelScheduledMessage(ctx context.Context, id, userID string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE scheduled_messages SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1 AND sender_id = $2 AND status = 'pending'
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ClaimDueScheduledMessages uses FOR UPDATE SKIP LOCKED so several server
+// instances can dispatch concurrently without sending a message twice. A claim
+// that was never completed, because the dispatcher crashed or could not record
+// the outcome, is retried once it is older than ScheduledClaimTimeout.
+func (r *PostgresRepository) ClaimDueScheduledMessages(ctx context.Context, now time.Time, limit int) ([]ScheduledMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE scheduled_messages SET status = 'sending', claimed_at = $1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM scheduled_messages
+			WHERE (status = 'pending' AND send_at <= $1)
+			   OR (status = 'sending' AND claimed_at <= $3)
+			ORDER BY send_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+scheduledColumns,
+		now, limit, now.Add(-ScheduledClaimTimeout))
+	if err != nil {
+		return nil, err
+	}
+	return scanScheduledMessages(rows)
+}
+
+// CompleteScheduledMessage marks a claimed message as sent, or failed if deliveryErr is set.
+func (r *PostgresRepository) CompleteScheduledMessage(ctx context.Context, id string, messageID *string, deliveryErr error) error {
+	status := ScheduledSent
+	var errText *string
+	if deliveryErr != nil {
+		status = ScheduledFailed
+		e := deliveryErr.Error()
+		errText = &e
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE scheduled_messages
+		SET status = $2, message_id = $3, error = $4, updated_at = NOW()
+		WHERE id = $1
+	`, id, status, messageID, errText)
+	return err
+}
+
+// SaveMentions stores the mentions parsed from a message.
+func (r *PostgresRepository) SaveMentions(ctx context.Context, messageID string, mentions []Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, mn := range mentions {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO message_mentions (message_id, user_id, start_offset, length)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING
+		`, messageID, mn.UserID, mn.Offset, mn.Length)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListMentions returns messages mentioning the user in threads they still belong to,
+// skipping anything older than the user's cleared_at.
+func (r *PostgresRepository) ListMentions(ctx context.Context, userID string, after *Cursor, limit int) ([]MentionedMessage, error) {
+	var afterTime *time.Time
+	var afterID *string
+	if after != nil {
+		afterTime = &after.CreatedAt
+		afterID = &after.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			m.id, m.thread_id, COALESCE(m.sender_id::text, ''), COALESCE(p.full_name, 'Deleted User'),
+			m.content, m.created_at, m.reply_to_id, m.is_forwarded, m.kind,
+			COALESCE(tg.name, 'Group') AS thread_name,
+			`+mentionsColumn+`
+		FROM messages m
+		JOIN thread_participants tp ON tp.thread_id = m.thread_id AND tp.user_id = $1
+		JOIN message_threads mt ON mt.id = m.thread_id
+		LEFT JOIN travel_groups tg ON tg.id = mt.group_id
+		LEFT JOIN profiles p ON p.user_id = m.sender_id
+		WHERE EXISTS (SELECT 1 FROM message_mentions mm WHERE mm.message_id = m.id AND mm.user_id = $1)
+		  AND m.created_at > COALESCE(tp.cleared_at, '1970-01-01'::timestamptz)
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		  AND ($2::timestamptz IS NULL OR (m.created_at, m.id) < ($2::timestamptz, $3::uuid))
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $4
+	`, userID, afterTime, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []MentionedMessage
+	for rows.Next() {
+		var mm MentionedMessage
+		var mentionsJSON []byte
+		if err := rows.Scan(&mm.ID, &mm.ThreadID, &mm.SenderID, &mm.SenderName, &mm.Content, &mm.CreatedAt,
+			&mm.ReplyToID, &mm.IsForwarded, &mm.Kind, &mm.ThreadName, &mentionsJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(mentionsJSON, &mm.Mentions); err != nil {
+			return nil, err
+		}
+		results = append(results, mm)
+	}
+	return results, rows.Err()
+}
+
 // ClearThread sets cleared_at for a user, hiding older messages from their view.
 func (r *PostgresRepository) ClearThread(ctx context.Context, threadID, userID string) error {
 	_, err := r.db.ExecContext(ctx, `
@@ -375,11 +948,17 @@ func (r *PostgresRepository) AcceptRequest(ctx context.Context, threadID, userID
 		return err
 	}
 
+	// 4. Record the response for the sender's request history
+	if err := respondToRequest(ctx, tx, threadID, userID, "accepted"); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
 // DeclineRequest deletes the request thread and removes the pending connection.
-func (r *PostgresRepository) DeclineRequest(ctx context.Context, threadID, userID string) error {
+// When block is set the sender is blocked in the same transaction.
+func (r *PostgresRepository) DeclineRequest(ctx context.Context, threadID, userID string, block bool) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -397,7 +976,16 @@ func (r *PostgresRepository) DeclineRequest(ctx context.Context, threadID, userI
 		return err
 	}
 
-	// 2. Delete the thread (cascades to messages and participants)
+	// 2. Record the response before the thread (and its thread_id) goes away
+	status := "declined"
+	if block {
+		status = "blocked"
+	}
+	if err := respondToRequest(ctx, tx, threadID, userID, status); err != nil {
+		return err
+	}
+
+	// 3. Delete the thread (cascades to messages and participants)
 	_, err = tx.ExecContext(ctx, `
 		DELETE FROM message_threads WHERE id = $1 AND is_request = true
 	`, threadID)
@@ -405,7 +993,7 @@ func (r *PostgresRepository) DeclineRequest(ctx context.Context, threadID, userI
 		return err
 	}
 
-	// 3. Delete the pending connection
+	// 4. Delete the pending connection
 	_, err = tx.ExecContext(ctx, `
 		DELETE FROM connections
 		WHERE ((user_id_1 = $1 AND user_id_2 = $2) OR (user_id_1 = $2 AND user_id_2 = $1))
@@ -415,9 +1003,302 @@ func (r *PostgresRepository) DeclineRequest(ctx context.Context, threadID, userI
 		return err
 	}
 
+	// 5. Optionally block the sender
+	if block {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO connections (user_id_1, user_id_2, status, created_at)
+			VALUES ($1, $2, 'blocked', NOW())
+			ON CONFLICT (user_id_1, user_id_2)
+			DO UPDATE SET status = 'blocked'
+		`, userID, otherUserID)
+		if err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit()
 }
 
+// respondToRequest closes the pending message_requests row for a request thread.
+func respondToRequest(ctx context.Context, tx *sql.Tx, threadID, recipientID, status string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE message_requests
+		SET status = $3, responded_at = NOW()
+		WHERE thread_id = $1 AND recipient_id = $2 AND status = 'pending'
+	`, threadID, recipientID, status)
+	return err
+}
+
+// OpenMessageRequest finds or creates the request thread from sender to
+// recipient and logs a new message request in the same transaction, so a
+// sender over quota is left with no empty request thread.
+// Repeat calls for a thread that already has a pending request only return it.
+// Returns ErrRequestQuotaExceeded when the sender has no requests left today.
+func (r *PostgresRepository) OpenMessageRequest(ctx context.Context, senderID, recipientID string) (Thread, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Thread{}, err
+	}
+	defer tx.Rollback()
+
+	// Lock the sender so concurrent requests cannot both pass the quota check
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, senderID); err != nil {
+		return Thread{}, err
+	}
+
+	t, err := findDirectThread(ctx, tx, senderID, recipientID, true)
+	switch {
+	case err == nil:
+		var pending bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM message_requests WHERE thread_id = $1 AND status = 'pending')
+		`, t.ID).Scan(&pending)
+		if err != nil {
+			return Thread{}, err
+		}
+		if pending {
+			return t, tx.Commit()
+		}
+	case err != sql.ErrNoRows:
+		return Thread{}, err
+	}
+
+	stats, err := requestStats(ctx, tx, senderID, time.Now())
+	if err != nil {
+		return Thread{}, err
+	}
+	if stats.Remaining() == 0 {
+		return Thread{}, ErrRequestQuotaExceeded
+	}
+
+	if t.ID == "" {
+		if t, err = createDirectThread(ctx, tx, senderID, recipientID, true); err != nil {
+			return Thread{}, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO message_requests (sender_id, recipient_id, thread_id) VALUES ($1, $2, $3)
+	`, senderID, recipientID, t.ID)
+	if err != nil {
+		return Thread{}, err
+	}
+	return t, tx.Commit()
+}
+
+// IsRequestRecipient reports whether the thread has a pending request addressed to the user.
+func (r *PostgresRepository) IsRequestRecipient(ctx context.Context, threadID, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM message_requests
+			WHERE thread_id = $1 AND recipient_id = $2 AND status = 'pending'
+		)
+	`, threadID, userID).Scan(&exists)
+	return exists, err
+}
+
+// GetRequestStats returns the sender's request counts used for quotas and throttling.
+func (r *PostgresRepository) GetRequestStats(ctx context.Context, senderID string, now time.Time) (RequestStats, error) {
+	return requestStats(ctx, r.db, senderID, now)
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func requestStats(ctx context.Context, q queryRower, senderID string, now time.Time) (RequestStats, error) {
+	var s RequestStats
+	err := q.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE created_at > $2 - INTERVAL '24 hours'),
+			COUNT(*) FILTER (WHERE status <> 'pending' AND responded_at > $3),
+			COUNT(*) FILTER (WHERE status IN ('declined', 'blocked') AND responded_at > $3)
+		FROM message_requests
+		WHERE sender_id = $1 AND created_at > $3
+	`, senderID, now, now.Add(-ThrottleWindow)).Scan(&s.SentToday, &s.Responded, &s.Declined)
+	return s, err
+}
+
+// GetThread returns a thread by ID.
+func (r *PostgresRepository) GetThread(ctx context.Context, threadID string) (Thread, error) {
+	var t Thread
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, type, group_id, created_at, is_request, request_message_count, no_forward,
+			COALESCE(message_ttl_seconds, 0), read_only
+		FROM message_threads WHERE id = $1
+	`, threadID).Scan(&t.ID, &t.Type, &t.GroupID, &t.CreatedAt, &t.IsRequest, &t.RequestMessageCount, &t.NoForward,
+		&t.MessageTTLSeconds, &t.ReadOnly)
+	return t, err
+}
+
+// GetThreadSettings returns the participant's notification settings for a thread.
+// Returns sql.ErrNoRows if the user is not a participant.
+func (r *PostgresRepository) GetThreadSettings(ctx context.Context, threadID, userID string) (ThreadSettings, error) {
+	var s ThreadSettings
+	err := r.db.QueryRowContext(ctx, `
+		SELECT tp.is_muted, tp.muted_until, tp.notification_level, mt.no_forward,
+			COALESCE(mt.message_ttl_seconds, 0)
+		FROM thread_participants tp
+		JOIN message_threads mt ON mt.id = tp.thread_id
+		WHERE tp.thread_id = $1 AND tp.user_id = $2
+	`, threadID, userID).Scan(&s.IsMuted, &s.MutedUntil, &s.NotificationLevel, &s.NoForward, &s.MessageTTLSeconds)
+	return s, err
+}
+
+// UpdateThreadSettings overwrites the participant's notification settings for a thread.
+// Thread-wide fields are ignored; see SetThreadNoForward and SetThreadMessageTTL.
+func (r *PostgresRepository) UpdateThreadSettings(ctx context.Context, threadID, userID string, settings ThreadSettings) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE thread_participants
+		SET is_muted = $3, muted_until = $4, notification_level = $5
+		WHERE thread_id = $1 AND user_id = $2
+	`, threadID, userID, settings.IsMuted, settings.MutedUntil, settings.NotificationLevel)
+	return err
+}
+
+// SetThreadNoForward turns forwarding out of a thread on or off.
+func (r *PostgresRepository) SetThreadNoForward(ctx context.Context, threadID string, noForward bool) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE message_threads SET no_forward = $2 WHERE id = $1`, threadID, noForward)
+	return err
+}
+
+// SetThreadMessageTTL sets the disappearing message timer, storing NULL when it is turned off.
+func (r *PostgresRepository) SetThreadMessageTTL(ctx context.Context, threadID string, ttlSeconds int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE message_threads SET message_ttl_seconds = NULLIF($2, 0) WHERE id = $1
+	`, threadID, ttlSeconds)
+	return err
+}
+
+// GetPushPreferences reads the account-wide push toggles, defaulting to enabled
+// when the user has never saved preferences.
+func (r *PostgresRepository) GetPushPreferences(ctx context.Context, userID string) (PushPreferences, error) {
+	p := PushPreferences{PushNotifications: true, MessageAlerts: true}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(push_notifications, true), COALESCE(message_alerts, true)
+		FROM user_preferences WHERE user_id = $1
+	`, userID).Scan(&p.PushNotifications, &p.MessageAlerts)
+	if err == sql.ErrNoRows {
+		return p, nil
+	}
+	return p, err
+}
+
+// CanManageThread returns true only for group threads whose travel group the user owns or co-administers.
+func (r *PostgresRepository) CanManageThread(ctx context.Context, threadID, userID string) (bool, error) {
+	var ok bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM message_threads mt
+			JOIN group_members gm ON gm.group_id = mt.group_id
+			WHERE mt.id = $1 AND mt.type = 'group' AND gm.user_id = $2 AND gm.role IN ('owner', 'admin')
+		)
+	`, threadID, userID).Scan(&ok)
+	return ok, err
+}
+
+// PinMessage pins a message to its thread. Pinning an already pinned message is a no-op.
+// Returns ErrMessageNotInThread, ErrPinLimitReached or ErrThreadReadOnly when the pin is not allowed.
+func (r *PostgresRepository) PinMessage(ctx context.Context, threadID, messageID, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the thread so concurrent pins cannot both pass the limit check,
+	// and so the chat cannot be archived in between
+	var readOnly bool
+	if err := tx.QueryRowContext(ctx, `SELECT read_only FROM message_threads WHERE id = $1 FOR UPDATE`, threadID).Scan(&readOnly); err != nil {
+		return err
+	}
+	if readOnly {
+		return ErrThreadReadOnly
+	}
+
+	var inThread, pinned bool
+	var count int
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			EXISTS(SELECT 1 FROM messages WHERE id = $2 AND thread_id = $1 AND (expires_at IS NULL OR expires_at > NOW())),
+			EXISTS(SELECT 1 FROM pinned_messages WHERE thread_id = $1 AND message_id = $2),
+			(SELECT COUNT(*) FROM pinned_messages WHERE thread_id = $1)
+	`, threadID, messageID).Scan(&inThread, &pinned, &count)
+	if err != nil {
+		return err
+	}
+	if !inThread {
+		return ErrMessageNotInThread
+	}
+	if pinned {
+		return nil
+	}
+	if count >= MaxPinnedMessages {
+		return ErrPinLimitReached
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pinned_messages (thread_id, message_id, pinned_by)
+		VALUES ($1, $2, $3)
+	`, threadID, messageID, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UnpinMessage removes a pin. Returns sql.ErrNoRows if the message was not pinned.
+func (r *PostgresRepository) UnpinMessage(ctx context.Context, threadID, messageID string) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM pinned_messages WHERE thread_id = $1 AND message_id = $2
+	`, threadID, messageID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetPinnedMessages returns the thread's pinned messages, most recently pinned first.
+func (r *PostgresRepository) GetPinnedMessages(ctx context.Context, threadID string) ([]PinnedMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			m.id, m.thread_id, COALESCE(m.sender_id::text, ''), COALESCE(p.full_name, 'Deleted User'),
+			m.content, m.created_at, m.reply_to_id, m.is_forwarded, m.kind,
+			COALESCE(pm.pinned_by::text, ''), pm.pinned_at
+		FROM pinned_messages pm
+		JOIN messages m ON m.id = pm.message_id
+		LEFT JOIN profiles p ON p.user_id = m.sender_id
+		WHERE pm.thread_id = $1
+		  AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		ORDER BY pm.pinned_at DESC
+	`, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pins []PinnedMessage
+	for rows.Next() {
+		var pm PinnedMessage
+		if err := rows.Scan(&pm.ID, &pm.ThreadID, &pm.SenderID, &pm.SenderName, &pm.Content,
+			&pm.CreatedAt, &pm.ReplyToID, &pm.IsForwarded, &pm.Kind, &pm.PinnedBy, &pm.PinnedAt); err != nil {
+			return nil, err
+		}
+		pins = append(pins, pm)
+	}
+	return pins, rows.Err()
+}
+
 // IsParticipant checks whether a user belongs to a thread.
 func (r *PostgresRepository) IsParticipant(ctx context.Context, threadID, userID string) (bool, error) {
 	var exists bool
@@ -450,6 +1331,30 @@ func (r *PostgresRepository) GetParticipantIDs(ctx context.Context, threadID str
 	return ids, rows.Err()
 }
 
+// GetParticipants returns the thread's members with their display names.
+func (r *PostgresRepository) GetParticipants(ctx context.Context, threadID string) ([]Participant, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tp.user_id, COALESCE(p.full_name, '')
+		FROM thread_participants tp
+		LEFT JOIN profiles p ON p.user_id = tp.user_id
+		WHERE tp.thread_id = $1
+	`, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []Participant
+	for rows.Next() {
+		var p Participant
+		if err := rows.Scan(&p.UserID, &p.Name); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+	return participants, rows.Err()
+}
+
 // UpsertDeviceToken inserts or updates a device token for push notifications.
 func (r *PostgresRepository) UpsertDeviceToken(ctx context.Context, userID, token, platform string) error {
 	_, err := r.db.ExecContext(ctx, `
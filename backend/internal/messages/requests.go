@@ -0,0 +1,77 @@
+package messages
+
+import "time"
+
+// Message request quotas. A sender whose recent requests are mostly declined
+// is throttled to a much smaller daily allowance.
+const (
+	DailyRequestQuota     = 10
+	ThrottledRequestQuota = 2
+
+	// ThrottleWindow is how far back declines are counted.
+	ThrottleWindow = 30 * 24 * time.Hour
+	// ThrottleMinResponses avoids throttling on the first one or two declines.
+	ThrottleMinResponses = 5
+	// ThrottleDeclineRatio is the share of declined requests that triggers throttling.
+	ThrottleDeclineRatio = 0.6
+)
+
+// RequestStats summarises a user's recently sent message requests.
+type RequestStats struct {
+	SentToday int // requests sent in the last 24 hours
+	Responded int // requests answered within ThrottleWindow
+	Declined  int // of those, declined or declined and blocked
+}
+
+// Throttled reports whether recipients decline this sender too often.
+func (s RequestStats) Throttled() bool {
+	if s.Responded < ThrottleMinResponses {
+		return false
+	}
+	return float64(s.Declined)/float64(s.Responded) >= ThrottleDeclineRatio
+}
+
+// DailyLimit is how many requests the sender may send per 24 hours.
+func (s RequestStats) DailyLimit() int {
+	if s.Throttled() {
+		return ThrottledRequestQuota
+	}
+	return DailyRequestQuota
+}
+
+// Remaining is how many more requests the sender may send right now.
+func (s RequestStats) Remaining() int {
+	if n := s.DailyLimit() - s.SentToday; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// RequestQuota is the quota summary returned with the request inbox.
+type RequestQuota struct {
+	Limit     int  `json:"limit"`
+	Used      int  `json:"used"`
+	Remaining int  `json:"remaining"`
+	Throttled bool `json:"throttled"`
+}
+
+// Quota converts the stats into the client-facing summary.
+func (s RequestStats) Quota() RequestQuota {
+	return RequestQuota{
+		Limit:     s.DailyLimit(),
+		Used:      s.SentToday,
+		Remaining: s.Remaining(),
+		Throttled: s.Throttled(),
+	}
+}
+
+// RequestInbox is the response for GET /messages/requests.
+type RequestInbox struct {
+	Requests []ThreadSummary `json:"requests"`
+	Quota    RequestQuota    `json:"quota"`
+}
+
+// DeclineRequestBody is the optional payload for POST /messages/threads/{id}/decline.
+type DeclineRequestBody struct {
+	Block bool `json:"block"` // also block the sender
+}
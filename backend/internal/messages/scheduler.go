@@ -0,0 +1,84 @@
+package messages
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ScheduledClaimTimeout is how long a claimed message may stay in sending
+// before another dispatch picks it up again. Delivery is at least once: a
+// dispatcher that sent the message but died before recording it will cause
+// one duplicate.
+const ScheduledClaimTimeout = 5 * time.Minute
+
+// Scheduler periodically sends scheduled messages that have come due.
+type Scheduler struct {
+	repo      Repository
+	hub       *Hub
+	interval  time.Duration
+	batchSize int
+}
+
+// NewScheduler creates a Scheduler that polls every 15 seconds.
+func NewScheduler(repo Repository, hub *Hub) *Scheduler {
+	return &Scheduler{
+		repo:      repo,
+		hub:       hub,
+		interval:  15 * time.Second,
+		batchSize: 50,
+	}
+}
+
+// Run dispatches due messages until ctx is cancelled. Start it as a goroutine.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.DispatchDue(ctx)
+		}
+	}
+}
+
+// DispatchDue sends every message that is due now and returns how many were delivered.
+// Messages go through Hub.DeliverMessage, so the sender must still be allowed to post
+// in the thread when the message fires; otherwise it is marked failed.
+func (s *Scheduler) DispatchDue(ctx context.Context) int {
+	due, err := s.repo.ClaimDueScheduledMessages(ctx, time.Now(), s.batchSize)
+	if err != nil {
+		log.Printf("[Scheduler] Failed to claim due messages: %v", err)
+		return 0
+	}
+
+	sent := 0
+	for _, sm := range due {
+		msg, err := s.hub.DeliverMessage(ctx, sm.SenderID, SendMessageRequest{
+			ThreadID:  sm.ThreadID,
+			Content:   sm.Content,
+			ReplyToID: sm.ReplyToID,
+		})
+
+		var messageID *string
+		if err == nil {
+			messageID = &msg.ID
+			sent++
+			// The sender's other sessions should see the message appear too
+			s.hub.SendToUser(sm.SenderID, WSOutgoing{
+				Type:    "new_message",
+				Payload: WSNewMessage{Message: msg},
+			})
+		} else {
+			log.Printf("[Scheduler] Failed to deliver scheduled message %s: %v", sm.ID, err)
+		}
+
+		if err := s.repo.CompleteScheduledMessage(ctx, sm.ID, messageID, err); err != nil {
+			log.Printf("[Scheduler] Failed to record outcome for %s: %v", sm.ID, err)
+		}
+	}
+	return sent
+}
@@ -0,0 +1,65 @@
+package messages
+
+import "time"
+
+// Notification levels for a thread participant.
+const (
+	NotifyAll      = "all"
+	NotifyMentions = "mentions" // group threads only
+)
+
+// MaxMuteHours caps how long a timed mute may last.
+const MaxMuteHours = 24 * 365
+
+// MessageTTLOptions maps the disappearing message timers a thread can use to
+// their length in seconds. "off" keeps messages indefinitely.
+var MessageTTLOptions = map[string]int{
+	"off": 0,
+	"24h": 24 * 60 * 60,
+	"7d":  7 * 24 * 60 * 60,
+}
+
+// ThreadSettings are a participant's notification settings for one thread,
+// plus the thread-wide options that only a group admin can change
+// (either participant may change the timer of a direct chat).
+type ThreadSettings struct {
+	IsMuted           bool       `json:"is_muted"`
+	MutedUntil        *time.Time `json:"muted_until"` // nil while muted means "until unmuted"
+	NotificationLevel string     `json:"notification_level"`
+
+	// Thread-wide
+	NoForward         bool `json:"no_forward"`
+	MessageTTLSeconds int  `json:"message_ttl_seconds"`
+}
+
+// MutedAt reports whether the mute is in effect at the given time.
+// Timed mutes lapse on their own; nothing needs to clear them.
+func (s ThreadSettings) MutedAt(now time.Time) bool {
+	if !s.IsMuted {
+		return false
+	}
+	return s.MutedUntil == nil || now.Before(*s.MutedUntil)
+}
+
+// AllowsPush decides whether a message in this thread should produce a push
+// notification. Mentions break through mute and mentions-only mode.
+func (s ThreadSettings) AllowsPush(now time.Time, mentioned bool) bool {
+	if mentioned {
+		return true
+	}
+	if s.MutedAt(now) {
+		return false
+	}
+	return s.NotificationLevel != NotifyMentions
+}
+
+// PushPreferences are the account-wide toggles from user_preferences.
+type PushPreferences struct {
+	PushNotifications bool
+	MessageAlerts     bool
+}
+
+// AllowsMessagePush reports whether chat messages may be pushed at all.
+func (p PushPreferences) AllowsMessagePush() bool {
+	return p.PushNotifications && p.MessageAlerts
+}
@@ -0,0 +1,64 @@
+package messages
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Sweeper periodically hard-deletes disappearing messages that have expired.
+type Sweeper struct {
+	repo      Repository
+	hub       *Hub
+	interval  time.Duration
+	batchSize int
+}
+
+// NewSweeper creates a Sweeper that runs once a minute.
+func NewSweeper(repo Repository, hub *Hub) *Sweeper {
+	return &Sweeper{
+		repo:      repo,
+		hub:       hub,
+		interval:  time.Minute,
+		batchSize: 500,
+	}
+}
+
+// Run sweeps expired messages until ctx is cancelled. Start it as a goroutine.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.SweepExpired(ctx)
+		}
+	}
+}
+
+// SweepExpired deletes every message that has expired by now, batch by batch,
+// and tells connected participants about each deletion. It returns how many
+// messages were removed.
+func (s *Sweeper) SweepExpired(ctx context.Context) int {
+	now := time.Now()
+	deleted := 0
+	for {
+		expired, err := s.repo.DeleteExpiredMessages(ctx, now, s.batchSize)
+		if err != nil {
+			log.Printf("[Sweeper] Failed to delete expired messages: %v", err)
+			return deleted
+		}
+
+		for _, e := range expired {
+			s.hub.BroadcastMessageDeleted(ctx, e.ThreadID, e.ID)
+		}
+		deleted += len(expired)
+
+		if len(expired) < s.batchSize || ctx.Err() != nil {
+			return deleted
+		}
+	}
+}
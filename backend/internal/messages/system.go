@@ -0,0 +1,37 @@
+package messages
+
+// Message kinds. KindText is a normal user message; the others are system
+// messages posted by the server into group threads.
+const (
+	KindText                = "text"
+	KindMemberJoined        = "member_joined"
+	KindMemberLeft          = "member_left"
+	KindMemberKicked        = "member_kicked"
+	KindGroupRenamed        = "group_renamed"
+	KindMeetingPointChanged = "meeting_point_changed"
+	KindItineraryUpdated    = "itinerary_updated"
+	KindRoleChanged         = "role_changed"
+)
+
+// systemContent is the plain-text fallback stored in content for clients that
+// don't render a kind yet. It also shows up as the thread's last message.
+var systemContent = map[string]string{
+	KindMemberJoined:        "joined the group",
+	KindMemberLeft:          "left the group",
+	KindMemberKicked:        "removed a member",
+	KindGroupRenamed:        "renamed the group",
+	KindMeetingPointChanged: "changed the meeting point",
+	KindItineraryUpdated:    "updated the itinerary",
+	KindRoleChanged:         "changed a member's role",
+}
+
+// IsSystemKind reports whether kind is a known system message kind.
+func IsSystemKind(kind string) bool {
+	_, ok := systemContent[kind]
+	return ok
+}
+
+// IsSystem reports whether m was posted by the server rather than typed by a user.
+func (m Message) IsSystem() bool {
+	return m.Kind != "" && m.Kind != KindText
+}
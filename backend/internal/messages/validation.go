@@ -1,14 +1,35 @@
 package messages
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrContentTooLong = errors.New("message content exceeds 5000 characters")
 	ErrContentEmpty   = errors.New("message content is empty")
 	ErrNotParticipant = errors.New("user is not a participant of this thread")
 	ErrBlocked        = errors.New("user is blocked")
+
+	ErrRequestLimitReached  = errors.New("request message limit reached (10 messages)")
+	ErrRequestQuotaExceeded = errors.New("daily message request limit reached")
+	ErrForwardingDisabled   = errors.New("forwarding is disabled in this thread")
+	ErrThreadReadOnly       = errors.New("this chat is read-only")
+	ErrContentRejected      = errors.New("message was blocked by the content filter")
+
+	ErrPinLimitReached    = errors.New("thread already has the maximum number of pinned messages")
+	ErrMessageNotInThread = errors.New("message does not belong to this thread")
 )
 
+// MaxScheduleAhead is how far in the future a message may be scheduled.
+const MaxScheduleAhead = 30 * 24 * time.Hour
+
+// MaxForwardTargets is how many threads a message can be forwarded to in one request.
+const MaxForwardTargets = 5
+
+// MaxPinnedMessages is the number of messages a single thread may have pinned at once.
+const MaxPinnedMessages = 5
+
 // ValidateContent checks message content constraints.
 func ValidateContent(content string) error {
 	if len(content) == 0 {
@@ -18,7 +18,8 @@ var upgrader = websocket.Upgrader{
 
 // ServeWS handles WebSocket upgrade requests.
 // Auth is done via JWT passed as query param: /ws?token=xxx
-func ServeWS(hub *Hub) http.HandlerFunc {
+// Blocked and suspended users are turned away before the upgrade.
+func ServeWS(hub *Hub, users auth.Repository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// 1. Validate JWT from query parameter
 		token := r.URL.Query().Get("token")
@@ -34,6 +35,9 @@ func ServeWS(hub *Hub) http.HandlerFunc {
 		}
 
 		userID := claims.UserID
+		if !auth.CheckUserStatus(w, r, users, userID) {
+			return
+		}
 
 		// 2. Upgrade HTTP → WebSocket
 		conn, err := upgrader.Upgrade(w, r, nil)
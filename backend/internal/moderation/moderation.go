@@ -0,0 +1,232 @@
+// Package moderation screens user-written text (chat messages, bios and group
+// descriptions) against a reloadable set of wordlist and regex rules.
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Input is a piece of text to check and where it is going.
+type Input struct {
+	Field         string // FieldMessage, FieldBio or FieldGroupDescription
+	Text          string
+	RequestThread bool // a message sent in a message request thread
+}
+
+// Match records a rule that fired.
+type Match struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+}
+
+// Verdict is the outcome of checking an Input.
+type Verdict struct {
+	Action  string // the most severe action among the matches, or ActionAllow
+	Text    string // the input with masked spans replaced by asterisks
+	Matches []Match
+}
+
+// Rejected reports whether the content must be refused.
+func (v Verdict) Rejected() bool { return v.Action == ActionReject }
+
+// Flagged reports whether the content should be queued for admin review.
+func (v Verdict) Flagged() bool {
+	for _, m := range v.Matches {
+		if m.Action == ActionFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// Flag identifies stored content that a flag rule matched.
+type Flag struct {
+	TargetType string // "message", "user" (bio) or "group" (description)
+	TargetID   string
+	UserID     string // author of the content
+	ThreadID   *string
+	Content    string
+	Category   string
+	Reason     string
+}
+
+// Flagger records flagged content for review.
+type Flagger interface {
+	Flag(ctx context.Context, f Flag) error
+}
+
+// Moderator checks content against the current rules. Rules can be swapped
+// at any time; checks in flight keep using the rules they started with.
+type Moderator struct {
+	rules   atomic.Pointer[ruleset]
+	flagger Flagger
+
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+}
+
+// New returns a Moderator with only the built-in contact leak rules.
+func New() *Moderator {
+	m := &Moderator{}
+	rs, _ := compile(Config{})
+	m.rules.Store(rs)
+	return m
+}
+
+// SetFlagger sets where flagged content is recorded. Call it before serving requests.
+func (m *Moderator) SetFlagger(f Flagger) { m.flagger = f }
+
+// SetRules compiles cfg and makes it the active rule set.
+func (m *Moderator) SetRules(cfg Config) error {
+	rs, err := compile(cfg)
+	if err != nil {
+		return err
+	}
+	m.rules.Store(rs)
+	return nil
+}
+
+// LoadFile reads rules from a JSON file and remembers the path for Reload and Watch.
+func (m *Moderator) LoadFile(path string) error {
+	m.mu.Lock()
+	m.path = path
+	m.mu.Unlock()
+	return m.Reload()
+}
+
+// Reload re-reads the rules file. The current rules stay active if the file is invalid.
+// It is a no-op when no file was loaded.
+func (m *Moderator) Reload() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	if err := m.SetRules(cfg); err != nil {
+		return err
+	}
+	m.modTime = info.ModTime()
+	return nil
+}
+
+// RuleCount returns how many rules are active, including built-in ones.
+func (m *Moderator) RuleCount() int { return len(m.rules.Load().rules) }
+
+// Watch reloads the rules file whenever it changes, until ctx is cancelled.
+// Start it as a goroutine.
+func (m *Moderator) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			path, seen := m.path, m.modTime
+			m.mu.Unlock()
+			if path == "" {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(seen) {
+				continue
+			}
+			if err := m.Reload(); err != nil {
+				log.Printf("[Moderation] Keeping previous rules, reload failed: %v", err)
+				continue
+			}
+			log.Printf("[Moderation] Reloaded %d rules from %s", m.RuleCount(), path)
+		}
+	}
+}
+
+// Check runs every applicable rule over the input.
+func (m *Moderator) Check(in Input) Verdict {
+	v := Verdict{Action: ActionAllow, Text: in.Text}
+	var masked [][]int
+	for _, r := range m.rules.Load().rules {
+		if !r.appliesTo(in) {
+			continue
+		}
+		locs := r.find(in.Text)
+		if len(locs) == 0 {
+			continue
+		}
+		v.Matches = append(v.Matches, Match{Rule: r.name, Action: r.action})
+		if severity[r.action] > severity[v.Action] {
+			v.Action = r.action
+		}
+		if r.action == ActionMask {
+			masked = append(masked, locs...)
+		}
+	}
+	if len(masked) > 0 {
+		v.Text = mask(in.Text, masked)
+	}
+	return v
+}
+
+// FlagForReview records the content if any flag rule matched. Failures are
+// logged rather than returned; the content itself has already been saved.
+func (m *Moderator) FlagForReview(ctx context.Context, v Verdict, f Flag) {
+	if !v.Flagged() || m.flagger == nil {
+		return
+	}
+	var rules []string
+	for _, match := range v.Matches {
+		if match.Action == ActionFlag {
+			rules = append(rules, match.Rule)
+		}
+	}
+	if f.Category == "" {
+		f.Category = "inappropriate"
+	}
+	f.Reason = "matched " + strings.Join(rules, ", ")
+	if err := m.flagger.Flag(ctx, f); err != nil {
+		log.Printf("[Moderation] Failed to flag %s %s: %v", f.TargetType, f.TargetID, err)
+	}
+}
+
+// mask replaces every rune inside the given byte ranges with '*'.
+func mask(s string, ranges [][]int) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i, r := range s {
+		hidden := false
+		for _, loc := range ranges {
+			if i >= loc[0] && i < loc[1] {
+				hidden = true
+				break
+			}
+		}
+		if hidden && r != ' ' {
+			b.WriteByte('*')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package moderation
+
+import (
+	"context"
+	"database/sql"
+)
+
+// PostgresFlagger files flagged content into the admin reports queue as
+// automated reports with no reporter.
+type PostgresFlagger struct {
+	db *sql.DB
+}
+
+// NewFlagger returns a Flagger backed by the reports table.
+func NewFlagger(db *sql.DB) Flagger {
+	return &PostgresFlagger{db: db}
+}
+
+func (f *PostgresFlagger) Flag(ctx context.Context, fl Flag) error {
+	_, err := f.db.ExecContext(ctx, `
+		INSERT INTO reports (source, target_type, target_id, reported_user_id, thread_id, category, details, content_snapshot)
+		VALUES ('automated', $1, $2, $3, $4, $5, $6, $7)
+	`, fl.TargetType, fl.TargetID, fl.UserID, fl.ThreadID, fl.Category, fl.Reason, fl.Content)
+	return err
+}
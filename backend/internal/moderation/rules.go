@@ -0,0 +1,155 @@
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Actions a rule can take, from least to most severe.
+const (
+	ActionAllow  = "allow"
+	ActionFlag   = "flag"   // keep the content but queue it for admin review
+	ActionMask   = "mask"   // replace the matched text with asterisks
+	ActionReject = "reject" // refuse the content outright
+)
+
+var severity = map[string]int{
+	ActionAllow:  0,
+	ActionFlag:   1,
+	ActionMask:   2,
+	ActionReject: 3,
+}
+
+// Fields that content can be checked for.
+const (
+	FieldMessage          = "message"
+	FieldBio              = "bio"
+	FieldGroupDescription = "group_description"
+)
+
+var knownFields = map[string]bool{
+	FieldMessage:          true,
+	FieldBio:              true,
+	FieldGroupDescription: true,
+}
+
+// Config is the JSON rules file format.
+type Config struct {
+	Rules []RuleConfig `json:"rules"`
+	// ContactLeakAction applies to phone numbers and UPI IDs in message request
+	// threads. Defaults to mask; "allow" turns the check off.
+	ContactLeakAction string `json:"contact_leak_action,omitempty"`
+}
+
+// RuleConfig is a single wordlist or regex rule. Exactly one of Words or Pattern is set.
+type RuleConfig struct {
+	Name    string   `json:"name"`
+	Words   []string `json:"words,omitempty"`   // matched as whole words, case-insensitively
+	Pattern string   `json:"pattern,omitempty"` // Go regexp syntax
+	Action  string   `json:"action"`
+	Fields  []string `json:"fields,omitempty"` // empty applies the rule to every field
+}
+
+type rule struct {
+	name        string
+	action      string
+	fields      map[string]bool // nil means all fields
+	requestOnly bool            // only in message request threads
+	find        func(s string) [][]int
+}
+
+func (r rule) appliesTo(in Input) bool {
+	if r.requestOnly && (in.Field != FieldMessage || !in.RequestThread) {
+		return false
+	}
+	return r.fields == nil || r.fields[in.Field]
+}
+
+type ruleset struct {
+	rules []rule
+}
+
+// compile validates cfg and builds the rules it describes, followed by the built-in contact leak rules.
+func compile(cfg Config) (*ruleset, error) {
+	rs := &ruleset{}
+	for i, rc := range cfg.Rules {
+		name := rc.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		if _, ok := severity[rc.Action]; !ok || rc.Action == ActionAllow {
+			return nil, fmt.Errorf("%s: action must be reject, mask or flag", name)
+		}
+
+		var re *regexp.Regexp
+		var err error
+		switch {
+		case len(rc.Words) > 0 && rc.Pattern == "":
+			quoted := make([]string, 0, len(rc.Words))
+			for _, w := range rc.Words {
+				if w = strings.TrimSpace(w); w != "" {
+					quoted = append(quoted, regexp.QuoteMeta(w))
+				}
+			}
+			re, err = regexp.Compile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+		case rc.Pattern != "" && len(rc.Words) == 0:
+			re, err = regexp.Compile(rc.Pattern)
+		default:
+			return nil, fmt.Errorf("%s: set exactly one of words or pattern", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		r := rule{name: name, action: rc.Action, find: allMatches(re)}
+		if len(rc.Fields) > 0 {
+			r.fields = map[string]bool{}
+			for _, f := range rc.Fields {
+				if !knownFields[f] {
+					return nil, fmt.Errorf("%s: unknown field %q", name, f)
+				}
+				r.fields[f] = true
+			}
+		}
+		rs.rules = append(rs.rules, r)
+	}
+
+	contactAction := cfg.ContactLeakAction
+	if contactAction == "" {
+		contactAction = ActionMask
+	}
+	if _, ok := severity[contactAction]; !ok {
+		return nil, fmt.Errorf("contact_leak_action must be allow, reject, mask or flag")
+	}
+	if contactAction != ActionAllow {
+		rs.rules = append(rs.rules,
+			rule{name: "phone_number", action: contactAction, requestOnly: true, find: allMatches(phonePattern)},
+			rule{name: "upi_id", action: contactAction, requestOnly: true, find: findUPIIDs},
+		)
+	}
+	return rs, nil
+}
+
+func allMatches(re *regexp.Regexp) func(string) [][]int {
+	return func(s string) [][]int { return re.FindAllStringIndex(s, -1) }
+}
+
+// phonePattern matches Indian mobile numbers, optionally with a +91 prefix and a
+// separator in the middle ("98765 43210", "+91-9876543210").
+var phonePattern = regexp.MustCompile(`(?:\+91[\s-]?|\b)[6-9]\d{4}[\s-]?\d{5}\b`)
+
+var upiPattern = regexp.MustCompile(`\b[\w.\-]{2,256}@[A-Za-z]{2,64}\b`)
+
+// findUPIIDs matches handles such as "name@okaxis" but not email addresses,
+// whose domain continues with a dot.
+func findUPIIDs(s string) [][]int {
+	var out [][]int
+	for _, loc := range upiPattern.FindAllStringIndex(s, -1) {
+		if loc[1] < len(s) && s[loc[1]] == '.' {
+			continue
+		}
+		out = append(out, loc)
+	}
+	return out
+}
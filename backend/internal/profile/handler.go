@@ -2,23 +2,27 @@ package profile
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/muskan953/college-Hop/internal/auth"
 	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/moderation"
 )
 
 type Handler struct {
-	repo     Repository
-	authRepo auth.Repository
-	msgRepo  messages.Repository
+	repo      Repository
+	authRepo  auth.Repository
+	msgRepo   messages.Repository
+	moderator *moderation.Moderator
 }
 
-func NewHandler(repo Repository, authRepo auth.Repository, msgRepo messages.Repository) *Handler {
-	return &Handler{repo: repo, authRepo: authRepo, msgRepo: msgRepo}
+func NewHandler(repo Repository, authRepo auth.Repository, msgRepo messages.Repository, moderator *moderation.Moderator) *Handler {
+	return &Handler{repo: repo, authRepo: authRepo, msgRepo: msgRepo, moderator: moderator}
 }
 
 // IsValidUploadURL checks that the URL is a valid absolute URL.
@@ -82,6 +86,24 @@ func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Gender and home city are optional and only used for group matching.
+	// Clients that predate them leave them out, which keeps the stored values
+	if req.Gender != nil {
+		*req.Gender = strings.TrimSpace(*req.Gender)
+		genders := map[string]bool{"": true, "female": true, "male": true, "non_binary": true}
+		if !genders[*req.Gender] {
+			http.Error(w, "gender must be one of: female, male, non_binary", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.HomeCity != nil {
+		*req.HomeCity = strings.TrimSpace(*req.HomeCity)
+		if len(*req.HomeCity) > 100 {
+			http.Error(w, "home_city too long (max 100 chars)", http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Validate URL fields
 	if !IsValidUploadURL(req.ProfilePhotoURL) {
 		http.Error(w, "invalid profile_photo_url: must be a valid URL", http.StatusBadRequest)
@@ -108,12 +130,26 @@ func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	verdict := h.moderator.Check(moderation.Input{Field: moderation.FieldBio, Text: req.Bio})
+	if verdict.Rejected() {
+		http.Error(w, "bio was blocked by the content filter", http.StatusBadRequest)
+		return
+	}
+	req.Bio = verdict.Text
+
 	err := h.repo.UpsertProfile(r.Context(), user.ID, req)
 	if err != nil {
 		http.Error(w, "failed to update profile", http.StatusInternalServerError)
 		return
 	}
 
+	h.moderator.FlagForReview(r.Context(), verdict, moderation.Flag{
+		TargetType: "user",
+		TargetID:   user.ID,
+		UserID:     user.ID,
+		Content:    req.Bio,
+	})
+
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("profile updated"))
 }
@@ -254,25 +290,71 @@ func (h *Handler) ConnectUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 1. Create a pending connection
-	err := h.repo.CreateConnection(r.Context(), user.ID, targetID, "pending", &user.ID)
+	blocked, err := h.msgRepo.IsBlocked(r.Context(), user.ID, targetID)
+	if err != nil {
+		http.Error(w, "failed to create connection request", http.StatusInternalServerError)
+		return
+	}
+	if blocked {
+		http.Error(w, "cannot connect with this user", http.StatusForbidden)
+		return
+	}
+
+	// Daily request quota; senders who are often declined get a smaller one.
+	// Checked up front so a sender over quota creates nothing
+	stats, err := h.msgRepo.GetRequestStats(r.Context(), user.ID, time.Now())
 	if err != nil {
 		http.Error(w, "failed to create connection request", http.StatusInternalServerError)
 		return
 	}
+	if stats.Remaining() == 0 {
+		http.Error(w, "daily message request limit reached", http.StatusTooManyRequests)
+		return
+	}
+
+	verdict := h.moderator.Check(moderation.Input{
+		Field:         moderation.FieldMessage,
+		Text:          req.Message,
+		RequestThread: true,
+	})
+	if verdict.Rejected() {
+		http.Error(w, "message was blocked by the content filter", http.StatusBadRequest)
+		return
+	}
 
-	// 2. Create the request thread
-	thread, err := h.msgRepo.GetOrCreateDirectThread(r.Context(), user.ID, targetID, true)
+	// 1. Create the request thread and record the request; this is where the
+	// daily quota is enforced, since the check above can race with another
+	// request from the same sender
+	thread, err := h.msgRepo.OpenMessageRequest(r.Context(), user.ID, targetID)
+	if errors.Is(err, messages.ErrRequestQuotaExceeded) {
+		http.Error(w, "daily message request limit reached", http.StatusTooManyRequests)
+		return
+	}
 	if err != nil {
 		http.Error(w, "failed to create message thread", http.StatusInternalServerError)
 		return
 	}
 
+	// 2. Create a pending connection
+	err = h.repo.CreateConnection(r.Context(), user.ID, targetID, "pending", &user.ID)
+	if err != nil {
+		http.Error(w, "failed to create connection request", http.StatusInternalServerError)
+		return
+	}
+
 	// 3. Insert the first message into the thread
-	_, err = h.msgRepo.CreateMessage(r.Context(), thread.ID, user.ID, req.Message, nil, false)
+	msg, err := h.msgRepo.CreateMessage(r.Context(), thread.ID, user.ID, verdict.Text, nil, false)
 	if err != nil {
 		// Log error but don't fail the who request since the connection was created
 		log.Printf("[ConnectUser] Failed to send initial message: %v", err)
+	} else {
+		h.moderator.FlagForReview(r.Context(), verdict, moderation.Flag{
+			TargetType: "message",
+			TargetID:   msg.ID,
+			UserID:     user.ID,
+			ThreadID:   &msg.ThreadID,
+			Content:    msg.Content,
+		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/muskan953/college-Hop/internal/groups"
 )
 
 type Repository interface {
@@ -115,6 +117,17 @@ func (r *PostgresRepository) UpsertProfile(ctx context.Context, userID string, r
 		return err
 	}
 
+	// A NULL parameter means the field was not sent, so the stored value is kept
+	_, err = tx.ExecContext(ctx, `
+		UPDATE profiles SET
+			gender = CASE WHEN $1::text IS NULL THEN gender ELSE NULLIF($1, '') END,
+			home_city = CASE WHEN $2::text IS NULL THEN home_city ELSE NULLIF($2, '') END
+		WHERE user_id = $3
+	`, req.Gender, req.HomeCity, userID)
+	if err != nil {
+		return err
+	}
+
 	// Reset verification status to pending when ID card URL or expiry date changes.
 	// Both are visible on the physical ID card an admin reviews, so changing either
 	// invalidates the previous verification.
@@ -171,6 +184,7 @@ func (r *PostgresRepository) GetProfile(ctx context.Context, userID string) (*Pr
 	var idExpiration sql.NullTime
 	var idCardUploadedAt sql.NullTime
 	var fullName, collegeName, major, rollNumber, bio, profilePhotoURL, idCardURL, alternateEmail sql.NullString
+	var gender, homeCity sql.NullString
 
 	err := r.db.QueryRowContext(ctx, `
 		SELECT
@@ -183,6 +197,8 @@ func (r *PostgresRepository) GetProfile(ctx context.Context, userID string) (*Pr
 			COALESCE(p.profile_photo_url, ''),
 			COALESCE(p.college_id_card_url, ''),
 			COALESCE(p.alternate_email, ''),
+			p.gender,
+			p.home_city,
 			COALESCE(u.status, ''),
 			p.id_card_uploaded_at,
 			(u.status = 'verified' AND p.id_expiration IS NOT NULL AND p.id_expiration < NOW()) AS is_alumni
@@ -199,6 +215,8 @@ func (r *PostgresRepository) GetProfile(ctx context.Context, userID string) (*Pr
 		&profilePhotoURL,
 		&idCardURL,
 		&alternateEmail,
+		&gender,
+		&homeCity,
 		&profile.Status,
 		&idCardUploadedAt,
 		&profile.IsAlumni,
@@ -218,6 +236,8 @@ func (r *PostgresRepository) GetProfile(ctx context.Context, userID string) (*Pr
 	profile.ProfilePhotoURL = profilePhotoURL.String
 	profile.IDCardURL = idCardURL.String
 	profile.AlternateEmail = alternateEmail.String
+	profile.Gender = gender.String
+	profile.HomeCity = homeCity.String
 
 	// Keep IDExpiration for display purposes (informational, not used for badge logic)
 	if idExpiration.Valid {
@@ -338,6 +358,8 @@ func (r *PostgresRepository) GetPreferences(ctx context.Context, userID string)
 func (r *PostgresRepository) GetPublicProfile(ctx context.Context, userID string) (*PublicProfileResponse, error) {
 	var p PublicProfileResponse
 	var fullName, collegeName, major, bio, photoURL sql.NullString
+	var ratings int
+	var punctuality, communication float64
 
 	err := r.db.QueryRowContext(ctx, `
 		SELECT
@@ -347,12 +369,17 @@ func (r *PostgresRepository) GetPublicProfile(ctx context.Context, userID string
 			COALESCE(p.bio, ''),
 			COALESCE(p.profile_photo_url, ''),
 			COALESCE(u.status, 'pending') = 'verified' AS is_verified,
-			(u.status = 'verified' AND p.id_expiration IS NOT NULL AND p.id_expiration < NOW()) AS is_alumni
+			(u.status = 'verified' AND p.id_expiration IS NOT NULL AND p.id_expiration < NOW()) AS is_alumni,
+			COALESCE(t.ratings, 0),
+			COALESCE(t.punctuality, 0),
+			COALESCE(t.communication, 0)
 		FROM users u
 		LEFT JOIN profiles p ON p.user_id = u.id
+		LEFT JOIN user_trust_scores t ON t.user_id = u.id
 		WHERE u.id = $1
 	`, userID).Scan(
 		&fullName, &collegeName, &major, &bio, &photoURL, &p.IsVerified, &p.IsAlumni,
+		&ratings, &punctuality, &communication,
 	)
 	if err == sql.ErrNoRows {
 		return nil, sql.ErrNoRows
@@ -367,6 +394,7 @@ func (r *PostgresRepository) GetPublicProfile(ctx context.Context, userID string
 	p.Major = major.String
 	p.Bio = bio.String
 	p.ProfilePhotoURL = photoURL.String
+	p.TrustScore = groups.NewTrustScore(ratings, punctuality, communication)
 
 	// Fetch interests
 	rows, err := r.db.QueryContext(ctx, `
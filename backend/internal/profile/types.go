@@ -1,5 +1,7 @@
 package profile
 
+import "github.com/muskan953/college-Hop/internal/groups"
+
 type UpdateProfileRequest struct {
 	FullName        string   `json:"full_name"`
 	CollegeName     string   `json:"college_name"`
@@ -10,6 +12,8 @@ type UpdateProfileRequest struct {
 	ProfilePhotoURL string   `json:"profile_photo_url"`
 	IDCardURL       string   `json:"college_id_card_url"`
 	AlternateEmail  string   `json:"alternate_email"`
+	Gender          *string  `json:"gender"`    // nil leaves it unchanged, "" clears it
+	HomeCity        *string  `json:"home_city"` // nil leaves it unchanged, "" clears it
 	Interests       []string `json:"interests"`
 }
 
@@ -39,6 +43,8 @@ type ProfileResponse struct {
 	IDCardURL        string   `json:"college_id_card_url"`
 	IDCardUploadedAt string   `json:"id_card_uploaded_at,omitempty"`
 	AlternateEmail   string   `json:"alternate_email"`
+	Gender           string   `json:"gender,omitempty"`
+	HomeCity         string   `json:"home_city,omitempty"`
 	Interests        []string `json:"interests"`
 	Status           string   `json:"status"`
 	IsAlumni         bool     `json:"is_alumni"`
@@ -58,6 +64,9 @@ type PublicProfileResponse struct {
 	Interests       []string `json:"interests"`
 	IsAlumni        bool     `json:"is_alumni"`
 	IsVerified      bool     `json:"is_verified"`
+	// TrustScore aggregates post-trip ratings from other members; nil until
+	// the user has groups.MinTrustRatings ratings.
+	TrustScore *groups.TrustScore `json:"trust_score,omitempty"`
 }
 
 type UpdatePreferencesRequest struct {
@@ -0,0 +1,389 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/muskan953/college-Hop/internal/admin"
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/email"
+	"github.com/muskan953/college-Hop/internal/events"
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/moderation"
+	"github.com/muskan953/college-Hop/internal/profile"
+	"github.com/muskan953/college-Hop/internal/upload"
+	"github.com/muskan953/college-Hop/pkg/storage"
+)
+
+func NewRouter(authRepo auth.Repository, emailService email.Service, profileRepo profile.Repository, adminRepo admin.Repository, eventsRepo events.Repository, groupsRepo groups.Repository, messagesRepo messages.Repository, hub *messages.Hub, store storage.FileStorage, uploadDir string, db *sql.DB, moderator *moderation.Moderator, scorer groups.Scorer) *http.ServeMux {
+	mux := http.NewServeMux()
+	if moderator == nil {
+		moderator = moderation.New()
+	}
+
+	// authMW is the full auth middleware: validates JWT + rejects blocked users.
+	authMW := auth.NewAuthMiddleware(authRepo)
+
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+
+	// Serve admin panel UI (no auth — access is controlled by admin secret in the UI itself)
+	mux.HandleFunc("/admin-panel", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "./admin-panel/index.html")
+	})
+
+	authHandler := auth.NewHandler(authRepo, emailService)
+
+	mux.HandleFunc("/auth/signup", authHandler.Signup)
+	mux.HandleFunc("/auth/login", authHandler.Login)
+	mux.HandleFunc("/auth/verify", authHandler.Verify)
+	mux.HandleFunc("/auth/refresh", authHandler.Refresh)
+	mux.HandleFunc("/auth/logout", authHandler.Logout)
+
+	profileHandler := profile.NewHandler(profileRepo, authRepo, messagesRepo, moderator)
+
+	mux.Handle("/me", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			profileHandler.GetMe(w, r)
+			return
+		}
+		if r.Method == http.MethodPut {
+			profileHandler.UpdateMe(w, r)
+			return
+		}
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	})))
+
+	mux.Handle("/me/preferences", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			profileHandler.GetPreferences(w, r)
+			return
+		}
+		if r.Method == http.MethodPut {
+			profileHandler.UpdatePreferences(w, r)
+			return
+		}
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	})))
+
+	// Protected: alternate email verification
+	mux.Handle("/me/alternate-email/request-otp", authMW(http.HandlerFunc(profileHandler.RequestAlternateEmailOTP)))
+	mux.Handle("/me/alternate-email/verify", authMW(http.HandlerFunc(profileHandler.VerifyAlternateEmail)))
+
+	// Protected: get user connections
+	mux.Handle("/me/connections", authMW(http.HandlerFunc(profileHandler.GetConnections)))
+
+	// Protected: get blocked users list
+	mux.Handle("/me/blocked", authMW(http.HandlerFunc(profileHandler.GetBlockedUsers)))
+
+	// Upload route (protected by auth)
+	uploadHandler := upload.NewHandler(store)
+	mux.Handle("/upload", authMW(http.HandlerFunc(uploadHandler.Upload)))
+
+	// Serve uploaded files
+	// Profile photos are public
+	mux.Handle("/uploads/profile_photo/", http.StripPrefix("/uploads", upload.ServeFile(uploadDir)))
+	// ID cards are private (require authentication)
+	mux.Handle("/uploads/id_card/", authMW(http.StripPrefix("/uploads", upload.ServeFile(uploadDir))))
+	// Admin can view ID cards using admin secret (no JWT needed)
+	mux.Handle("/admin/uploads/", admin.AdminAuth(http.StripPrefix("/admin/uploads", upload.ServeFile(uploadDir))))
+
+	// Admin routes (protected by admin secret)
+	adminHandler := admin.NewHandler(adminRepo, hub, moderator)
+	seedHandler := admin.NewSeedHandler(db)
+	mux.Handle("/admin/users/pending", admin.AdminAuth(http.HandlerFunc(adminHandler.ListPendingUsers)))
+	mux.Handle("/admin/users/", admin.AdminAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Route: /admin/users/{id}/verify or /admin/users/{id}/block
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		path := r.URL.Path
+		if len(path) > 0 && path[len(path)-1] == '/' {
+			path = path[:len(path)-1]
+		}
+		if len(path) > 7 && path[len(path)-7:] == "/verify" {
+			adminHandler.VerifyUser(w, r)
+			return
+		}
+		if len(path) > 6 && path[len(path)-6:] == "/block" {
+			adminHandler.BlockUser(w, r)
+			return
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+	})))
+	mux.Handle("/admin/reports", admin.AdminAuth(http.HandlerFunc(adminHandler.ListReports)))
+	mux.Handle("/admin/reports/", admin.AdminAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Route: /admin/reports/{id}/resolve
+		if strings.HasSuffix(r.URL.Path, "/resolve") {
+			adminHandler.ResolveReport(w, r)
+			return
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+	})))
+	mux.Handle("/admin/moderation/reload", admin.AdminAuth(http.HandlerFunc(adminHandler.ReloadModerationRules)))
+	mux.Handle("/admin/seed", admin.AdminAuth(http.HandlerFunc(seedHandler.SeedDummyData)))
+	mux.Handle("/admin/seed/clear", admin.AdminAuth(http.HandlerFunc(seedHandler.ClearDummyData)))
+
+	// Protected: report a message, user, group or event to the admins
+	mux.Handle("/reports", authMW(http.HandlerFunc(adminHandler.CreateReport)))
+
+	// --- Events routes ---
+	eventsHandler := events.NewHandler(eventsRepo)
+
+	// Public: list approved events
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			eventsHandler.ListEvents(w, r)
+			return
+		}
+		// Protected: create event (any auth user)
+		authMW(http.HandlerFunc(eventsHandler.CreateEvent)).ServeHTTP(w, r)
+	})
+
+	// Protected: set/get user's selected event
+	mux.Handle("/me/event", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			eventsHandler.SetUserEvent(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			eventsHandler.GetUserEvent(w, r)
+			return
+		}
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	})))
+
+	// Protected: get all user events
+	mux.Handle("/me/events", authMW(http.HandlerFunc(eventsHandler.GetUserEvents)))
+
+	// Admin: pending events + approve/reject
+	mux.Handle("/admin/events/pending", admin.AdminAuth(http.HandlerFunc(eventsHandler.ListPendingEvents)))
+	mux.Handle("/admin/events/", admin.AdminAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if strings.HasSuffix(path, "/approve") {
+			eventsHandler.ApproveEvent(w, r)
+			return
+		}
+		if strings.HasSuffix(path, "/reject") {
+			eventsHandler.RejectEvent(w, r)
+			return
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+	})))
+
+	// --- Groups routes ---
+	groupsHandler := groups.NewHandler(groupsRepo, hub, moderator, scorer)
+
+	// Protected: suggested groups
+	mux.Handle("/groups/suggested", authMW(http.HandlerFunc(groupsHandler.SuggestedGroups)))
+
+	// Protected: get all groups the user belongs to
+	mux.Handle("/me/groups", authMW(http.HandlerFunc(groupsHandler.GetMyGroups)))
+
+	// Protected: direct group invites waiting for the user
+	mux.Handle("/me/invites", authMW(http.HandlerFunc(groupsHandler.GetMyInvites)))
+
+	// Protected: join requests the user has sent
+	mux.Handle("/me/group-requests", authMW(http.HandlerFunc(groupsHandler.GetMyJoinRequests)))
+
+	// Protected: preview, accept or decline a group invite (/invites/{code}/...)
+	mux.Handle("/invites/", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/accept") && r.Method == http.MethodPost:
+			groupsHandler.AcceptInvite(w, r)
+		case strings.HasSuffix(path, "/decline") && r.Method == http.MethodPost:
+			groupsHandler.DeclineInvite(w, r)
+		case r.Method == http.MethodGet:
+			groupsHandler.GetInvite(w, r)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})))
+
+	// Protected: create group or list all groups
+	mux.Handle("/groups", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			groupsHandler.ListAllGroups(w, r)
+			return
+		}
+		if r.Method == http.MethodPost {
+			groupsHandler.CreateGroup(w, r)
+			return
+		}
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	})))
+
+	// Protected: group detail, update, delete, join, waitlist, leave, kick, lock, roles, itinerary, expenses, invites, ratings (/groups/{id}/...)
+	mux.Handle("/groups/", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/join") && r.Method == http.MethodPost:
+			groupsHandler.JoinGroup(w, r)
+		case strings.HasSuffix(path, "/join") && r.Method == http.MethodDelete:
+			groupsHandler.CancelJoinRequest(w, r)
+		case strings.HasSuffix(path, "/waitlist") && r.Method == http.MethodGet:
+			groupsHandler.GetWaitlist(w, r)
+		case strings.HasSuffix(path, "/waitlist") && r.Method == http.MethodDelete:
+			groupsHandler.LeaveWaitlist(w, r)
+		case strings.HasSuffix(path, "/waitlist/claim") && r.Method == http.MethodPost:
+			groupsHandler.ClaimWaitlistSpot(w, r)
+		case strings.HasSuffix(path, "/leave") && r.Method == http.MethodPost:
+			groupsHandler.LeaveGroup(w, r)
+		case strings.HasSuffix(path, "/kick") && r.Method == http.MethodPost:
+			groupsHandler.KickMember(w, r)
+		case strings.HasSuffix(path, "/lock") && r.Method == http.MethodPost:
+			groupsHandler.LockGroup(w, r)
+		case strings.HasSuffix(path, "/unlock") && r.Method == http.MethodPost:
+			groupsHandler.UnlockGroup(w, r)
+		case strings.HasSuffix(path, "/transfer") && r.Method == http.MethodPost:
+			groupsHandler.TransferOwnership(w, r)
+		case strings.Contains(path, "/members/") && strings.HasSuffix(path, "/promote") && r.Method == http.MethodPost:
+			groupsHandler.PromoteMember(w, r)
+		case strings.Contains(path, "/members/") && strings.HasSuffix(path, "/demote") && r.Method == http.MethodPost:
+			groupsHandler.DemoteMember(w, r)
+		case strings.HasSuffix(path, "/accept") && r.Method == http.MethodPost:
+			groupsHandler.AcceptRequest(w, r)
+		case strings.HasSuffix(path, "/decline") && r.Method == http.MethodPost:
+			groupsHandler.DeclineRequest(w, r)
+		case strings.HasSuffix(path, "/requests") && r.Method == http.MethodGet:
+			groupsHandler.GetJoinRequests(w, r)
+		case strings.HasSuffix(path, "/itinerary") && r.Method == http.MethodGet:
+			groupsHandler.GetItinerary(w, r)
+		case strings.HasSuffix(path, "/itinerary") && r.Method == http.MethodPut:
+			groupsHandler.SaveItinerary(w, r)
+		case strings.HasSuffix(path, "/itinerary") && r.Method == http.MethodDelete:
+			groupsHandler.DeleteItinerary(w, r)
+		case strings.HasSuffix(path, "/itinerary/legs") && r.Method == http.MethodPost:
+			groupsHandler.AddItineraryLeg(w, r)
+		case strings.Contains(path, "/itinerary/legs/") && r.Method == http.MethodPut:
+			groupsHandler.UpdateItineraryLeg(w, r)
+		case strings.Contains(path, "/itinerary/legs/") && r.Method == http.MethodDelete:
+			groupsHandler.DeleteItineraryLeg(w, r)
+		case strings.HasSuffix(path, "/expenses") && r.Method == http.MethodGet:
+			groupsHandler.ListExpenses(w, r)
+		case strings.HasSuffix(path, "/expenses") && r.Method == http.MethodPost:
+			groupsHandler.CreateExpense(w, r)
+		case strings.HasSuffix(path, "/expenses/balances") && r.Method == http.MethodGet:
+			groupsHandler.GetExpenseBalances(w, r)
+		case strings.HasSuffix(path, "/expenses/settlements") && r.Method == http.MethodGet:
+			groupsHandler.ListSettlements(w, r)
+		case strings.HasSuffix(path, "/expenses/settlements") && r.Method == http.MethodPost:
+			groupsHandler.CreateSettlement(w, r)
+		case strings.Contains(path, "/expenses/") && r.Method == http.MethodDelete:
+			groupsHandler.DeleteExpense(w, r)
+		case strings.HasSuffix(path, "/invites") && r.Method == http.MethodGet:
+			groupsHandler.ListInvites(w, r)
+		case strings.HasSuffix(path, "/invites") && r.Method == http.MethodPost:
+			groupsHandler.CreateInvite(w, r)
+		case strings.Contains(path, "/invites/") && r.Method == http.MethodDelete:
+			groupsHandler.RevokeInvite(w, r)
+		case strings.HasSuffix(path, "/ratings") && r.Method == http.MethodGet:
+			groupsHandler.ListMyRatings(w, r)
+		case strings.HasSuffix(path, "/ratings") && r.Method == http.MethodPost:
+			groupsHandler.RateMember(w, r)
+		case r.Method == http.MethodGet:
+			groupsHandler.GetGroup(w, r)
+		case r.Method == http.MethodPut:
+			groupsHandler.UpdateGroup(w, r)
+		case r.Method == http.MethodDelete:
+			groupsHandler.DeleteGroup(w, r)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})))
+
+	// Protected: peer matching
+	mux.Handle("/users/matches", authMW(http.HandlerFunc(groupsHandler.FindMatches)))
+
+	// Protected: view any user's profile GET /users/{id} — requires auth so
+	// profiles can't be viewed outside the app.
+	mux.Handle("/users/", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/connect") && r.Method == http.MethodPost:
+			profileHandler.ConnectUser(w, r)
+		case strings.HasSuffix(path, "/block") && r.Method == http.MethodPost:
+			profileHandler.BlockUser(w, r)
+		case strings.HasSuffix(path, "/unblock") && r.Method == http.MethodPost:
+			profileHandler.UnblockUser(w, r)
+		case r.Method == http.MethodGet:
+			profileHandler.GetPublicProfile(w, r)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})))
+
+	// --- Messages routes ---
+	msgHandler := messages.NewHandler(messagesRepo, hub)
+
+	// Protected: list threads
+	mux.Handle("/messages/threads", authMW(http.HandlerFunc(msgHandler.ListThreads)))
+
+	// Protected: message request inbox
+	mux.Handle("/messages/requests", authMW(http.HandlerFunc(msgHandler.ListRequests)))
+
+	// Protected: get-or-create direct thread
+	mux.Handle("/messages/thread/direct", authMW(http.HandlerFunc(msgHandler.GetOrCreateDirectThread)))
+
+	// Protected: full-text search across the user's threads
+	mux.Handle("/messages/search", authMW(http.HandlerFunc(msgHandler.SearchMessages)))
+
+	// Protected: forward a message into other threads
+	mux.Handle("/messages/forward", authMW(http.HandlerFunc(msgHandler.ForwardMessage)))
+
+	// Protected: scheduled messages
+	mux.Handle("/messages/schedule", authMW(http.HandlerFunc(msgHandler.ScheduleMessage)))
+	mux.Handle("/messages/scheduled", authMW(http.HandlerFunc(msgHandler.ListScheduledMessages)))
+	mux.Handle("/messages/scheduled/", authMW(http.HandlerFunc(msgHandler.CancelScheduledMessage)))
+
+	// Protected: send message (HTTP fallback)
+	mux.Handle("/messages/send", authMW(http.HandlerFunc(msgHandler.SendMessage)))
+
+	// Protected: clear chat, get messages, delete message, pins, settings, replies
+	mux.Handle("/messages/", authMW(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/pins") && r.Method == http.MethodGet:
+			msgHandler.ListPins(w, r)
+		case strings.HasSuffix(path, "/pins") && r.Method == http.MethodPost:
+			msgHandler.PinMessage(w, r)
+		case strings.Contains(path, "/pins/") && r.Method == http.MethodDelete:
+			msgHandler.UnpinMessage(w, r)
+		case strings.HasSuffix(path, "/settings") && r.Method == http.MethodPut:
+			msgHandler.UpdateThreadSettings(w, r)
+		case strings.HasSuffix(path, "/replies") && r.Method == http.MethodGet:
+			msgHandler.GetReplies(w, r)
+		case strings.HasSuffix(path, "/clear") && r.Method == http.MethodPost:
+			msgHandler.ClearThread(w, r)
+		case strings.HasSuffix(path, "/read") && r.Method == http.MethodPost:
+			msgHandler.HandleMarkRead(w, r)
+		case strings.HasSuffix(path, "/accept") && r.Method == http.MethodPost:
+			msgHandler.AcceptRequest(w, r)
+		case strings.HasSuffix(path, "/decline") && r.Method == http.MethodPost:
+			msgHandler.DeclineRequest(w, r)
+		case r.Method == http.MethodGet:
+			msgHandler.GetMessages(w, r)
+		case r.Method == http.MethodDelete:
+			msgHandler.DeleteMessage(w, r)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})))
+
+	// Protected: messages that @mention the user
+	mux.Handle("/me/mentions", authMW(http.HandlerFunc(msgHandler.ListMentions)))
+
+	// Protected: register device token for push notifications
+	mux.Handle("/me/device-token", authMW(http.HandlerFunc(msgHandler.RegisterDeviceToken)))
+
+	// WebSocket endpoint (auth via query param, not middleware)
+	mux.HandleFunc("/ws", messages.ServeWS(hub, authRepo))
+
+	return mux
+}
@@ -0,0 +1,8 @@
+-- Full-text search over message content.
+-- The 'simple' configuration is used (no stemming / stop words) so that
+-- PNRs, train numbers and mixed-language chat are matched verbatim.
+ALTER TABLE messages
+  ADD COLUMN IF NOT EXISTS search_vector tsvector
+  GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_messages_search_vector ON messages USING GIN (search_vector);
@@ -0,0 +1,10 @@
+-- Messages pinned to the top of a thread (meeting point, tickets, ...)
+CREATE TABLE IF NOT EXISTS pinned_messages (
+    thread_id   UUID REFERENCES message_threads(id) ON DELETE CASCADE,
+    message_id  UUID REFERENCES messages(id) ON DELETE CASCADE,
+    pinned_by   UUID REFERENCES users(id) ON DELETE SET NULL,
+    pinned_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (thread_id, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pinned_messages_thread_time ON pinned_messages(thread_id, pinned_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE thread_participants DROP COLUMN IF EXISTS notification_level;
+ALTER TABLE thread_participants DROP COLUMN IF EXISTS muted_until;
+ALTER TABLE thread_participants DROP COLUMN IF EXISTS is_muted;
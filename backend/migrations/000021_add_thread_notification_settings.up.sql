@@ -0,0 +1,6 @@
+-- Per-thread notification settings for each participant.
+-- A muted participant with a NULL muted_until stays muted until they unmute.
+ALTER TABLE thread_participants ADD COLUMN IF NOT EXISTS is_muted BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE thread_participants ADD COLUMN IF NOT EXISTS muted_until TIMESTAMPTZ;
+ALTER TABLE thread_participants ADD COLUMN IF NOT EXISTS notification_level VARCHAR(20) NOT NULL DEFAULT 'all'
+    CHECK (notification_level IN ('all', 'mentions'));
@@ -0,0 +1,10 @@
+-- @mentions parsed from group messages. Offsets are UTF-16 code units.
+CREATE TABLE IF NOT EXISTS message_mentions (
+    message_id   UUID REFERENCES messages(id) ON DELETE CASCADE,
+    user_id      UUID REFERENCES users(id) ON DELETE CASCADE,
+    start_offset INT NOT NULL,
+    length       INT NOT NULL,
+    PRIMARY KEY (message_id, start_offset)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_mentions_user ON message_mentions(user_id, message_id);
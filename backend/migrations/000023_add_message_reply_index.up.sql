@@ -0,0 +1,2 @@
+-- Speeds up reply counts and reply-chain traversal
+CREATE INDEX IF NOT EXISTS idx_messages_reply_to ON messages(reply_to_id) WHERE reply_to_id IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE message_threads DROP COLUMN IF EXISTS no_forward;
+
+ALTER TABLE messages
+DROP COLUMN IF EXISTS original_sender_id,
+DROP COLUMN IF EXISTS original_message_id;
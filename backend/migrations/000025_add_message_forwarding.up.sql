@@ -0,0 +1,7 @@
+-- Provenance for forwarded messages
+ALTER TABLE messages
+ADD COLUMN IF NOT EXISTS original_message_id UUID REFERENCES messages(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS original_sender_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Threads whose messages may not be forwarded elsewhere
+ALTER TABLE message_threads ADD COLUMN IF NOT EXISTS no_forward BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,8 @@
+-- Per-thread retention for disappearing messages; NULL means messages are kept
+ALTER TABLE message_threads
+ADD COLUMN IF NOT EXISTS message_ttl_seconds INT CHECK (message_ttl_seconds IN (86400, 604800));
+
+-- Stamped on insert from the thread's retention at that time
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_messages_expires_at ON messages(expires_at) WHERE expires_at IS NOT NULL;
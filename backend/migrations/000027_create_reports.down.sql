@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_until;
+
+DROP TABLE IF EXISTS reports;
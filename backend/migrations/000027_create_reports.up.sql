@@ -0,0 +1,27 @@
+-- User reports of messages, profiles, groups and events, reviewed by admins
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reporter_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('message', 'user', 'group', 'event')),
+    target_id UUID NOT NULL,
+    -- Author or owner of the reported content, if known
+    reported_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    -- Thread of a reported message
+    thread_id UUID REFERENCES message_threads(id) ON DELETE SET NULL,
+    category VARCHAR(30) NOT NULL CHECK (category IN ('harassment', 'spam', 'hate_speech', 'inappropriate', 'impersonation', 'safety', 'other')),
+    details TEXT,
+    -- Copy of the reported text so evidence survives deletion
+    content_snapshot TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+    resolution VARCHAR(20) CHECK (resolution IN ('warn', 'delete_content', 'suspend')),
+    resolution_note TEXT,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One open report per reporter per target
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_unique ON reports(reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_reports_status_created ON reports(status, created_at);
+
+-- Temporary suspension; permanent suspensions use status = 'blocked'
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMPTZ;
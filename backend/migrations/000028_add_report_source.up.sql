@@ -0,0 +1,3 @@
+-- Reports filed by the moderation filter rather than a user
+ALTER TABLE reports
+ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (source IN ('user', 'automated'));
@@ -0,0 +1,25 @@
+-- History of message requests, kept after a declined request thread is deleted.
+-- Used for the daily request quota and decline-ratio throttling.
+CREATE TABLE IF NOT EXISTS message_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    thread_id UUID REFERENCES message_threads(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'blocked')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_requests_sender ON message_requests(sender_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_message_requests_thread ON message_requests(thread_id) WHERE status = 'pending';
+
+-- Track requests that are already pending
+INSERT INTO message_requests (sender_id, recipient_id, thread_id, created_at)
+SELECT c.requester_id,
+       CASE WHEN c.user_id_1 = c.requester_id THEN c.user_id_2 ELSE c.user_id_1 END,
+       mt.id, mt.created_at
+FROM connections c
+JOIN thread_participants a ON a.user_id = c.user_id_1
+JOIN thread_participants b ON b.thread_id = a.thread_id AND b.user_id = c.user_id_2
+JOIN message_threads mt ON mt.id = a.thread_id AND mt.type = 'direct' AND mt.is_request = true
+WHERE c.status = 'pending' AND c.requester_id IS NOT NULL;
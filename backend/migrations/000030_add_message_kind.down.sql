@@ -0,0 +1,3 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS metadata;
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_kind_check;
+ALTER TABLE messages DROP COLUMN IF EXISTS kind;
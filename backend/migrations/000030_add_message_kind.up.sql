@@ -0,0 +1,9 @@
+-- 'text' for user messages; anything else is a system message rendered by the client
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS kind VARCHAR(30) NOT NULL DEFAULT 'text';
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_kind_check;
+ALTER TABLE messages ADD CONSTRAINT messages_kind_check CHECK (kind IN (
+    'text', 'member_joined', 'member_left', 'member_kicked', 'group_renamed', 'meeting_point_changed'
+));
+
+-- Structured details for system messages (e.g. the affected user, old and new values)
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS metadata JSONB;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_messages_thread_time ON messages(thread_id, created_at DESC);
+DROP INDEX IF EXISTS idx_messages_thread_keyset;
@@ -0,0 +1,3 @@
+-- Keyset pagination over (created_at, id) within a thread
+CREATE INDEX IF NOT EXISTS idx_messages_thread_keyset ON messages(thread_id, created_at, id);
+DROP INDEX IF EXISTS idx_messages_thread_time;
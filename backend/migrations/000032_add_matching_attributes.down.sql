@@ -0,0 +1,5 @@
+ALTER TABLE travel_groups DROP CONSTRAINT IF EXISTS travel_groups_gender_preference_check;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS gender_preference;
+ALTER TABLE profiles DROP COLUMN IF EXISTS home_city;
+ALTER TABLE profiles DROP CONSTRAINT IF EXISTS profiles_gender_check;
+ALTER TABLE profiles DROP COLUMN IF EXISTS gender;
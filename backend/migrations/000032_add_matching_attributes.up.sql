@@ -0,0 +1,10 @@
+-- Optional self-reported attributes used by group matching
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS gender VARCHAR(20);
+ALTER TABLE profiles DROP CONSTRAINT IF EXISTS profiles_gender_check;
+ALTER TABLE profiles ADD CONSTRAINT profiles_gender_check CHECK (gender IN ('female', 'male', 'non_binary'));
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS home_city VARCHAR(100);
+
+-- Who a group is open to; enforced when matching and joining
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS gender_preference VARCHAR(20) NOT NULL DEFAULT 'any';
+ALTER TABLE travel_groups DROP CONSTRAINT IF EXISTS travel_groups_gender_preference_check;
+ALTER TABLE travel_groups ADD CONSTRAINT travel_groups_gender_preference_check CHECK (gender_preference IN ('any', 'women_only', 'men_only'));
@@ -0,0 +1,8 @@
+ALTER TABLE travel_groups DROP CONSTRAINT IF EXISTS travel_groups_transport_mode_check;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS transport_mode;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS destination_lng;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS destination_lat;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS destination_city;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS origin_lng;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS origin_lat;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS origin_city;
@@ -0,0 +1,10 @@
+-- Where a group leaves from and goes to; coordinates are optional
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS origin_city VARCHAR(100);
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS origin_lat DOUBLE PRECISION;
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS origin_lng DOUBLE PRECISION;
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS destination_city VARCHAR(100);
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS destination_lat DOUBLE PRECISION;
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS destination_lng DOUBLE PRECISION;
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS transport_mode VARCHAR(20);
+ALTER TABLE travel_groups DROP CONSTRAINT IF EXISTS travel_groups_transport_mode_check;
+ALTER TABLE travel_groups ADD CONSTRAINT travel_groups_transport_mode_check CHECK (transport_mode IN ('train', 'bus', 'flight', 'carpool'));
@@ -0,0 +1,8 @@
+DELETE FROM messages WHERE kind = 'itinerary_updated';
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_kind_check;
+ALTER TABLE messages ADD CONSTRAINT messages_kind_check CHECK (kind IN (
+    'text', 'member_joined', 'member_left', 'member_kicked', 'group_renamed', 'meeting_point_changed'
+));
+
+DROP TABLE IF EXISTS group_itinerary_legs;
+DROP TABLE IF EXISTS group_itineraries;
@@ -0,0 +1,31 @@
+-- One shared itinerary per travel group
+CREATE TABLE IF NOT EXISTS group_itineraries (
+    group_id UUID PRIMARY KEY REFERENCES travel_groups(id) ON DELETE CASCADE,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Leg ids are assigned by the server so they survive the itinerary being re-saved
+CREATE TABLE IF NOT EXISTS group_itinerary_legs (
+    id UUID PRIMARY KEY,
+    group_id UUID NOT NULL REFERENCES group_itineraries(group_id) ON DELETE CASCADE,
+    mode VARCHAR(20) CHECK (mode IN ('train', 'bus', 'flight', 'carpool')),
+    carrier VARCHAR(50),
+    number VARCHAR(20),
+    departure_station VARCHAR(100) NOT NULL,
+    departure_time TIMESTAMPTZ NOT NULL,
+    arrival_station VARCHAR(100) NOT NULL,
+    arrival_time TIMESTAMPTZ NOT NULL,
+    -- PNR or booking reference; only returned to group members
+    booking_reference VARCHAR(30),
+    CHECK (arrival_time > departure_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_itinerary_legs_group ON group_itinerary_legs(group_id, departure_time);
+
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_kind_check;
+ALTER TABLE messages ADD CONSTRAINT messages_kind_check CHECK (kind IN (
+    'text', 'member_joined', 'member_left', 'member_kicked', 'group_renamed', 'meeting_point_changed',
+    'itinerary_updated'
+));
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS group_settlements;
+DROP TABLE IF EXISTS group_expense_shares;
+DROP TABLE IF EXISTS group_expenses;
@@ -0,0 +1,38 @@
+-- Shared costs within a travel group. Amounts are integer minor units (paise, cents)
+CREATE TABLE IF NOT EXISTS group_expenses (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id    UUID NOT NULL REFERENCES travel_groups(id) ON DELETE CASCADE,
+    description VARCHAR(100) NOT NULL,
+    amount      BIGINT NOT NULL CHECK (amount > 0),
+    currency    CHAR(3) NOT NULL,
+    paid_by     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    split_type  VARCHAR(20) NOT NULL CHECK (split_type IN ('equal', 'exact', 'percentage')),
+    created_by  UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_expenses_group ON group_expenses(group_id, created_at);
+
+-- Each participant's share of an expense; shares always add up to the expense amount
+CREATE TABLE IF NOT EXISTS group_expense_shares (
+    expense_id UUID NOT NULL REFERENCES group_expenses(id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount     BIGINT NOT NULL CHECK (amount >= 0),
+    percent    NUMERIC(5, 2),
+    PRIMARY KEY (expense_id, user_id)
+);
+
+-- Payments between members that settle their balances
+CREATE TABLE IF NOT EXISTS group_settlements (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id   UUID NOT NULL REFERENCES travel_groups(id) ON DELETE CASCADE,
+    from_user  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount     BIGINT NOT NULL CHECK (amount > 0),
+    currency   CHAR(3) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    paid_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (from_user <> to_user)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_settlements_group ON group_settlements(group_id, paid_at);
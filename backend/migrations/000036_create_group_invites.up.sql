@@ -0,0 +1,23 @@
+-- Invites to a travel group: shareable links (any user with the code) or
+-- direct invites addressed to one user. Accepting skips requires_approval.
+CREATE TABLE IF NOT EXISTS group_invites (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id   UUID NOT NULL REFERENCES travel_groups(id) ON DELETE CASCADE,
+    code       VARCHAR(32) NOT NULL UNIQUE,
+    kind       VARCHAR(10) NOT NULL CHECK (kind IN ('link', 'direct')),
+    invitee_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    max_uses   INT CHECK (max_uses > 0),
+    uses       INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    status     VARCHAR(10) NOT NULL DEFAULT 'active'
+               CHECK (status IN ('active', 'accepted', 'declined', 'revoked')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((kind = 'direct') = (invitee_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_invites_group ON group_invites(group_id) WHERE status = 'active';
+
+-- One open direct invite per user and group; inviting again refreshes it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_group_invites_invitee
+    ON group_invites(group_id, invitee_id) WHERE status = 'active';
@@ -0,0 +1,9 @@
+DELETE FROM messages WHERE kind = 'role_changed';
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_kind_check;
+ALTER TABLE messages ADD CONSTRAINT messages_kind_check CHECK (kind IN (
+    'text', 'member_joined', 'member_left', 'member_kicked', 'group_renamed', 'meeting_point_changed',
+    'itinerary_updated'
+));
+
+DROP INDEX IF EXISTS idx_group_members_owner;
+ALTER TABLE group_members DROP COLUMN IF EXISTS role;
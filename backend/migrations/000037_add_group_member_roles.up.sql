@@ -0,0 +1,16 @@
+-- Members can be the group's owner, a co-admin or a plain member
+ALTER TABLE group_members ADD COLUMN IF NOT EXISTS role VARCHAR(10) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('owner', 'admin', 'member'));
+
+-- Existing creators own their groups
+UPDATE group_members gm SET role = 'owner'
+FROM travel_groups tg
+WHERE gm.group_id = tg.id AND gm.user_id = tg.created_by;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_group_members_owner ON group_members(group_id) WHERE role = 'owner';
+
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_kind_check;
+ALTER TABLE messages ADD CONSTRAINT messages_kind_check CHECK (kind IN (
+    'text', 'member_joined', 'member_left', 'member_kicked', 'group_renamed', 'meeting_point_changed',
+    'itinerary_updated', 'role_changed'
+));
@@ -0,0 +1,15 @@
+-- FIFO waitlist for full travel groups. When a spot opens, the head of the
+-- queue is added directly, or offered the spot for a limited time if the
+-- group requires approval. A live offer holds the spot.
+CREATE TABLE IF NOT EXISTS group_waitlist (
+    group_id         UUID NOT NULL REFERENCES travel_groups(id) ON DELETE CASCADE,
+    user_id          UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status           VARCHAR(10) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'offered')),
+    offer_expires_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, user_id),
+    CHECK ((status = 'offered') = (offer_expires_at IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_waitlist_queue ON group_waitlist(group_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_group_waitlist_offers ON group_waitlist(offer_expires_at) WHERE status = 'offered';
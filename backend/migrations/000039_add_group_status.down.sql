@@ -0,0 +1,4 @@
+ALTER TABLE message_threads DROP COLUMN IF EXISTS read_only;
+DROP INDEX IF EXISTS idx_travel_groups_active;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS status;
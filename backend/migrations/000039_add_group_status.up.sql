@@ -0,0 +1,11 @@
+-- Group lifecycle: open -> (locked) -> traveling -> completed -> archived.
+-- Only open groups accept new members. The archiver moves groups along
+-- once they depart and after their event ends.
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'open'
+    CHECK (status IN ('open', 'locked', 'traveling', 'completed', 'archived'));
+ALTER TABLE travel_groups ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_travel_groups_active ON travel_groups(status) WHERE status <> 'archived';
+
+-- Archived group chats keep their history but accept no new messages
+ALTER TABLE message_threads ADD COLUMN IF NOT EXISTS read_only BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS user_trust_scores;
+DROP TABLE IF EXISTS member_ratings;
@@ -0,0 +1,25 @@
+-- Post-trip peer ratings. Members of a completed group rate each other once
+-- per trip; ratings outlive the group so that deleting it does not wipe
+-- anyone's trust score.
+CREATE TABLE IF NOT EXISTS member_ratings (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id      UUID REFERENCES travel_groups(id) ON DELETE SET NULL,
+    rater_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ratee_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    punctuality   SMALLINT NOT NULL CHECK (punctuality BETWEEN 1 AND 5),
+    communication SMALLINT NOT NULL CHECK (communication BETWEEN 1 AND 5),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (group_id, rater_id, ratee_id),
+    CHECK (rater_id <> ratee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_member_ratings_ratee ON member_ratings(ratee_id);
+
+-- Aggregated ratings per user, read by profiles and group matching.
+CREATE OR REPLACE VIEW user_trust_scores AS
+SELECT ratee_id AS user_id,
+       COUNT(*) AS ratings,
+       AVG(punctuality)::DOUBLE PRECISION AS punctuality,
+       AVG(communication)::DOUBLE PRECISION AS communication
+FROM member_ratings
+GROUP BY ratee_id;
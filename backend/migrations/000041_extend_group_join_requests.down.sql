@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_group_join_requests_user;
+DROP INDEX IF EXISTS idx_group_join_requests_expiry;
+
+ALTER TABLE group_join_requests DROP CONSTRAINT IF EXISTS group_join_requests_status_check;
+ALTER TABLE group_join_requests ALTER COLUMN status DROP NOT NULL;
+
+ALTER TABLE group_join_requests
+    DROP COLUMN IF EXISTS resolved_at,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS message;
@@ -0,0 +1,19 @@
+-- Join requests carry an optional note from the requester, can be cancelled
+-- by them, and expire if nobody acts on them in time.
+ALTER TABLE group_join_requests
+    ADD COLUMN IF NOT EXISTS message     TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS expires_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMPTZ;
+
+UPDATE group_join_requests SET expires_at = requested_at + INTERVAL '7 days' WHERE expires_at IS NULL;
+UPDATE group_join_requests SET status = 'pending' WHERE status IS NULL;
+
+ALTER TABLE group_join_requests
+    ALTER COLUMN expires_at SET NOT NULL,
+    ALTER COLUMN status SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS group_join_requests_status_check,
+    ADD CONSTRAINT group_join_requests_status_check
+        CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled', 'expired'));
+
+CREATE INDEX IF NOT EXISTS idx_group_join_requests_expiry ON group_join_requests(expires_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_group_join_requests_user ON group_join_requests(user_id, requested_at DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_travel_groups_origin_city;
+DROP INDEX IF EXISTS idx_travel_groups_event_created;
+DROP INDEX IF EXISTS idx_travel_groups_listed_departure;
+DROP INDEX IF EXISTS idx_travel_groups_listed_newest;
+DROP INDEX IF EXISTS idx_travel_groups_search_vector;
+ALTER TABLE travel_groups DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,18 @@
+-- Full-text search over group names and descriptions, using the 'simple'
+-- configuration like message search.
+ALTER TABLE travel_groups
+  ADD COLUMN IF NOT EXISTS search_vector tsvector
+  GENERATED ALWAYS AS (to_tsvector('simple', name || ' ' || COALESCE(description, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_travel_groups_search_vector ON travel_groups USING GIN (search_vector);
+
+-- Keyset pagination for GET /groups over groups that are still listed
+CREATE INDEX IF NOT EXISTS idx_travel_groups_listed_newest ON travel_groups(created_at DESC, id DESC)
+  WHERE status NOT IN ('completed', 'archived');
+CREATE INDEX IF NOT EXISTS idx_travel_groups_listed_departure
+  ON travel_groups((COALESCE(departure_date, 'infinity'::timestamp)), id)
+  WHERE status NOT IN ('completed', 'archived');
+
+-- Event and origin filters; the college filter goes through group_members' primary key
+CREATE INDEX IF NOT EXISTS idx_travel_groups_event_created ON travel_groups(event_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_travel_groups_origin_city ON travel_groups(LOWER(origin_city));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scheduled_messages_claimed;
+ALTER TABLE scheduled_messages DROP COLUMN IF EXISTS claimed_at;
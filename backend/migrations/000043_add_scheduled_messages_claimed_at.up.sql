@@ -0,0 +1,7 @@
+-- When a dispatcher claimed a scheduled message, so that claims abandoned by a
+-- crashed dispatcher can be retried
+ALTER TABLE scheduled_messages ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
+
+UPDATE scheduled_messages SET claimed_at = updated_at WHERE status = 'sending' AND claimed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_messages_claimed ON scheduled_messages(claimed_at) WHERE status = 'sending';
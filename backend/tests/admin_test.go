@@ -18,7 +18,7 @@ func newAdminRouter(t *testing.T) http.Handler {
 	return server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 }
 
@@ -48,7 +48,7 @@ func TestAdminListPending_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, mockAdminRepo,
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/admin/users/pending", nil)
@@ -82,7 +82,7 @@ func TestAdminVerifyUser_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, mockAdminRepo,
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("POST", "/admin/users/u1/verify", nil)
@@ -114,7 +114,7 @@ func TestAdminBlockUser_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, mockAdminRepo,
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("POST", "/admin/users/u1/block", nil)
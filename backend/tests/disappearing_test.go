@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+func putMessageTTL(t *testing.T, mockRepo *MockMessagesRepository, ttl string) *httptest.ResponseRecorder {
+	t.Helper()
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]interface{}{"message_ttl": ttl})
+	req, _ := http.NewRequest("PUT", "/messages/thread-1/settings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSetMessageTTL_InvalidValue(t *testing.T) {
+	rr := putMessageTTL(t, &MockMessagesRepository{}, "1h")
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("message_ttl=1h: got %d, want 400", rr.Code)
+	}
+}
+
+func TestSetMessageTTL_GroupNonAdmin(t *testing.T) {
+	mockRepo := &MockMessagesRepository{
+		CanManageThreadFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return false, nil
+		},
+		SetThreadMessageTTLFunc: func(ctx context.Context, threadID string, ttlSeconds int) error {
+			t.Error("non-admin must not change the timer")
+			return nil
+		},
+	}
+	rr := putMessageTTL(t, mockRepo, "24h")
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("message_ttl by group member: got %d, want 403", rr.Code)
+	}
+}
+
+func TestSetMessageTTL_DirectThread(t *testing.T) {
+	saved := -1
+	mockRepo := &MockMessagesRepository{
+		GetThreadFunc: func(ctx context.Context, threadID string) (messages.Thread, error) {
+			return messages.Thread{ID: threadID, Type: "direct"}, nil
+		},
+		CanManageThreadFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return false, nil
+		},
+		SetThreadMessageTTLFunc: func(ctx context.Context, threadID string, ttlSeconds int) error {
+			saved = ttlSeconds
+			return nil
+		},
+	}
+	rr := putMessageTTL(t, mockRepo, "7d")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("message_ttl in direct thread: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if saved != 7*24*60*60 {
+		t.Errorf("expected a 7 day timer to be saved, got %d", saved)
+	}
+	var settings messages.ThreadSettings
+	json.NewDecoder(rr.Body).Decode(&settings)
+	if settings.MessageTTLSeconds != saved {
+		t.Errorf("response message_ttl_seconds = %d, want %d", settings.MessageTTLSeconds, saved)
+	}
+}
+
+func TestSetMessageTTL_Off(t *testing.T) {
+	saved := -1
+	mockRepo := &MockMessagesRepository{
+		GetThreadSettingsFunc: func(ctx context.Context, threadID, userID string) (messages.ThreadSettings, error) {
+			return messages.ThreadSettings{NotificationLevel: messages.NotifyAll, MessageTTLSeconds: 86400}, nil
+		},
+		SetThreadMessageTTLFunc: func(ctx context.Context, threadID string, ttlSeconds int) error {
+			saved = ttlSeconds
+			return nil
+		},
+	}
+	rr := putMessageTTL(t, mockRepo, "off")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("message_ttl=off: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if saved != 0 {
+		t.Errorf("expected the timer to be cleared, got %d", saved)
+	}
+}
+
+func TestSweepExpired_BroadcastsDeletions(t *testing.T) {
+	var cutoff time.Time
+	broadcasts := map[string]int{}
+	mockRepo := &MockMessagesRepository{
+		DeleteExpiredMessagesFunc: func(ctx context.Context, now time.Time, limit int) ([]messages.ExpiredMessage, error) {
+			cutoff = now
+			return []messages.ExpiredMessage{
+				{ID: "msg-1", ThreadID: "thread-1"},
+				{ID: "msg-2", ThreadID: "thread-2"},
+			}, nil
+		},
+		GetParticipantIDsFunc: func(ctx context.Context, threadID string) ([]string, error) {
+			broadcasts[threadID]++
+			return []string{"user-1", "user-2"}, nil
+		},
+	}
+	hub := messages.NewHub(mockRepo, nil, nil)
+	deleted := messages.NewSweeper(mockRepo, hub).SweepExpired(context.Background())
+
+	if deleted != 2 {
+		t.Errorf("expected 2 messages swept, got %d", deleted)
+	}
+	if time.Since(cutoff) > time.Minute {
+		t.Errorf("sweeper should delete messages expired as of now, got cutoff %v", cutoff)
+	}
+	if broadcasts["thread-1"] != 1 || broadcasts["thread-2"] != 1 {
+		t.Errorf("expected one deletion broadcast per thread, got %v", broadcasts)
+	}
+}
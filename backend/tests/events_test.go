@@ -30,7 +30,7 @@ func TestListEvents_ReturnsApproved(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		mockEventsRepo, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/events", nil)
@@ -63,7 +63,7 @@ func TestListEvents_EmptyList(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		mockEventsRepo, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/events", nil)
@@ -87,7 +87,7 @@ func TestCreateEvent_RequiresAuth(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{
@@ -120,7 +120,7 @@ func TestCreateEvent_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		mockEventsRepo, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{
@@ -155,7 +155,7 @@ func TestCreateEvent_ValidationFails(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepositoryFull{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	// Missing required fields
@@ -180,7 +180,7 @@ func TestSetUserEvent_RequiresAuth(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{"event_id": "evt-1"}
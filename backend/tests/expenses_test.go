@@ -0,0 +1,316 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+)
+
+func percent(p float64) *float64 { return &p }
+
+// expensesRepo is a groups repo whose members are listed, in join order.
+func expensesRepo(members ...string) *MockGroupsRepositoryFull {
+	repo := itineraryRepo(nil, members...)
+	repo.GetGroupMembersFunc = func(ctx context.Context, groupID string) ([]groups.GroupMemberProfile, error) {
+		var profiles []groups.GroupMemberProfile
+		for _, m := range members {
+			profiles = append(profiles, groups.GroupMemberProfile{UserID: m})
+		}
+		return profiles, nil
+	}
+	return repo
+}
+
+// capSettlements makes repo.CreateSettlement enforce the balance cap the way
+// the Postgres repository does, calling record for each accepted settlement.
+func capSettlements(repo *MockGroupsRepositoryFull, record func(settlement *groups.Settlement)) {
+	repo.CreateSettlementFunc = func(ctx context.Context, settlement *groups.Settlement) error {
+		expenses, _ := repo.GetExpenses(ctx, settlement.GroupID)
+		settlements, _ := repo.GetSettlements(ctx, settlement.GroupID)
+		if !settlement.WithinBalance(groups.ComputeBalances(expenses, settlements)) {
+			return groups.ErrSettlementExceedsBalance
+		}
+		record(settlement)
+		return nil
+	}
+}
+
+func shareAmounts(shares []groups.ExpenseShare) []int64 {
+	var amounts []int64
+	for _, s := range shares {
+		amounts = append(amounts, s.Amount)
+	}
+	return amounts
+}
+
+func TestSplitShares(t *testing.T) {
+	tests := []struct {
+		name      string
+		splitType string
+		splits    []groups.ExpenseShare
+		want      []int64
+	}{
+		{"equal with remainder", groups.SplitEqual, []groups.ExpenseShare{{UserID: "a"}, {UserID: "b"}, {UserID: "c"}}, []int64{334, 333, 333}},
+		{"exact", groups.SplitExact, []groups.ExpenseShare{{UserID: "a", Amount: 700}, {UserID: "b", Amount: 300}}, []int64{700, 300}},
+		{"percentage largest remainder", groups.SplitPercentage, []groups.ExpenseShare{
+			{UserID: "a", Percent: percent(33.33)}, {UserID: "b", Percent: percent(33.33)}, {UserID: "c", Percent: percent(33.34)},
+		}, []int64{333, 333, 334}},
+	}
+	for _, tt := range tests {
+		shares, err := groups.SplitShares(1000, tt.splitType, tt.splits)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := shareAmounts(shares)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: shares = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	bad := map[string][]groups.ExpenseShare{
+		groups.SplitExact:      {{UserID: "a", Amount: 700}, {UserID: "b", Amount: 200}},
+		groups.SplitPercentage: {{UserID: "a", Percent: percent(50)}, {UserID: "b", Percent: percent(40)}},
+		"shares":               {{UserID: "a"}},
+	}
+	for splitType, splits := range bad {
+		if _, err := groups.SplitShares(1000, splitType, splits); err == nil {
+			t.Errorf("SplitShares(%s, %+v) should fail", splitType, splits)
+		}
+	}
+}
+
+func TestComputeBalancesAndSettleUp(t *testing.T) {
+	expenses := []groups.Expense{
+		{Amount: 900, Currency: "INR", PaidBy: "a", Shares: []groups.ExpenseShare{{UserID: "a", Amount: 300}, {UserID: "b", Amount: 300}, {UserID: "c", Amount: 300}}},
+		{Amount: 300, Currency: "INR", PaidBy: "b", Shares: []groups.ExpenseShare{{UserID: "c", Amount: 300}}},
+		{Amount: 50, Currency: "USD", PaidBy: "c", Shares: []groups.ExpenseShare{{UserID: "a", Amount: 50}}},
+	}
+	// b already paid a back
+	settlements := []groups.Settlement{{FromUser: "b", ToUser: "a", Amount: 300, Currency: "INR"}}
+
+	balances := groups.ComputeBalances(expenses, settlements)
+	net := map[string]int64{}
+	for _, b := range balances {
+		net[b.Currency+":"+b.UserID] = b.Net
+	}
+	want := map[string]int64{"INR:a": 300, "INR:b": 300, "INR:c": -600, "USD:a": -50, "USD:c": 50}
+	for k, v := range want {
+		if net[k] != v {
+			t.Errorf("net %s = %d, want %d (balances %+v)", k, net[k], v, balances)
+		}
+	}
+
+	transfers := groups.SettleUp(balances)
+	wantTransfers := []groups.Transfer{
+		{FromUser: "c", ToUser: "a", Amount: 300, Currency: "INR"},
+		{FromUser: "c", ToUser: "b", Amount: 300, Currency: "INR"},
+		{FromUser: "a", ToUser: "c", Amount: 50, Currency: "USD"},
+	}
+	if len(transfers) != len(wantTransfers) {
+		t.Fatalf("settle up = %+v, want %+v", transfers, wantTransfers)
+	}
+	for i := range wantTransfers {
+		if transfers[i] != wantTransfers[i] {
+			t.Errorf("transfer %d = %+v, want %+v", i, transfers[i], wantTransfers[i])
+		}
+	}
+}
+
+func TestCreateExpense_EqualSplitDefaultsToMembers(t *testing.T) {
+	var saved *groups.Expense
+	repo := expensesRepo("a", "b", "c")
+	repo.CreateExpenseFunc = func(ctx context.Context, expense *groups.Expense) error {
+		saved = expense
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	body := map[string]interface{}{"description": "Cab to station", "amount": 100000, "currency": "inr"}
+	rr := doItinerary(t, router, "b", "POST", "/groups/g1/expenses", body)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST expense: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if saved == nil || saved.PaidBy != "b" || saved.CreatedBy != "b" || saved.Currency != "INR" || saved.SplitType != groups.SplitEqual {
+		t.Fatalf("saved expense = %+v", saved)
+	}
+	if got := shareAmounts(saved.Shares); len(got) != 3 || got[0] != 33334 || got[1] != 33333 || got[2] != 33333 {
+		t.Errorf("shares = %v, want [33334 33333 33333]", got)
+	}
+}
+
+func TestCreateExpense_Invalid(t *testing.T) {
+	router, _ := newSystemMessageRouter(t, expensesRepo("a", "b"))
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"invalid currency", map[string]interface{}{"description": "Cab", "amount": 500, "currency": "RUPEES"}},
+		{"zero amount", map[string]interface{}{"description": "Cab", "amount": 0, "currency": "INR"}},
+		{"non-member participant", map[string]interface{}{"description": "Cab", "amount": 500, "currency": "INR", "splits": []map[string]interface{}{{"user_id": "a"}, {"user_id": "stranger"}}}},
+		{"non-member payer", map[string]interface{}{"description": "Cab", "amount": 500, "currency": "INR", "paid_by": "stranger"}},
+		{"exact does not add up", map[string]interface{}{"description": "Cab", "amount": 500, "currency": "INR", "split_type": "exact", "splits": []map[string]interface{}{{"user_id": "a", "amount": 100}}}},
+	}
+	for _, tt := range tests {
+		rr := doItinerary(t, router, "a", "POST", "/groups/g1/expenses", tt.body)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", tt.name, rr.Code)
+		}
+	}
+
+	rr := doItinerary(t, router, "outsider", "POST", "/groups/g1/expenses", map[string]interface{}{"description": "Cab", "amount": 500, "currency": "INR"})
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("non-member adding expense: got %d, want 403", rr.Code)
+	}
+}
+
+func TestGetExpenseBalances(t *testing.T) {
+	repo := expensesRepo("a", "b")
+	repo.GetExpensesFunc = func(ctx context.Context, groupID string) ([]groups.Expense, error) {
+		return []groups.Expense{{Amount: 800, Currency: "INR", PaidBy: "a", Shares: []groups.ExpenseShare{{UserID: "a", Amount: 400}, {UserID: "b", Amount: 400}}}}, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	rr := doItinerary(t, router, "b", "GET", "/groups/g1/expenses/balances", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET balances: got %d, want 200", rr.Code)
+	}
+	var summary groups.ExpenseSummary
+	json.NewDecoder(rr.Body).Decode(&summary)
+	if len(summary.Balances) != 2 || len(summary.SettleUp) != 1 || summary.SettleUp[0] != (groups.Transfer{FromUser: "b", ToUser: "a", Amount: 400, Currency: "INR"}) {
+		t.Errorf("summary = %+v, want b paying a 400 INR", summary)
+	}
+
+	rr = doItinerary(t, router, "outsider", "GET", "/groups/g1/expenses/balances", nil)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("non-member GET balances: got %d, want 403", rr.Code)
+	}
+}
+
+func TestCreateSettlement(t *testing.T) {
+	var recorded *groups.Settlement
+	repo := expensesRepo("a", "b", "c")
+	// b owes a 400 INR
+	repo.GetExpensesFunc = func(ctx context.Context, groupID string) ([]groups.Expense, error) {
+		return []groups.Expense{{Amount: 800, Currency: "INR", PaidBy: "a", Shares: []groups.ExpenseShare{{UserID: "a", Amount: 400}, {UserID: "b", Amount: 400}}}}, nil
+	}
+	capSettlements(repo, func(settlement *groups.Settlement) { recorded = settlement })
+	router, _ := newSystemMessageRouter(t, repo)
+
+	payment := map[string]interface{}{"from_user": "b", "to_user": "a", "amount": 400, "currency": "INR"}
+	if rr := doItinerary(t, router, "c", "POST", "/groups/g1/expenses/settlements", payment); rr.Code != http.StatusForbidden {
+		t.Errorf("marking someone else's payment: got %d, want 403", rr.Code)
+	}
+	if rr := doItinerary(t, router, "b", "POST", "/groups/g1/expenses/settlements", payment); rr.Code != http.StatusForbidden {
+		t.Errorf("payer marking their own payment: got %d, want 403", rr.Code)
+	}
+	if recorded != nil {
+		t.Fatal("settlement should only be recorded by the recipient")
+	}
+
+	rr := doItinerary(t, router, "a", "POST", "/groups/g1/expenses/settlements", payment)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("recipient marking payment: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if recorded == nil || recorded.GroupID != "g1" || recorded.CreatedBy != "a" || recorded.Amount != 400 {
+		t.Errorf("recorded settlement = %+v", recorded)
+	}
+
+	// A former member with nothing left to settle cannot be paid
+	rr = doItinerary(t, router, "a", "POST", "/groups/g1/expenses/settlements", map[string]interface{}{"from_user": "gone", "to_user": "a", "amount": 400, "currency": "INR"})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("settling with a stranger: got %d, want 400", rr.Code)
+	}
+}
+
+func TestCreateSettlement_CappedAtOutstandingBalance(t *testing.T) {
+	recorded := 0
+	repo := expensesRepo("a", "b", "c")
+	// b owes a 400 INR and has already paid 150 of it; c owes a 400 INR
+	repo.GetExpensesFunc = func(ctx context.Context, groupID string) ([]groups.Expense, error) {
+		return []groups.Expense{{Amount: 1200, Currency: "INR", PaidBy: "a", Shares: []groups.ExpenseShare{
+			{UserID: "a", Amount: 400}, {UserID: "b", Amount: 400}, {UserID: "c", Amount: 400},
+		}}}, nil
+	}
+	repo.GetSettlementsFunc = func(ctx context.Context, groupID string) ([]groups.Settlement, error) {
+		return []groups.Settlement{{FromUser: "b", ToUser: "a", Amount: 150, Currency: "INR"}}, nil
+	}
+	capSettlements(repo, func(settlement *groups.Settlement) { recorded++ })
+	router, _ := newSystemMessageRouter(t, repo)
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+		want int
+	}{
+		{"more than b still owes", map[string]interface{}{"from_user": "b", "to_user": "a", "amount": 251, "currency": "INR"}, http.StatusBadRequest},
+		{"other currency", map[string]interface{}{"from_user": "b", "to_user": "a", "amount": 100, "currency": "USD"}, http.StatusBadRequest},
+		{"b pays off the rest", map[string]interface{}{"from_user": "b", "to_user": "a", "amount": 250, "currency": "INR"}, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		if rr := doItinerary(t, router, "a", "POST", "/groups/g1/expenses/settlements", tt.body); rr.Code != tt.want {
+			t.Errorf("%s: got %d, want %d. Body: %s", tt.name, rr.Code, tt.want, rr.Body.String())
+		}
+	}
+
+	// c owes money, but not to b
+	if rr := doItinerary(t, router, "b", "POST", "/groups/g1/expenses/settlements", map[string]interface{}{"from_user": "c", "to_user": "b", "amount": 100, "currency": "INR"}); rr.Code != http.StatusBadRequest {
+		t.Errorf("recipient who is owed nothing: got %d, want 400", rr.Code)
+	}
+	if recorded != 1 {
+		t.Errorf("recorded %d settlements, want 1", recorded)
+	}
+}
+
+func TestSettlement_WithinBalance(t *testing.T) {
+	// b owes a 400 INR; a owes c 50 USD
+	balances := []groups.Balance{
+		{UserID: "a", Currency: "INR", Net: 400},
+		{UserID: "b", Currency: "INR", Net: -400},
+		{UserID: "a", Currency: "USD", Net: -50},
+		{UserID: "c", Currency: "USD", Net: 50},
+	}
+	tests := []struct {
+		name string
+		s    groups.Settlement
+		want bool
+	}{
+		{"full amount", groups.Settlement{FromUser: "b", ToUser: "a", Amount: 400, Currency: "INR"}, true},
+		{"part of it", groups.Settlement{FromUser: "b", ToUser: "a", Amount: 1, Currency: "INR"}, true},
+		{"too much", groups.Settlement{FromUser: "b", ToUser: "a", Amount: 401, Currency: "INR"}, false},
+		{"wrong direction", groups.Settlement{FromUser: "a", ToUser: "b", Amount: 100, Currency: "INR"}, false},
+		{"other currency", groups.Settlement{FromUser: "b", ToUser: "a", Amount: 10, Currency: "USD"}, false},
+		{"recipient owed nothing", groups.Settlement{FromUser: "b", ToUser: "c", Amount: 10, Currency: "INR"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.s.WithinBalance(balances); got != tt.want {
+			t.Errorf("%s: WithinBalance = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeleteExpense_OnlyPayerOrAuthor(t *testing.T) {
+	deleted := false
+	repo := expensesRepo("a", "b", "c")
+	repo.GetExpenseFunc = func(ctx context.Context, groupID, expenseID string) (*groups.Expense, error) {
+		return &groups.Expense{ID: expenseID, GroupID: groupID, PaidBy: "a", CreatedBy: "b"}, nil
+	}
+	repo.DeleteExpenseFunc = func(ctx context.Context, groupID, expenseID string) error {
+		deleted = true
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "c", "DELETE", "/groups/g1/expenses/e1", nil); rr.Code != http.StatusForbidden || deleted {
+		t.Errorf("third member deleting expense: got %d (deleted=%v), want 403", rr.Code, deleted)
+	}
+	if rr := doItinerary(t, router, "b", "DELETE", "/groups/g1/expenses/e1", nil); rr.Code != http.StatusOK || !deleted {
+		t.Errorf("author deleting expense: got %d (deleted=%v), want 200", rr.Code, deleted)
+	}
+}
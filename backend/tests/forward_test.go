@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+func postForward(t *testing.T, mockRepo *MockMessagesRepository, payload map[string]interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", "/messages/forward", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestForwardMessage_SourceNotVisible(t *testing.T) {
+	mockRepo := &MockMessagesRepository{
+		GetMessageFunc: func(ctx context.Context, messageID, userID string) (messages.Message, error) {
+			return messages.Message{}, sql.ErrNoRows
+		},
+	}
+	rr := postForward(t, mockRepo, map[string]interface{}{"message_id": "msg-1", "thread_ids": []string{"thread-2"}})
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("forwarding an unreadable message: got %d, want 404", rr.Code)
+	}
+}
+
+func TestForwardMessage_NoForwardThread(t *testing.T) {
+	mockRepo := &MockMessagesRepository{
+		GetThreadFunc: func(ctx context.Context, threadID string) (messages.Thread, error) {
+			return messages.Thread{ID: threadID, Type: "group", NoForward: true}, nil
+		},
+		CreateForwardedMessageFunc: func(ctx context.Context, threadID, senderID string, src messages.Message) (messages.Message, error) {
+			t.Error("message must not be forwarded out of a no-forward thread")
+			return messages.Message{}, nil
+		},
+	}
+	rr := postForward(t, mockRepo, map[string]interface{}{"message_id": "msg-1", "thread_ids": []string{"thread-2"}})
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("forwarding out of a no-forward thread: got %d, want 403", rr.Code)
+	}
+}
+
+func TestForwardMessage_TooManyTargets(t *testing.T) {
+	rr := postForward(t, &MockMessagesRepository{}, map[string]interface{}{
+		"message_id": "msg-1",
+		"thread_ids": []string{"t1", "t2", "t3", "t4", "t5", "t6"},
+	})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("forwarding to 6 threads: got %d, want 400", rr.Code)
+	}
+}
+
+func TestForwardMessage_PartialFailure(t *testing.T) {
+	var copied []string
+	mockRepo := &MockMessagesRepository{
+		GetMessageFunc: func(ctx context.Context, messageID, userID string) (messages.Message, error) {
+			return messages.Message{ID: messageID, ThreadID: "thread-src", SenderID: "user-9", Content: "PNR 4521879630"}, nil
+		},
+		IsParticipantFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return threadID != "thread-stranger", nil
+		},
+		CreateForwardedMessageFunc: func(ctx context.Context, threadID, senderID string, src messages.Message) (messages.Message, error) {
+			if src.ID != "msg-1" || src.SenderID != "user-9" {
+				t.Errorf("source message not passed through: %+v", src)
+			}
+			copied = append(copied, threadID)
+			return messages.Message{ID: "fwd-" + threadID, ThreadID: threadID, Content: src.Content, IsForwarded: true}, nil
+		},
+	}
+	rr := postForward(t, mockRepo, map[string]interface{}{
+		"message_id": "msg-1",
+		"thread_ids": []string{"thread-a", "thread-stranger", "thread-a"},
+	})
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /messages/forward: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var resp messages.ForwardResponse
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if len(copied) != 1 || len(resp.Forwarded) != 1 || resp.Forwarded[0].ThreadID != "thread-a" {
+		t.Errorf("expected exactly one forward into thread-a, got %+v (copied %v)", resp.Forwarded, copied)
+	}
+	if len(resp.Failed) != 1 || resp.Failed[0].ThreadID != "thread-stranger" {
+		t.Errorf("expected thread-stranger to fail, got %+v", resp.Failed)
+	}
+}
+
+func TestUpdateThreadSettings_NoForwardRequiresAdmin(t *testing.T) {
+	token, _ := auth.GenerateToken("user-2", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		CanManageThreadFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return false, nil
+		},
+		SetThreadNoForwardFunc: func(ctx context.Context, threadID string, noForward bool) error {
+			t.Error("non-admin must not change no_forward")
+			return nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]interface{}{"no_forward": true})
+	req, _ := http.NewRequest("PUT", "/messages/thread-1/settings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("no_forward as non-admin: got %d, want 403", rr.Code)
+	}
+}
+
+func TestSendMessage_IgnoresClientForwardFlag(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	forwarded := true
+	mockRepo := &MockMessagesRepository{
+		CreateMessageFunc: func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error) {
+			forwarded = isForwarded
+			return messages.Message{ID: "msg-1", ThreadID: threadID, SenderID: senderID, Content: content, IsForwarded: isForwarded}, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]interface{}{"thread_id": "thread-1", "content": "Totally forwarded", "is_forwarded": true})
+	req, _ := http.NewRequest("POST", "/messages/send", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST /messages/send: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if forwarded {
+		t.Error("a client-supplied is_forwarded must not mark the message as forwarded")
+	}
+}
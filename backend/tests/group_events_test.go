@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/server"
+)
+
+// liveGroupRouter returns a router whose hub is running, and a function that
+// connects a user to it over WebSocket.
+func liveGroupRouter(t *testing.T, groupsRepo groups.Repository) (http.Handler, func(userID string) *websocket.Conn) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "testsecret")
+	msgRepo := &MockMessagesRepository{}
+	hub := messages.NewHub(msgRepo, nil, nil)
+	go hub.Run()
+
+	router := server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
+		&MockEventsRepository{}, groupsRepo,
+		msgRepo, hub, &MockFileStorage{}, "./uploads", nil, nil, nil,
+	)
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	connect := func(userID string) *websocket.Conn {
+		t.Helper()
+		token, _ := auth.GenerateToken(userID, "student@nitw.ac.in")
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws?token="+token, nil)
+		if err != nil {
+			t.Fatalf("dial as %s: %v", userID, err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		for deadline := time.Now().Add(2 * time.Second); !hub.IsOnline(userID); time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s never came online", userID)
+			}
+		}
+		return conn
+	}
+	return router, connect
+}
+
+// nextGroupEvent reads the next group event from conn, skipping presence
+// updates, and decodes its payload into v.
+func nextGroupEvent(t *testing.T, conn *websocket.Conn, v interface{}) string {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var event struct {
+			Type    string          `json:"type"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("reading event: %v", err)
+		}
+		if event.Type == "presence_update" || event.Type == "notification" {
+			continue
+		}
+		if err := json.Unmarshal(event.Payload, v); err != nil {
+			t.Fatalf("decoding %s payload: %v", event.Type, err)
+		}
+		return event.Type
+	}
+}
+
+// liveRepo is a group whose member list follows roles as it changes.
+func liveRepo(roles map[string]string) *MockGroupsRepositoryFull {
+	repo := statusRepo(groups.StatusOpen, roles)
+	repo.GetGroupMembersFunc = func(ctx context.Context, groupID string) ([]groups.GroupMemberProfile, error) {
+		var members []groups.GroupMemberProfile
+		for id, role := range roles {
+			members = append(members, groups.GroupMemberProfile{UserID: id, Role: role})
+		}
+		return members, nil
+	}
+	repo.RemoveMemberFunc = func(ctx context.Context, groupID, userID string) error {
+		delete(roles, userID)
+		return nil
+	}
+	return repo
+}
+
+func TestJoinRequest_LiveEvents(t *testing.T) {
+	roles := map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember}
+	repo := liveRepo(roles)
+	repo.JoinGroupCheckedFunc = func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
+		return true, nil
+	}
+	repo.AcceptJoinRequestFunc = func(ctx context.Context, groupID, userID string) error {
+		roles[userID] = groups.RoleMember
+		return nil
+	}
+	router, connect := liveGroupRouter(t, repo)
+	owner, member, requester := connect("owner"), connect("member"), connect("requester")
+
+	rr := doItinerary(t, router, "requester", "POST", "/groups/g1/join", groups.JoinGroupRequest{Message: "Sharing a cab?"})
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("join: got %d, want 202. Body: %s", rr.Code, rr.Body.String())
+	}
+	var received messages.WSJoinRequestReceived
+	if typ := nextGroupEvent(t, owner, &received); typ != messages.EventJoinRequestReceived ||
+		received.GroupID != "g1" || received.UserID != "requester" || received.Message != "Sharing a cab?" {
+		t.Errorf("owner got %s %+v, want the join request", typ, received)
+	}
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/requests/requester/accept", nil); rr.Code != http.StatusOK {
+		t.Fatalf("accept: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var resolved messages.WSJoinRequestResolved
+	if typ := nextGroupEvent(t, requester, &resolved); typ != messages.EventJoinRequestResolved ||
+		resolved.Status != groups.RequestAccepted || resolved.ResolvedBy != "owner" {
+		t.Errorf("requester got %s %+v, want the request accepted by owner", typ, resolved)
+	}
+	var joined messages.WSMemberJoined
+	if typ := nextGroupEvent(t, requester, &joined); typ != messages.EventMemberJoined ||
+		joined.UserID != "requester" || joined.Via != messages.JoinedByRequest || joined.ApprovedBy != "owner" {
+		t.Errorf("requester got %s %+v, want their own member_joined", typ, joined)
+	}
+
+	// Plain members only hear about the new member, not the request
+	if typ := nextGroupEvent(t, member, &joined); typ != messages.EventMemberJoined || joined.UserID != "requester" {
+		t.Errorf("member got %s %+v, want member_joined", typ, joined)
+	}
+}
+
+func TestKickAndLock_LiveEvents(t *testing.T) {
+	roles := map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember, "kicked": groups.RoleMember}
+	repo := liveRepo(roles)
+	repo.SetGroupStatusFunc = func(ctx context.Context, groupID, from, to string) error { return nil }
+	router, connect := liveGroupRouter(t, repo)
+	member, kicked := connect("member"), connect("kicked")
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/kick", groups.KickRequest{UserID: "kicked"}); rr.Code != http.StatusOK {
+		t.Fatalf("kick: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	for user, conn := range map[string]*websocket.Conn{"member": member, "kicked": kicked} {
+		var left messages.WSMemberLeft
+		if typ := nextGroupEvent(t, conn, &left); typ != messages.EventMemberLeft || left.UserID != "kicked" || left.RemovedBy != "owner" {
+			t.Errorf("%s got %s %+v, want member_left removed by owner", user, typ, left)
+		}
+	}
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/lock", nil); rr.Code != http.StatusOK {
+		t.Fatalf("lock: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var updated messages.WSGroupUpdated
+	if typ := nextGroupEvent(t, member, &updated); typ != messages.EventGroupUpdated ||
+		updated.Change != messages.GroupChangeStatus || updated.Status != groups.StatusLocked || updated.ActorID != "owner" {
+		t.Errorf("member got %s %+v, want group_updated to locked", typ, updated)
+	}
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -32,7 +33,7 @@ func TestCreateGroup_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]interface{}{
@@ -61,7 +62,7 @@ func TestCreateGroup_RequiresAuth(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]interface{}{
@@ -86,7 +87,7 @@ func TestCreateGroup_ValidationFails(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	// Missing event_id and name
@@ -105,14 +106,14 @@ func TestCreateGroup_ValidationFails(t *testing.T) {
 	}
 }
 
-func TestJoinGroup_GroupFull(t *testing.T) {
+func TestJoinGroup_GroupFullJoinsWaitlist(t *testing.T) {
 	t.Setenv("JWT_SECRET", "testsecret")
 
 	mockGroupsRepo := &MockGroupsRepositoryFull{
 		GetGroupFunc: func(ctx context.Context, groupID string) (*groups.Group, error) {
 			return &groups.Group{ID: "grp-1", MaxMembers: 4}, nil
 		},
-		JoinGroupCheckedFunc: func(ctx context.Context, groupID, userID string) (bool, error) {
+		JoinGroupCheckedFunc: func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
 			return false, groups.ErrGroupFull // atomic check says full
 		},
 	}
@@ -120,7 +121,7 @@ func TestJoinGroup_GroupFull(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("POST", "/groups/grp-1/join", nil)
@@ -130,8 +131,13 @@ func TestJoinGroup_GroupFull(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
 
-	if rr.Code != http.StatusBadRequest {
-		t.Errorf("POST /groups/{id}/join (full): got %d, want %d. Body: %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	if rr.Code != http.StatusAccepted {
+		t.Errorf("POST /groups/{id}/join (full): got %d, want %d. Body: %s", rr.Code, http.StatusAccepted, rr.Body.String())
+	}
+	var resp map[string]interface{}
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if resp["position"] != float64(1) {
+		t.Errorf("waitlist position = %v, want 1", resp["position"])
 	}
 }
 
@@ -141,7 +147,7 @@ func TestSuggestedGroups_RequiresAuth(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/groups/suggested?event_id=evt-1", nil)
@@ -165,7 +171,7 @@ func TestSuggestedGroups_RequiresEventID(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/groups/suggested", nil) // missing event_id
@@ -186,7 +192,7 @@ func TestFindMatches_RequiresAuth(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/users/matches?event_id=evt-1", nil)
@@ -217,7 +223,7 @@ func TestFindMatches_ReturnsScoredResults(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/users/matches?event_id=evt-1", nil)
@@ -275,7 +281,7 @@ func TestGetGroup_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/groups/grp-1", nil)
@@ -307,7 +313,7 @@ func TestGetGroup_RequiresAuth(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/groups/grp-1", nil)
@@ -329,7 +335,7 @@ func TestUpdateGroup_Success(t *testing.T) {
 		GetGroupFunc: func(ctx context.Context, groupID string) (*groups.Group, error) {
 			return &groups.Group{ID: groupID, Name: "Old Name", CreatedBy: creatorID, MaxMembers: 4}, nil
 		},
-		UpdateGroupFunc: func(ctx context.Context, groupID, name, description, meetingPoint string, departureDate *time.Time) error {
+		UpdateGroupFunc: func(ctx context.Context, group *groups.Group) error {
 			return nil
 		},
 	}
@@ -337,7 +343,7 @@ func TestUpdateGroup_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{"name": "New Name", "description": "Updated description"}
@@ -367,7 +373,7 @@ func TestUpdateGroup_ForbiddenForNonCreator(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{"name": "Hacked Name"}
@@ -399,7 +405,7 @@ func TestUpdateGroup_MissingName(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{"description": "Only description, no name"}
@@ -435,7 +441,7 @@ func TestDeleteGroup_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("DELETE", "/groups/grp-1", nil)
@@ -462,7 +468,7 @@ func TestDeleteGroup_ForbiddenForNonCreator(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("DELETE", "/groups/grp-1", nil)
@@ -500,7 +506,7 @@ func TestLeaveGroup_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("POST", "/groups/grp-1/leave", nil)
@@ -515,7 +521,8 @@ func TestLeaveGroup_Success(t *testing.T) {
 	}
 }
 
-func TestLeaveGroup_CreatorCannotLeave(t *testing.T) {
+// The owner may leave once someone else can take over; as the only member they must delete instead.
+func TestLeaveGroup_SoleOwnerCannotLeave(t *testing.T) {
 	t.Setenv("JWT_SECRET", "testsecret")
 
 	creatorID := "creator-user-id"
@@ -528,7 +535,7 @@ func TestLeaveGroup_CreatorCannotLeave(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("POST", "/groups/grp-1/leave", nil)
@@ -539,7 +546,7 @@ func TestLeaveGroup_CreatorCannotLeave(t *testing.T) {
 	router.ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusBadRequest {
-		t.Errorf("POST /groups/{id}/leave by creator: got %d, want 400", rr.Code)
+		t.Errorf("POST /groups/{id}/leave by sole owner: got %d, want 400", rr.Code)
 	}
 }
 
@@ -558,7 +565,7 @@ func TestLeaveGroup_NotAMember(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("POST", "/groups/grp-1/leave", nil)
@@ -596,7 +603,7 @@ func TestKickMember_Success(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{"user_id": targetID}
@@ -625,7 +632,7 @@ func TestKickMember_ForbiddenForNonCreator(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{"user_id": "someone"}
@@ -655,7 +662,7 @@ func TestKickMember_CannotKickSelf(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	// Try to kick yourself
@@ -689,7 +696,7 @@ func TestKickMember_TargetNotInGroup(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	payload := map[string]string{"user_id": "ghost-user"}
@@ -713,7 +720,7 @@ type MockGroupsRepositoryFull struct {
 	GetGroupFunc                        func(ctx context.Context, groupID string) (*groups.Group, error)
 	GetGroupThreadIDFunc                func(ctx context.Context, groupID string) (string, error)
 	JoinGroupFunc                       func(ctx context.Context, groupID, userID string) error
-	JoinGroupCheckedFunc                func(ctx context.Context, groupID, userID string) (bool, error)
+	JoinGroupCheckedFunc                func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error)
 	GetMemberCountFunc                  func(ctx context.Context, groupID string) (int, error)
 	GetGroupsForEventFunc               func(ctx context.Context, eventID string) ([]groups.Group, error)
 	GetGroupsWithCountsForEventFunc     func(ctx context.Context, eventID string) ([]groups.GroupWithDetails, error)
@@ -721,12 +728,55 @@ type MockGroupsRepositoryFull struct {
 	GetGroupMemberInterestsFunc         func(ctx context.Context, groupID string) ([][]string, error)
 	GetUsersForEventFunc                func(ctx context.Context, eventID, excludeUserID string) ([]groups.UserWithInterests, error)
 	GetUserInterestsFunc                func(ctx context.Context, userID string) ([]string, error)
+	GetMatchProfileFunc                 func(ctx context.Context, userID string) (groups.MatchProfile, error)
+	GetMatchProfilesFunc                func(ctx context.Context, userIDs []string) (map[string]groups.MatchProfile, error)
+	GetGroupMemberProfilesForEventFunc  func(ctx context.Context, eventID string) (map[string][]groups.MatchProfile, error)
+	GetGroupMemberProfilesFunc          func(ctx context.Context, groupID string) ([]groups.MatchProfile, error)
 	GetGroupMembersFunc                 func(ctx context.Context, groupID string) ([]groups.GroupMemberProfile, error)
-	UpdateGroupFunc                     func(ctx context.Context, groupID, name, description, meetingPoint string, departureDate *time.Time) error
+	UpdateGroupFunc                     func(ctx context.Context, group *groups.Group) error
 	DeleteGroupFunc                     func(ctx context.Context, groupID string) error
+	AcceptJoinRequestFunc               func(ctx context.Context, groupID, userID string) error
+	GetJoinRequestsFunc                 func(ctx context.Context, groupID string) ([]groups.JoinRequest, error)
+	DeclineJoinRequestFunc              func(ctx context.Context, groupID, userID string) error
+	CancelJoinRequestFunc               func(ctx context.Context, groupID, userID string) error
+	GetUserJoinRequestsFunc             func(ctx context.Context, userID string) ([]groups.OutgoingJoinRequest, error)
+	ExpireJoinRequestsFunc              func(ctx context.Context, now time.Time, limit int) ([]groups.OutgoingJoinRequest, error)
+	SetGroupStatusFunc                  func(ctx context.Context, groupID, from, to string) error
+	AdvanceGroupLifecycleFunc           func(ctx context.Context, now time.Time, archiveAfter time.Duration) ([]groups.StatusChange, error)
 	RemoveMemberFunc                    func(ctx context.Context, groupID, userID string) error
 	IsGroupMemberFunc                   func(ctx context.Context, groupID, userID string) (bool, error)
 	GetUserGroupsFunc                   func(ctx context.Context, userID string) ([]groups.GroupWithDetails, error)
+	SearchGroupsFunc                    func(ctx context.Context, userID string, search groups.GroupSearch) ([]groups.GroupWithDetails, error)
+	GetItineraryFunc                    func(ctx context.Context, groupID string) (*groups.Itinerary, error)
+	SaveItineraryFunc                   func(ctx context.Context, groupID, userID string, legs []groups.ItineraryLeg) (*groups.Itinerary, error)
+	DeleteItineraryFunc                 func(ctx context.Context, groupID string) error
+	AddItineraryLegFunc                 func(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error)
+	UpdateItineraryLegFunc              func(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error)
+	DeleteItineraryLegFunc              func(ctx context.Context, groupID, userID, legID string) (*groups.Itinerary, error)
+	CreateExpenseFunc                   func(ctx context.Context, expense *groups.Expense) error
+	GetExpenseFunc                      func(ctx context.Context, groupID, expenseID string) (*groups.Expense, error)
+	GetExpensesFunc                     func(ctx context.Context, groupID string) ([]groups.Expense, error)
+	DeleteExpenseFunc                   func(ctx context.Context, groupID, expenseID string) error
+	CreateSettlementFunc                func(ctx context.Context, settlement *groups.Settlement) error
+	GetSettlementsFunc                  func(ctx context.Context, groupID string) ([]groups.Settlement, error)
+	GetMemberRoleFunc                   func(ctx context.Context, groupID, userID string) (string, error)
+	SetMemberRoleFunc                   func(ctx context.Context, groupID, userID, role string) error
+	TransferOwnershipFunc               func(ctx context.Context, groupID, toUserID string) error
+	CreateInviteFunc                    func(ctx context.Context, invite *groups.Invite) error
+	GetInvitesFunc                      func(ctx context.Context, groupID string) ([]groups.Invite, error)
+	GetInviteByCodeFunc                 func(ctx context.Context, code string) (*groups.InviteDetails, error)
+	GetUserInvitesFunc                  func(ctx context.Context, userID string) ([]groups.InviteDetails, error)
+	UseInviteFunc                       func(ctx context.Context, inviteID string) error
+	ReleaseInviteFunc                   func(ctx context.Context, inviteID string) error
+	CloseInviteFunc                     func(ctx context.Context, groupID, inviteID, status string) error
+	JoinWaitlistFunc                    func(ctx context.Context, groupID, userID string) (int, error)
+	GetWaitlistFunc                     func(ctx context.Context, groupID string) ([]groups.WaitlistEntry, error)
+	LeaveWaitlistFunc                   func(ctx context.Context, groupID, userID string) error
+	PromoteFromWaitlistFunc             func(ctx context.Context, groupID string) ([]groups.Promotion, error)
+	ClaimWaitlistOfferFunc              func(ctx context.Context, groupID, userID string) error
+	ExpireWaitlistOffersFunc            func(ctx context.Context, now time.Time, limit int) ([]groups.WaitlistEntry, error)
+	CreateRatingFunc                    func(ctx context.Context, rating *groups.Rating) error
+	GetRatingsByRaterFunc               func(ctx context.Context, groupID, raterID string) ([]groups.Rating, error)
 }
 
 func (m *MockGroupsRepositoryFull) CreateGroup(ctx context.Context, group *groups.Group) error {
@@ -739,7 +789,7 @@ func (m *MockGroupsRepositoryFull) GetGroup(ctx context.Context, groupID string)
 	if m.GetGroupFunc != nil {
 		return m.GetGroupFunc(ctx, groupID)
 	}
-	return &groups.Group{MaxMembers: 4}, nil
+	return &groups.Group{MaxMembers: 4, Status: groups.StatusOpen}, nil
 }
 func (m *MockGroupsRepositoryFull) GetGroupThreadID(ctx context.Context, groupID string) (string, error) {
 	if m.GetGroupThreadIDFunc != nil {
@@ -753,9 +803,9 @@ func (m *MockGroupsRepositoryFull) JoinGroup(ctx context.Context, groupID, userI
 	}
 	return nil
 }
-func (m *MockGroupsRepositoryFull) JoinGroupChecked(ctx context.Context, groupID, userID string) (bool, error) {
+func (m *MockGroupsRepositoryFull) JoinGroupChecked(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
 	if m.JoinGroupCheckedFunc != nil {
-		return m.JoinGroupCheckedFunc(ctx, groupID, userID)
+		return m.JoinGroupCheckedFunc(ctx, groupID, userID, skipApproval, message)
 	}
 	return false, nil
 }
@@ -809,9 +859,41 @@ func (m *MockGroupsRepositoryFull) GetGroupMembers(ctx context.Context, groupID
 	return []groups.GroupMemberProfile{}, nil
 }
 
-func (m *MockGroupsRepositoryFull) UpdateGroup(ctx context.Context, groupID, name, description, meetingPoint string, departureDate *time.Time) error {
+func (m *MockGroupsRepositoryFull) GetMatchProfile(ctx context.Context, userID string) (groups.MatchProfile, error) {
+	if m.GetMatchProfileFunc != nil {
+		return m.GetMatchProfileFunc(ctx, userID)
+	}
+	return groups.MatchProfile{UserID: userID}, nil
+}
+
+func (m *MockGroupsRepositoryFull) GetMatchProfiles(ctx context.Context, userIDs []string) (map[string]groups.MatchProfile, error) {
+	if m.GetMatchProfilesFunc != nil {
+		return m.GetMatchProfilesFunc(ctx, userIDs)
+	}
+	profiles := make(map[string]groups.MatchProfile)
+	for _, id := range userIDs {
+		profiles[id] = groups.MatchProfile{UserID: id}
+	}
+	return profiles, nil
+}
+
+func (m *MockGroupsRepositoryFull) GetGroupMemberProfilesForEvent(ctx context.Context, eventID string) (map[string][]groups.MatchProfile, error) {
+	if m.GetGroupMemberProfilesForEventFunc != nil {
+		return m.GetGroupMemberProfilesForEventFunc(ctx, eventID)
+	}
+	return map[string][]groups.MatchProfile{}, nil
+}
+
+func (m *MockGroupsRepositoryFull) GetGroupMemberProfiles(ctx context.Context, groupID string) ([]groups.MatchProfile, error) {
+	if m.GetGroupMemberProfilesFunc != nil {
+		return m.GetGroupMemberProfilesFunc(ctx, groupID)
+	}
+	return []groups.MatchProfile{}, nil
+}
+
+func (m *MockGroupsRepositoryFull) UpdateGroup(ctx context.Context, group *groups.Group) error {
 	if m.UpdateGroupFunc != nil {
-		return m.UpdateGroupFunc(ctx, groupID, name, description, meetingPoint, departureDate)
+		return m.UpdateGroupFunc(ctx, group)
 	}
 	return nil
 }
@@ -822,6 +904,18 @@ func (m *MockGroupsRepositoryFull) DeleteGroup(ctx context.Context, groupID stri
 	}
 	return nil
 }
+func (m *MockGroupsRepositoryFull) SetGroupStatus(ctx context.Context, groupID, from, to string) error {
+	if m.SetGroupStatusFunc != nil {
+		return m.SetGroupStatusFunc(ctx, groupID, from, to)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) AdvanceGroupLifecycle(ctx context.Context, now time.Time, archiveAfter time.Duration) ([]groups.StatusChange, error) {
+	if m.AdvanceGroupLifecycleFunc != nil {
+		return m.AdvanceGroupLifecycleFunc(ctx, now, archiveAfter)
+	}
+	return nil, nil
+}
 
 func (m *MockGroupsRepositoryFull) RemoveMember(ctx context.Context, groupID, userID string) error {
 	if m.RemoveMemberFunc != nil {
@@ -844,22 +938,240 @@ func (m *MockGroupsRepositoryFull) GetUserGroups(ctx context.Context, userID str
 	return []groups.GroupWithDetails{}, nil
 }
 
-func (m *MockGroupsRepositoryFull) GetAllGroups(ctx context.Context, userID string) ([]groups.GroupWithDetails, error) {
+func (m *MockGroupsRepositoryFull) SearchGroups(ctx context.Context, userID string, search groups.GroupSearch) ([]groups.GroupWithDetails, error) {
+	if m.SearchGroupsFunc != nil {
+		return m.SearchGroupsFunc(ctx, userID, search)
+	}
 	return []groups.GroupWithDetails{}, nil
 }
 
 func (m *MockGroupsRepositoryFull) CreateJoinRequest(ctx context.Context, groupID, userID string) error {
 	return nil
 }
-func (m *MockGroupsRepositoryFull) GetJoinRequests(ctx context.Context, groupID string) ([]groups.GroupMemberProfile, error) {
-	return []groups.GroupMemberProfile{}, nil
+func (m *MockGroupsRepositoryFull) GetJoinRequests(ctx context.Context, groupID string) ([]groups.JoinRequest, error) {
+	if m.GetJoinRequestsFunc != nil {
+		return m.GetJoinRequestsFunc(ctx, groupID)
+	}
+	return []groups.JoinRequest{}, nil
 }
 func (m *MockGroupsRepositoryFull) AcceptJoinRequest(ctx context.Context, groupID, userID string) error {
+	if m.AcceptJoinRequestFunc != nil {
+		return m.AcceptJoinRequestFunc(ctx, groupID, userID)
+	}
 	return nil
 }
 func (m *MockGroupsRepositoryFull) DeclineJoinRequest(ctx context.Context, groupID, userID string) error {
+	if m.DeclineJoinRequestFunc != nil {
+		return m.DeclineJoinRequestFunc(ctx, groupID, userID)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) CancelJoinRequest(ctx context.Context, groupID, userID string) error {
+	if m.CancelJoinRequestFunc != nil {
+		return m.CancelJoinRequestFunc(ctx, groupID, userID)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) GetUserJoinRequests(ctx context.Context, userID string) ([]groups.OutgoingJoinRequest, error) {
+	if m.GetUserJoinRequestsFunc != nil {
+		return m.GetUserJoinRequestsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+func (m *MockGroupsRepositoryFull) ExpireJoinRequests(ctx context.Context, now time.Time, limit int) ([]groups.OutgoingJoinRequest, error) {
+	if m.ExpireJoinRequestsFunc != nil {
+		return m.ExpireJoinRequestsFunc(ctx, now, limit)
+	}
+	return nil, nil
+}
+func (m *MockGroupsRepositoryFull) GetItinerary(ctx context.Context, groupID string) (*groups.Itinerary, error) {
+	if m.GetItineraryFunc != nil {
+		return m.GetItineraryFunc(ctx, groupID)
+	}
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepositoryFull) SaveItinerary(ctx context.Context, groupID, userID string, legs []groups.ItineraryLeg) (*groups.Itinerary, error) {
+	if m.SaveItineraryFunc != nil {
+		return m.SaveItineraryFunc(ctx, groupID, userID, legs)
+	}
+	return &groups.Itinerary{GroupID: groupID, Legs: legs, UpdatedBy: userID}, nil
+}
+func (m *MockGroupsRepositoryFull) AddItineraryLeg(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error) {
+	if m.AddItineraryLegFunc != nil {
+		return m.AddItineraryLegFunc(ctx, groupID, userID, leg)
+	}
+	return &groups.Itinerary{GroupID: groupID, Legs: []groups.ItineraryLeg{leg}, UpdatedBy: userID}, nil
+}
+func (m *MockGroupsRepositoryFull) UpdateItineraryLeg(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error) {
+	if m.UpdateItineraryLegFunc != nil {
+		return m.UpdateItineraryLegFunc(ctx, groupID, userID, leg)
+	}
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepositoryFull) DeleteItineraryLeg(ctx context.Context, groupID, userID, legID string) (*groups.Itinerary, error) {
+	if m.DeleteItineraryLegFunc != nil {
+		return m.DeleteItineraryLegFunc(ctx, groupID, userID, legID)
+	}
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepositoryFull) DeleteItinerary(ctx context.Context, groupID string) error {
+	if m.DeleteItineraryFunc != nil {
+		return m.DeleteItineraryFunc(ctx, groupID)
+	}
+	return sql.ErrNoRows
+}
+func (m *MockGroupsRepositoryFull) CreateExpense(ctx context.Context, expense *groups.Expense) error {
+	if m.CreateExpenseFunc != nil {
+		return m.CreateExpenseFunc(ctx, expense)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) GetExpense(ctx context.Context, groupID, expenseID string) (*groups.Expense, error) {
+	if m.GetExpenseFunc != nil {
+		return m.GetExpenseFunc(ctx, groupID, expenseID)
+	}
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepositoryFull) GetExpenses(ctx context.Context, groupID string) ([]groups.Expense, error) {
+	if m.GetExpensesFunc != nil {
+		return m.GetExpensesFunc(ctx, groupID)
+	}
+	return []groups.Expense{}, nil
+}
+func (m *MockGroupsRepositoryFull) DeleteExpense(ctx context.Context, groupID, expenseID string) error {
+	if m.DeleteExpenseFunc != nil {
+		return m.DeleteExpenseFunc(ctx, groupID, expenseID)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) CreateSettlement(ctx context.Context, settlement *groups.Settlement) error {
+	if m.CreateSettlementFunc != nil {
+		return m.CreateSettlementFunc(ctx, settlement)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) GetSettlements(ctx context.Context, groupID string) ([]groups.Settlement, error) {
+	if m.GetSettlementsFunc != nil {
+		return m.GetSettlementsFunc(ctx, groupID)
+	}
+	return []groups.Settlement{}, nil
+}
+// GetMemberRole defaults to what the roles migration backfills: the creator
+// owns the group and every other member is a plain member.
+func (m *MockGroupsRepositoryFull) GetMemberRole(ctx context.Context, groupID, userID string) (string, error) {
+	if m.GetMemberRoleFunc != nil {
+		return m.GetMemberRoleFunc(ctx, groupID, userID)
+	}
+	if g, err := m.GetGroup(ctx, groupID); err == nil && g.CreatedBy == userID {
+		return groups.RoleOwner, nil
+	}
+	if member, err := m.IsGroupMember(ctx, groupID, userID); err != nil || !member {
+		return "", sql.ErrNoRows
+	}
+	return groups.RoleMember, nil
+}
+func (m *MockGroupsRepositoryFull) SetMemberRole(ctx context.Context, groupID, userID, role string) error {
+	if m.SetMemberRoleFunc != nil {
+		return m.SetMemberRoleFunc(ctx, groupID, userID, role)
+	}
 	return nil
 }
+func (m *MockGroupsRepositoryFull) TransferOwnership(ctx context.Context, groupID, toUserID string) error {
+	if m.TransferOwnershipFunc != nil {
+		return m.TransferOwnershipFunc(ctx, groupID, toUserID)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) CreateInvite(ctx context.Context, invite *groups.Invite) error {
+	if m.CreateInviteFunc != nil {
+		return m.CreateInviteFunc(ctx, invite)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) GetInvites(ctx context.Context, groupID string) ([]groups.Invite, error) {
+	if m.GetInvitesFunc != nil {
+		return m.GetInvitesFunc(ctx, groupID)
+	}
+	return []groups.Invite{}, nil
+}
+func (m *MockGroupsRepositoryFull) GetInviteByCode(ctx context.Context, code string) (*groups.InviteDetails, error) {
+	if m.GetInviteByCodeFunc != nil {
+		return m.GetInviteByCodeFunc(ctx, code)
+	}
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepositoryFull) GetUserInvites(ctx context.Context, userID string) ([]groups.InviteDetails, error) {
+	if m.GetUserInvitesFunc != nil {
+		return m.GetUserInvitesFunc(ctx, userID)
+	}
+	return []groups.InviteDetails{}, nil
+}
+func (m *MockGroupsRepositoryFull) UseInvite(ctx context.Context, inviteID string) error {
+	if m.UseInviteFunc != nil {
+		return m.UseInviteFunc(ctx, inviteID)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) ReleaseInvite(ctx context.Context, inviteID string) error {
+	if m.ReleaseInviteFunc != nil {
+		return m.ReleaseInviteFunc(ctx, inviteID)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) CloseInvite(ctx context.Context, groupID, inviteID, status string) error {
+	if m.CloseInviteFunc != nil {
+		return m.CloseInviteFunc(ctx, groupID, inviteID, status)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) JoinWaitlist(ctx context.Context, groupID, userID string) (int, error) {
+	if m.JoinWaitlistFunc != nil {
+		return m.JoinWaitlistFunc(ctx, groupID, userID)
+	}
+	return 1, nil
+}
+func (m *MockGroupsRepositoryFull) GetWaitlist(ctx context.Context, groupID string) ([]groups.WaitlistEntry, error) {
+	if m.GetWaitlistFunc != nil {
+		return m.GetWaitlistFunc(ctx, groupID)
+	}
+	return []groups.WaitlistEntry{}, nil
+}
+func (m *MockGroupsRepositoryFull) LeaveWaitlist(ctx context.Context, groupID, userID string) error {
+	if m.LeaveWaitlistFunc != nil {
+		return m.LeaveWaitlistFunc(ctx, groupID, userID)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) PromoteFromWaitlist(ctx context.Context, groupID string) ([]groups.Promotion, error) {
+	if m.PromoteFromWaitlistFunc != nil {
+		return m.PromoteFromWaitlistFunc(ctx, groupID)
+	}
+	return nil, nil
+}
+func (m *MockGroupsRepositoryFull) ClaimWaitlistOffer(ctx context.Context, groupID, userID string) error {
+	if m.ClaimWaitlistOfferFunc != nil {
+		return m.ClaimWaitlistOfferFunc(ctx, groupID, userID)
+	}
+	return groups.ErrNoWaitlistOffer
+}
+func (m *MockGroupsRepositoryFull) ExpireWaitlistOffers(ctx context.Context, now time.Time, limit int) ([]groups.WaitlistEntry, error) {
+	if m.ExpireWaitlistOffersFunc != nil {
+		return m.ExpireWaitlistOffersFunc(ctx, now, limit)
+	}
+	return nil, nil
+}
+func (m *MockGroupsRepositoryFull) CreateRating(ctx context.Context, rating *groups.Rating) error {
+	if m.CreateRatingFunc != nil {
+		return m.CreateRatingFunc(ctx, rating)
+	}
+	return nil
+}
+func (m *MockGroupsRepositoryFull) GetRatingsByRater(ctx context.Context, groupID, raterID string) ([]groups.Rating, error) {
+	if m.GetRatingsByRaterFunc != nil {
+		return m.GetRatingsByRaterFunc(ctx, groupID, raterID)
+	}
+	return nil, nil
+}
 
 
 
@@ -881,7 +1193,7 @@ func TestGetMyGroups_ReturnsGroups(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/me/groups", nil)
@@ -919,7 +1231,7 @@ func TestGetMyGroups_ReturnsEmptyWhenNone(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, mockGroupsRepo,
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/me/groups", nil)
@@ -948,7 +1260,7 @@ func TestGetMyGroups_RequiresAuth(t *testing.T) {
 	router := server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		nil, nil, &MockFileStorage{}, "./uploads",
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 
 	req, _ := http.NewRequest("GET", "/me/groups", nil)
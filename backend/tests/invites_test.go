@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+)
+
+// invitesRepo is a groups repo with one approval-only group created by "owner".
+func invitesRepo(members ...string) *MockGroupsRepositoryFull {
+	repo := itineraryRepo(nil, members...)
+	repo.GetGroupFunc = func(ctx context.Context, groupID string) (*groups.Group, error) {
+		return &groups.Group{ID: groupID, Name: "Team Alpha", CreatedBy: "owner", MaxMembers: 4, RequiresApproval: true, Status: groups.StatusOpen}, nil
+	}
+	return repo
+}
+
+func linkInvite(mutate func(*groups.InviteDetails)) func(ctx context.Context, code string) (*groups.InviteDetails, error) {
+	return func(ctx context.Context, code string) (*groups.InviteDetails, error) {
+		d := &groups.InviteDetails{Invite: groups.Invite{
+			ID: "inv-1", GroupID: "g1", Code: code, Kind: groups.InviteLink, CreatedBy: "owner",
+			ExpiresAt: time.Now().Add(time.Hour), Status: groups.InviteActive,
+		}}
+		if mutate != nil {
+			mutate(d)
+		}
+		return d, nil
+	}
+}
+
+func TestCreateInvite_Link(t *testing.T) {
+	var created *groups.Invite
+	repo := invitesRepo("owner", "member")
+	repo.CreateInviteFunc = func(ctx context.Context, invite *groups.Invite) error {
+		created = invite
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "member", "POST", "/groups/g1/invites", map[string]interface{}{}); rr.Code != http.StatusForbidden {
+		t.Errorf("non-creator creating invite: got %d, want 403", rr.Code)
+	}
+	for name, body := range map[string]map[string]interface{}{
+		"zero uses":         {"max_uses": 0},
+		"too long":          {"expires_in_hours": 24 * 31},
+		"direct with limit": {"user_id": "friend", "max_uses": 3},
+	} {
+		if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/invites", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", name, rr.Code)
+		}
+	}
+
+	rr := doItinerary(t, router, "owner", "POST", "/groups/g1/invites", map[string]interface{}{"max_uses": 5, "expires_in_hours": 48})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST invite: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if created == nil || created.Kind != groups.InviteLink || len(created.Code) != 22 || *created.MaxUses != 5 {
+		t.Fatalf("created invite = %+v", created)
+	}
+	if ttl := time.Until(created.ExpiresAt); ttl < 47*time.Hour || ttl > 48*time.Hour {
+		t.Errorf("invite expires in %v, want 48h", ttl)
+	}
+}
+
+func TestCreateInvite_Direct(t *testing.T) {
+	var created *groups.Invite
+	repo := invitesRepo("owner", "member")
+	repo.CreateInviteFunc = func(ctx context.Context, invite *groups.Invite) error {
+		if invite.InviteeID == "ghost" {
+			return groups.ErrInviteeNotFound
+		}
+		created = invite
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/invites", map[string]string{"user_id": "member"}); rr.Code != http.StatusConflict {
+		t.Errorf("inviting a member: got %d, want 409", rr.Code)
+	}
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/invites", map[string]string{"user_id": "ghost"}); rr.Code != http.StatusNotFound {
+		t.Errorf("inviting an unknown user: got %d, want 404", rr.Code)
+	}
+
+	rr := doItinerary(t, router, "owner", "POST", "/groups/g1/invites", map[string]string{"user_id": "friend"})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST direct invite: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if created == nil || created.Kind != groups.InviteDirect || created.InviteeID != "friend" || *created.MaxUses != 1 {
+		t.Errorf("created invite = %+v, want a single-use direct invite for friend", created)
+	}
+}
+
+func TestAcceptInvite_SkipsApproval(t *testing.T) {
+	used, skipped := false, false
+	repo := invitesRepo("owner")
+	repo.GetInviteByCodeFunc = linkInvite(nil)
+	repo.UseInviteFunc = func(ctx context.Context, inviteID string) error {
+		used = true
+		return nil
+	}
+	repo.JoinGroupCheckedFunc = func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
+		skipped = skipApproval
+		return false, nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	rr := doItinerary(t, router, "newbie", "POST", "/invites/abc/accept", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("accept invite: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if !used || !skipped {
+		t.Errorf("invite used = %v, approval skipped = %v; want both", used, skipped)
+	}
+	if len(*posted) != 1 || (*posted)[0].metadata["invited_by"] != "owner" {
+		t.Errorf("system messages = %+v, want member_joined invited by owner", *posted)
+	}
+}
+
+func TestAcceptInvite_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   string
+		mutate func(*groups.InviteDetails)
+		code   int
+	}{
+		{"expired", "newbie", func(d *groups.InviteDetails) { d.ExpiresAt = time.Now().Add(-time.Minute) }, http.StatusGone},
+		{"used up", "newbie", func(d *groups.InviteDetails) { two := 2; d.MaxUses, d.Uses = &two, 2 }, http.StatusGone},
+		{"revoked", "newbie", func(d *groups.InviteDetails) { d.Status = groups.InviteRevoked }, http.StatusGone},
+		{"for someone else", "newbie", func(d *groups.InviteDetails) { d.Kind, d.InviteeID = groups.InviteDirect, "friend" }, http.StatusForbidden},
+		{"already a member", "owner", nil, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		joined := false
+		repo := invitesRepo("owner")
+		repo.GetInviteByCodeFunc = linkInvite(tt.mutate)
+		repo.JoinGroupCheckedFunc = func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
+			joined = true
+			return false, nil
+		}
+		router, _ := newSystemMessageRouter(t, repo)
+
+		rr := doItinerary(t, router, tt.user, "POST", "/invites/abc/accept", nil)
+		if rr.Code != tt.code || joined {
+			t.Errorf("%s: got %d (joined=%v), want %d", tt.name, rr.Code, joined, tt.code)
+		}
+	}
+}
+
+func TestAcceptInvite_GroupFullReleasesUse(t *testing.T) {
+	released := false
+	repo := invitesRepo("owner")
+	repo.GetInviteByCodeFunc = linkInvite(nil)
+	repo.JoinGroupCheckedFunc = func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
+		return false, groups.ErrGroupFull
+	}
+	repo.ReleaseInviteFunc = func(ctx context.Context, inviteID string) error {
+		released = true
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	rr := doItinerary(t, router, "newbie", "POST", "/invites/abc/accept", nil)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("accepting into a full group: got %d, want 400", rr.Code)
+	}
+	if !released {
+		t.Error("the claimed invite use should be released when the group is full")
+	}
+}
+
+func TestGetMyInvites(t *testing.T) {
+	repo := invitesRepo()
+	repo.GetUserInvitesFunc = func(ctx context.Context, userID string) ([]groups.InviteDetails, error) {
+		d, _ := linkInvite(func(d *groups.InviteDetails) {
+			d.Kind, d.InviteeID, d.GroupName = groups.InviteDirect, userID, "Team Alpha"
+		})(ctx, "abc")
+		return []groups.InviteDetails{*d}, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	rr := doItinerary(t, router, "friend", "GET", "/me/invites", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /me/invites: got %d, want 200", rr.Code)
+	}
+	var invites []groups.InviteDetails
+	json.NewDecoder(rr.Body).Decode(&invites)
+	if len(invites) != 1 || invites[0].GroupName != "Team Alpha" || invites[0].InviteeID != "friend" {
+		t.Errorf("invites = %+v", invites)
+	}
+}
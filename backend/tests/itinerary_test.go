@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+var legDeparture = time.Date(2026, 11, 3, 6, 0, 0, 0, time.UTC)
+
+func itineraryLeg(id, from, to string, departs time.Time) groups.ItineraryLeg {
+	return groups.ItineraryLeg{
+		ID: id, Mode: groups.TransportTrain, Carrier: "IR", Number: "12724",
+		DepartureStation: from, DepartureTime: departs,
+		ArrivalStation: to, ArrivalTime: departs.Add(3 * time.Hour),
+		BookingReference: "PNR4521",
+	}
+}
+
+// itineraryRepo is a groups repo with one group whose members are listed.
+func itineraryRepo(legs []groups.ItineraryLeg, members ...string) *MockGroupsRepositoryFull {
+	return &MockGroupsRepositoryFull{
+		IsGroupMemberFunc: func(ctx context.Context, groupID, userID string) (bool, error) {
+			for _, m := range members {
+				if m == userID {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		GetItineraryFunc: func(ctx context.Context, groupID string) (*groups.Itinerary, error) {
+			return &groups.Itinerary{GroupID: groupID, Legs: append([]groups.ItineraryLeg{}, legs...)}, nil
+		},
+	}
+}
+
+func doItinerary(t *testing.T, router http.Handler, userID, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &buf)
+	token, _ := auth.GenerateToken(userID, "student@nitw.ac.in")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGetItinerary_BookingReferenceMembersOnly(t *testing.T) {
+	repo := itineraryRepo([]groups.ItineraryLeg{itineraryLeg("leg-1", "Secunderabad", "Warangal", legDeparture)}, "member")
+	router, _ := newSystemMessageRouter(t, repo)
+
+	for user, want := range map[string]string{"member": "PNR4521", "outsider": ""} {
+		rr := doItinerary(t, router, user, "GET", "/groups/g1/itinerary", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET itinerary as %s: got %d, want 200", user, rr.Code)
+		}
+		var it groups.Itinerary
+		json.NewDecoder(rr.Body).Decode(&it)
+		if len(it.Legs) != 1 || it.Legs[0].BookingReference != want {
+			t.Errorf("%s sees legs %+v, want booking_reference %q", user, it.Legs, want)
+		}
+	}
+}
+
+func TestSaveItinerary_NonMember(t *testing.T) {
+	saved := false
+	repo := itineraryRepo(nil, "member")
+	repo.SaveItineraryFunc = func(ctx context.Context, groupID, userID string, legs []groups.ItineraryLeg) (*groups.Itinerary, error) {
+		saved = true
+		return &groups.Itinerary{}, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	body := groups.SaveItineraryRequest{Legs: []groups.ItineraryLeg{itineraryLeg("", "Secunderabad", "Warangal", legDeparture)}}
+	rr := doItinerary(t, router, "outsider", "PUT", "/groups/g1/itinerary", body)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("PUT itinerary as non-member: got %d, want 403", rr.Code)
+	}
+	if saved {
+		t.Error("itinerary should not be saved for a non-member")
+	}
+}
+
+func TestSaveItinerary_SortsLegsAndPostsToChat(t *testing.T) {
+	var savedLegs []groups.ItineraryLeg
+	repo := itineraryRepo([]groups.ItineraryLeg{itineraryLeg("leg-1", "Secunderabad", "Warangal", legDeparture)}, "member")
+	repo.SaveItineraryFunc = func(ctx context.Context, groupID, userID string, legs []groups.ItineraryLeg) (*groups.Itinerary, error) {
+		savedLegs = legs
+		return &groups.Itinerary{GroupID: groupID, Legs: legs, UpdatedBy: userID}, nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	body := groups.SaveItineraryRequest{Legs: []groups.ItineraryLeg{
+		itineraryLeg("", "Warangal", "Kazipet", legDeparture.Add(5*time.Hour)),
+		itineraryLeg("leg-1", "Secunderabad", "Warangal", legDeparture),
+		itineraryLeg("made-up-id", "Kazipet", "Hanamkonda", legDeparture.Add(9*time.Hour)),
+	}}
+	rr := doItinerary(t, router, "member", "PUT", "/groups/g1/itinerary", body)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT itinerary: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+
+	if len(savedLegs) != 3 || savedLegs[0].DepartureStation != "Secunderabad" || savedLegs[1].DepartureStation != "Warangal" {
+		t.Fatalf("saved legs = %+v, want them in departure order", savedLegs)
+	}
+	if savedLegs[0].ID != "leg-1" {
+		t.Errorf("existing leg id = %q, want leg-1 kept", savedLegs[0].ID)
+	}
+	if savedLegs[1].ID == "" || savedLegs[2].ID == "made-up-id" {
+		t.Errorf("new legs got ids %q and %q, want server-assigned ids", savedLegs[1].ID, savedLegs[2].ID)
+	}
+
+	if len(*posted) != 1 || (*posted)[0].kind != messages.KindItineraryUpdated || (*posted)[0].metadata["action"] != groups.ItinerarySaved {
+		t.Fatalf("system messages = %+v, want one itinerary_updated", *posted)
+	}
+	for k, v := range (*posted)[0].metadata {
+		if strings.Contains(v, "PNR") {
+			t.Errorf("system message metadata %s leaks the booking reference", k)
+		}
+	}
+}
+
+func TestSaveItinerary_InvalidLeg(t *testing.T) {
+	router, _ := newSystemMessageRouter(t, itineraryRepo(nil, "member"))
+
+	backwards := itineraryLeg("", "Secunderabad", "Warangal", legDeparture)
+	backwards.ArrivalTime = legDeparture.Add(-time.Hour)
+	noStation := itineraryLeg("", "", "Warangal", legDeparture)
+	badMode := itineraryLeg("", "Secunderabad", "Warangal", legDeparture)
+	badMode.Mode = "hovercraft"
+
+	for name, leg := range map[string]groups.ItineraryLeg{"arrives before departing": backwards, "no station": noStation, "bad mode": badMode} {
+		rr := doItinerary(t, router, "member", "PUT", "/groups/g1/itinerary", groups.SaveItineraryRequest{Legs: []groups.ItineraryLeg{leg}})
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", name, rr.Code)
+		}
+	}
+}
+
+func TestItineraryLegs_Add(t *testing.T) {
+	var added groups.ItineraryLeg
+	repo := itineraryRepo(nil, "member")
+	repo.AddItineraryLegFunc = func(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error) {
+		added = leg
+		return &groups.Itinerary{GroupID: groupID, Legs: []groups.ItineraryLeg{leg}}, nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	rr := doItinerary(t, router, "member", "POST", "/groups/g1/itinerary/legs", itineraryLeg("made-up-id", "Secunderabad", "Warangal", legDeparture))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST leg: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if added.ID == "" || added.ID == "made-up-id" {
+		t.Errorf("added leg id = %q, want a server-assigned id", added.ID)
+	}
+	if len(*posted) != 1 || (*posted)[0].metadata["action"] != groups.ItineraryLegAdded || (*posted)[0].metadata["legs"] != "1" {
+		t.Errorf("system messages = %+v, want leg_added with 1 leg", *posted)
+	}
+
+	repo.AddItineraryLegFunc = func(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error) {
+		return nil, groups.ErrTooManyLegs
+	}
+	rr = doItinerary(t, router, "member", "POST", "/groups/g1/itinerary/legs", itineraryLeg("", "Warangal", "Kazipet", legDeparture))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("POST leg to a full itinerary: got %d, want 400", rr.Code)
+	}
+
+	backwards := itineraryLeg("", "Secunderabad", "Warangal", legDeparture)
+	backwards.ArrivalTime = legDeparture.Add(-time.Hour)
+	rr = doItinerary(t, router, "member", "POST", "/groups/g1/itinerary/legs", backwards)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("POST invalid leg: got %d, want 400", rr.Code)
+	}
+}
+
+func TestItineraryLegs_UpdateAndDelete(t *testing.T) {
+	legs := []groups.ItineraryLeg{
+		itineraryLeg("leg-1", "Secunderabad", "Warangal", legDeparture),
+		itineraryLeg("leg-2", "Warangal", "Kazipet", legDeparture.Add(5*time.Hour)),
+	}
+	find := func(id string) int {
+		for i, l := range legs {
+			if l.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	repo := itineraryRepo(nil, "member")
+	repo.UpdateItineraryLegFunc = func(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error) {
+		i := find(leg.ID)
+		if i < 0 {
+			return nil, sql.ErrNoRows
+		}
+		legs[i] = leg
+		return &groups.Itinerary{GroupID: groupID, Legs: legs}, nil
+	}
+	repo.DeleteItineraryLegFunc = func(ctx context.Context, groupID, userID, legID string) (*groups.Itinerary, error) {
+		i := find(legID)
+		if i < 0 {
+			return nil, sql.ErrNoRows
+		}
+		legs = append(legs[:i], legs[i+1:]...)
+		return &groups.Itinerary{GroupID: groupID, Legs: legs}, nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	update := itineraryLeg("ignored", "Secunderabad", "Hanamkonda", legDeparture)
+	rr := doItinerary(t, router, "member", "PUT", "/groups/g1/itinerary/legs/leg-1", update)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT leg: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if len(legs) != 2 || legs[0].ID != "leg-1" || legs[0].ArrivalStation != "Hanamkonda" {
+		t.Errorf("after update legs = %+v, want leg-1 arriving at Hanamkonda", legs)
+	}
+
+	rr = doItinerary(t, router, "member", "DELETE", "/groups/g1/itinerary/legs/leg-2", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("DELETE leg: got %d, want 200", rr.Code)
+	}
+	if len(legs) != 1 || legs[0].ID != "leg-1" {
+		t.Errorf("after delete legs = %+v, want only leg-1", legs)
+	}
+
+	rr = doItinerary(t, router, "member", "DELETE", "/groups/g1/itinerary/legs/leg-9", nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("DELETE unknown leg: got %d, want 404", rr.Code)
+	}
+	rr = doItinerary(t, router, "member", "PUT", "/groups/g1/itinerary/legs/leg-9", update)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("PUT unknown leg: got %d, want 404", rr.Code)
+	}
+
+	if len(*posted) != 2 || (*posted)[0].metadata["action"] != groups.ItineraryLegUpdated || (*posted)[1].metadata["action"] != groups.ItineraryLegRemoved {
+		t.Errorf("system messages = %+v, want leg_updated then leg_removed", *posted)
+	}
+}
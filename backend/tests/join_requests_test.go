@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+)
+
+func TestJoinGroup_RequestMessage(t *testing.T) {
+	var stored []string
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner})
+	repo.JoinGroupCheckedFunc = func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
+		stored = append(stored, message)
+		return true, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "alice", "POST", "/groups/g1/join", map[string]string{"message": "  Also from NITW, leaving Friday  "}); rr.Code != http.StatusAccepted {
+		t.Fatalf("join with a note: got %d, want 202. Body: %s", rr.Code, rr.Body.String())
+	}
+	if rr := doItinerary(t, router, "alice", "POST", "/groups/g1/join", nil); rr.Code != http.StatusAccepted {
+		t.Errorf("join without a body: got %d, want 202", rr.Code)
+	}
+	long := map[string]string{"message": strings.Repeat("a", groups.MaxJoinRequestMessage+1)}
+	if rr := doItinerary(t, router, "alice", "POST", "/groups/g1/join", long); rr.Code != http.StatusBadRequest {
+		t.Errorf("join with an overlong note: got %d, want 400", rr.Code)
+	}
+
+	if len(stored) != 2 || stored[0] != "Also from NITW, leaving Friday" || stored[1] != "" {
+		t.Errorf("stored messages = %q", stored)
+	}
+}
+
+func TestGetJoinRequests_MatchScore(t *testing.T) {
+	repo := statusRepo(groups.StatusOpen, map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember})
+	repo.GetJoinRequestsFunc = func(ctx context.Context, groupID string) ([]groups.JoinRequest, error) {
+		return []groups.JoinRequest{
+			{UserID: "alice", FullName: "Alice", Message: "hi"},
+			{UserID: "bob", FullName: "Bob"},
+		}, nil
+	}
+	repo.GetGroupMemberProfilesForEventFunc = func(ctx context.Context, eventID string) (map[string][]groups.MatchProfile, error) {
+		t.Error("scoring requests must not load every group in the event")
+		return nil, nil
+	}
+	repo.GetGroupMemberProfilesFunc = func(ctx context.Context, groupID string) ([]groups.MatchProfile, error) {
+		return []groups.MatchProfile{{UserID: "owner", Interests: []string{"AI", "Music"}}}, nil
+	}
+	repo.GetMatchProfileFunc = func(ctx context.Context, userID string) (groups.MatchProfile, error) {
+		t.Error("requester profiles must be loaded in one batch")
+		return groups.MatchProfile{}, nil
+	}
+	batches := 0
+	repo.GetMatchProfilesFunc = func(ctx context.Context, userIDs []string) (map[string]groups.MatchProfile, error) {
+		batches++
+		return map[string]groups.MatchProfile{
+			"alice": {UserID: "alice", Interests: []string{"AI", "Music"}},
+			"bob":   {UserID: "bob", Interests: []string{"Chess"}},
+		}, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "member", "GET", "/groups/g1/requests", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("member viewing requests: got %d, want 403", rr.Code)
+	}
+
+	rr := doItinerary(t, router, "owner", "GET", "/groups/g1/requests", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("owner viewing requests: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var requests []groups.JoinRequest
+	json.NewDecoder(rr.Body).Decode(&requests)
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	alice, bob := requests[0], requests[1]
+	if alice.Message != "hi" || len(alice.CommonInterests) != 2 || alice.MatchScore <= bob.MatchScore {
+		t.Errorf("alice = %+v, bob = %+v; want alice to share 2 interests and score higher", alice, bob)
+	}
+	if bob.CommonInterests == nil || len(bob.CommonInterests) != 0 {
+		t.Errorf("bob common interests = %v, want []", bob.CommonInterests)
+	}
+	if batches != 1 {
+		t.Errorf("loaded requester profiles in %d queries, want 1", batches)
+	}
+}
+
+func TestHandleRequest_NotPending(t *testing.T) {
+	repo := statusRepo(groups.StatusOpen, map[string]string{"owner": groups.RoleOwner})
+	repo.AcceptJoinRequestFunc = func(ctx context.Context, groupID, userID string) error { return sql.ErrNoRows }
+	repo.DeclineJoinRequestFunc = func(ctx context.Context, groupID, userID string) error { return sql.ErrNoRows }
+	router, _ := newSystemMessageRouter(t, repo)
+
+	for _, action := range []string{"accept", "decline"} {
+		if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/requests/alice/"+action, nil); rr.Code != http.StatusNotFound {
+			t.Errorf("%s without a pending request: got %d, want 404", action, rr.Code)
+		}
+	}
+}
+
+func TestAcceptRequest_GroupFilledMeanwhile(t *testing.T) {
+	repo := statusRepo(groups.StatusOpen, map[string]string{"owner": groups.RoleOwner})
+	repo.AcceptJoinRequestFunc = func(ctx context.Context, groupID, userID string) error { return groups.ErrGroupFull }
+	router, posted := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/requests/alice/accept", nil); rr.Code != http.StatusConflict {
+		t.Errorf("accepting into a full group: got %d, want 409", rr.Code)
+	}
+	if len(*posted) != 0 {
+		t.Errorf("system messages = %+v, want none", *posted)
+	}
+}
+
+func TestCancelJoinRequest(t *testing.T) {
+	pending := map[string]bool{"alice": true}
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner})
+	repo.CancelJoinRequestFunc = func(ctx context.Context, groupID, userID string) error {
+		if !pending[userID] {
+			return sql.ErrNoRows
+		}
+		delete(pending, userID)
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "alice", "DELETE", "/groups/g1/join", nil); rr.Code != http.StatusOK {
+		t.Errorf("cancelling a pending request: got %d, want 200", rr.Code)
+	}
+	if rr := doItinerary(t, router, "alice", "DELETE", "/groups/g1/join", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("cancelling twice: got %d, want 404", rr.Code)
+	}
+}
+
+func TestGetMyJoinRequests(t *testing.T) {
+	repo := rolesRepo(nil)
+	repo.GetUserJoinRequestsFunc = func(ctx context.Context, userID string) ([]groups.OutgoingJoinRequest, error) {
+		if userID != "alice" {
+			return nil, nil
+		}
+		return []groups.OutgoingJoinRequest{
+			{GroupID: "g1", GroupName: "Team Alpha", UserID: userID, Status: groups.RequestPending},
+			{GroupID: "g2", GroupName: "Team Beta", UserID: userID, Status: groups.RequestDeclined},
+		}, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	for user, want := range map[string]int{"alice": 2, "bob": 0} {
+		rr := doItinerary(t, router, user, "GET", "/me/group-requests", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: got %d, want 200", user, rr.Code)
+		}
+		var requests []groups.OutgoingJoinRequest
+		if err := json.NewDecoder(rr.Body).Decode(&requests); err != nil || requests == nil || len(requests) != want {
+			t.Errorf("%s: requests = %+v (%v), want %d", user, requests, err, want)
+		}
+	}
+}
+
+func TestJoinRequestExpirer(t *testing.T) {
+	calls := 0
+	var cutoff time.Time
+	repo := &MockGroupsRepositoryFull{
+		ExpireJoinRequestsFunc: func(ctx context.Context, now time.Time, limit int) ([]groups.OutgoingJoinRequest, error) {
+			calls++
+			cutoff = now
+			return []groups.OutgoingJoinRequest{
+				{GroupID: "g1", GroupName: "Team Alpha", UserID: "alice", Status: groups.RequestExpired},
+				{GroupID: "g2", GroupName: "Team Beta", UserID: "bob", Status: groups.RequestExpired},
+			}, nil
+		},
+	}
+
+	before := time.Now()
+	if n := groups.NewJoinRequestExpirer(repo, nil).ExpireRequests(context.Background()); n != 2 {
+		t.Errorf("expired %d requests, want 2", n)
+	}
+	if calls != 1 || cutoff.Before(before) {
+		t.Errorf("calls = %d, cutoff = %v; want one batch cut off at the current time", calls, cutoff)
+	}
+}
@@ -0,0 +1,230 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// statusRepo is a groups repo whose group is in the given lifecycle state.
+func statusRepo(status string, roles map[string]string) *MockGroupsRepositoryFull {
+	repo := rolesRepo(roles)
+	repo.GetGroupFunc = func(ctx context.Context, groupID string) (*groups.Group, error) {
+		return &groups.Group{ID: groupID, Name: "Team Alpha", MaxMembers: 4, Status: status}, nil
+	}
+	return repo
+}
+
+func TestLockAndUnlockGroup(t *testing.T) {
+	var transitions []string
+	promoted := false
+	repo := statusRepo(groups.StatusOpen, map[string]string{"owner": groups.RoleOwner, "admin": groups.RoleAdmin})
+	repo.SetGroupStatusFunc = func(ctx context.Context, groupID, from, to string) error {
+		transitions = append(transitions, from+"->"+to)
+		return nil
+	}
+	repo.PromoteFromWaitlistFunc = func(ctx context.Context, groupID string) ([]groups.Promotion, error) {
+		promoted = true
+		return nil, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "admin", "POST", "/groups/g1/lock", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("admin locking: got %d, want 403", rr.Code)
+	}
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/lock", nil); rr.Code != http.StatusOK || promoted {
+		t.Errorf("owner locking: got %d (promoted=%v), want 200 without promotion", rr.Code, promoted)
+	}
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/unlock", nil); rr.Code != http.StatusOK || !promoted {
+		t.Errorf("owner unlocking: got %d (promoted=%v), want 200 with the waitlist promoted", rr.Code, promoted)
+	}
+	if len(transitions) != 2 || transitions[0] != "open->locked" || transitions[1] != "locked->open" {
+		t.Errorf("transitions = %v", transitions)
+	}
+}
+
+func TestLockGroup_WrongState(t *testing.T) {
+	repo := statusRepo(groups.StatusTraveling, map[string]string{"owner": groups.RoleOwner})
+	repo.SetGroupStatusFunc = func(ctx context.Context, groupID, from, to string) error {
+		return sql.ErrNoRows
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/lock", nil); rr.Code != http.StatusConflict {
+		t.Errorf("locking a traveling group: got %d, want 409", rr.Code)
+	}
+}
+
+func TestJoinGroup_ClosedGroupNotWaitlisted(t *testing.T) {
+	queued := false
+	repo := statusRepo(groups.StatusLocked, nil)
+	repo.JoinGroupCheckedFunc = func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
+		return false, groups.ErrGroupClosed
+	}
+	repo.JoinWaitlistFunc = func(ctx context.Context, groupID, userID string) (int, error) {
+		queued = true
+		return 1, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "newbie", "POST", "/groups/g1/join", nil); rr.Code != http.StatusConflict || queued {
+		t.Errorf("joining a locked group: got %d (queued=%v), want 409", rr.Code, queued)
+	}
+}
+
+func TestClosedGroup_ManagementRestrictions(t *testing.T) {
+	accepted := false
+	repo := statusRepo(groups.StatusLocked, map[string]string{"owner": groups.RoleOwner})
+	repo.AcceptJoinRequestFunc = func(ctx context.Context, groupID, userID string) error {
+		accepted = true
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/requests/newbie/accept", nil); rr.Code != http.StatusConflict || accepted {
+		t.Errorf("accepting a request into a locked group: got %d (accepted=%v), want 409", rr.Code, accepted)
+	}
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/invites", map[string]interface{}{}); rr.Code != http.StatusConflict {
+		t.Errorf("inviting to a locked group: got %d, want 409", rr.Code)
+	}
+
+	router, _ = newSystemMessageRouter(t, statusRepo(groups.StatusArchived, map[string]string{"owner": groups.RoleOwner}))
+	if rr := doItinerary(t, router, "owner", "PUT", "/groups/g1", map[string]string{"name": "Renamed"}); rr.Code != http.StatusConflict {
+		t.Errorf("updating an archived group: got %d, want 409", rr.Code)
+	}
+}
+
+func TestArchiver_Advance(t *testing.T) {
+	var gotAfter time.Duration
+	repo := &MockGroupsRepositoryFull{
+		AdvanceGroupLifecycleFunc: func(ctx context.Context, now time.Time, archiveAfter time.Duration) ([]groups.StatusChange, error) {
+			gotAfter = archiveAfter
+			return []groups.StatusChange{{GroupID: "g1", Status: groups.StatusCompleted}, {GroupID: "g2", Status: groups.StatusArchived}}, nil
+		},
+	}
+
+	changes := groups.NewArchiver(repo).Advance(context.Background())
+	if len(changes) != 2 || gotAfter != groups.ArchiveAfter {
+		t.Errorf("changes = %+v, archiveAfter = %v", changes, gotAfter)
+	}
+}
+
+func TestWeightedScorer_LockedGroupIneligible(t *testing.T) {
+	group := groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4, Status: groups.StatusLocked}, MemberCount: 1}
+	m := groups.NewWeightedScorer(groups.DefaultWeights).Score(groups.MatchProfile{UserID: "me"}, group, nil)
+	if m.Eligible || m.Reason != "group is locked" {
+		t.Errorf("locked group: eligible = %v, reason = %q", m.Eligible, m.Reason)
+	}
+}
+
+func TestDeliverMessage_ReadOnlyThread(t *testing.T) {
+	created := false
+	mockRepo := &MockMessagesRepository{
+		IsParticipantFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return true, nil
+		},
+		GetThreadFunc: func(ctx context.Context, threadID string) (messages.Thread, error) {
+			return messages.Thread{ID: threadID, Type: "group", ReadOnly: true}, nil
+		},
+		CreateMessageFunc: func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error) {
+			created = true
+			return messages.Message{}, nil
+		},
+	}
+
+	_, err := messages.NewHub(mockRepo, nil, nil).DeliverMessage(context.Background(), "user-1", messages.SendMessageRequest{ThreadID: "thread-1", Content: "anyone still here?"})
+	if err != messages.ErrThreadReadOnly || created {
+		t.Errorf("sending to an archived chat: err = %v, created = %v; want ErrThreadReadOnly", err, created)
+	}
+}
+
+func TestReadOnlyThread_RejectsPinsAndScheduledSends(t *testing.T) {
+	changed := false
+	mockRepo := &MockMessagesRepository{
+		GetThreadFunc: func(ctx context.Context, threadID string) (messages.Thread, error) {
+			return messages.Thread{ID: threadID, Type: "group", ReadOnly: true}, nil
+		},
+		PinMessageFunc: func(ctx context.Context, threadID, messageID, userID string) error {
+			changed = true
+			return nil
+		},
+		UnpinMessageFunc: func(ctx context.Context, threadID, messageID string) error {
+			changed = true
+			return nil
+		},
+		CreateScheduledMessageFunc: func(ctx context.Context, sm messages.ScheduledMessage) (messages.ScheduledMessage, error) {
+			changed = true
+			return sm, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+
+	tests := []struct {
+		name, method, path string
+		body               interface{}
+	}{
+		{"pin", "POST", "/messages/thread-1/pins", map[string]string{"message_id": "msg-1"}},
+		{"unpin", "DELETE", "/messages/thread-1/pins/msg-1", nil},
+		{"schedule", "POST", "/messages/schedule", map[string]interface{}{"thread_id": "thread-1", "content": "see you", "send_at": time.Now().Add(time.Hour)}},
+	}
+	for _, tt := range tests {
+		if rr := doItinerary(t, router, "user-1", tt.method, tt.path, tt.body); rr.Code != http.StatusForbidden {
+			t.Errorf("%s in an archived chat: got %d, want 403", tt.name, rr.Code)
+		}
+	}
+	if changed {
+		t.Error("a read-only chat must not be changed")
+	}
+}
+
+func TestReadOnlyThread_RejectsDeletesAndThreadSettings(t *testing.T) {
+	changed := false
+	mockRepo := &MockMessagesRepository{
+		GetThreadFunc: func(ctx context.Context, threadID string) (messages.Thread, error) {
+			return messages.Thread{ID: threadID, Type: "group", ReadOnly: true}, nil
+		},
+		GetMessageFunc: func(ctx context.Context, messageID, userID string) (messages.Message, error) {
+			return messages.Message{ID: messageID, ThreadID: "thread-1", SenderID: userID}, nil
+		},
+		DeleteMessageFunc: func(ctx context.Context, messageID, userID string) (string, error) {
+			changed = true
+			return "thread-1", nil
+		},
+		SetThreadNoForwardFunc: func(ctx context.Context, threadID string, noForward bool) error {
+			changed = true
+			return nil
+		},
+		SetThreadMessageTTLFunc: func(ctx context.Context, threadID string, ttlSeconds int) error {
+			changed = true
+			return nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+
+	tests := []struct {
+		name, method, path string
+		body               interface{}
+	}{
+		{"delete", "DELETE", "/messages/msg-1", nil},
+		{"no_forward", "PUT", "/messages/thread-1/settings", map[string]bool{"no_forward": true}},
+		{"message_ttl", "PUT", "/messages/thread-1/settings", map[string]string{"message_ttl": "24h"}},
+	}
+	for _, tt := range tests {
+		if rr := doItinerary(t, router, "user-1", tt.method, tt.path, tt.body); rr.Code != http.StatusForbidden {
+			t.Errorf("%s in an archived chat: got %d, want 403", tt.name, rr.Code)
+		}
+	}
+	if changed {
+		t.Error("a read-only chat must not be changed")
+	}
+
+	// Muting only affects the caller, so it is still allowed
+	if rr := doItinerary(t, router, "user-1", "PUT", "/messages/thread-1/settings", map[string]bool{"muted": true}); rr.Code != http.StatusOK {
+		t.Errorf("muting an archived chat: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+var mentionParticipants = []messages.Participant{
+	{UserID: "u-riya", Name: "Riya Sharma"},
+	{UserID: "u-riyan", Name: "Riyan Das"},
+	{UserID: "u-arjun", Name: "Arjun Mehta"},
+	{UserID: "u-arjun2", Name: "Arjun Rao"},
+	{UserID: "u-no-name", Name: ""},
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []messages.Mention
+	}{
+		{"full name", "hey @Riya Sharma, see you", []messages.Mention{{UserID: "u-riya", Offset: 4, Length: 12}}},
+		{"case insensitive", "@riya sharma ok", []messages.Mention{{UserID: "u-riya", Offset: 0, Length: 12}}},
+		{"unique first name", "@Riyan you there?", []messages.Mention{{UserID: "u-riyan", Offset: 0, Length: 6}}},
+		{"first name is not a prefix of a longer one", "@Riya is late", []messages.Mention{{UserID: "u-riya", Offset: 0, Length: 5}}},
+		{"ambiguous first name", "@Arjun call me", nil},
+		{"full name resolves ambiguity", "@Arjun Rao call me", []messages.Mention{{UserID: "u-arjun2", Offset: 0, Length: 10}}},
+		{"email is not a mention", "mail riya@Riya Sharma.com", nil},
+		{"unknown name", "@Nobody here", nil},
+		{"utf-16 offsets", "🚆 @Riya Sharma", []messages.Mention{{UserID: "u-riya", Offset: 3, Length: 12}}},
+		{"multiple", "@Riyan @Arjun Mehta", []messages.Mention{
+			{UserID: "u-riyan", Offset: 0, Length: 6},
+			{UserID: "u-arjun", Offset: 7, Length: 12},
+		}},
+	}
+	for _, tt := range tests {
+		got := messages.ParseMentions(tt.content, mentionParticipants)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: mention %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestSendMessage_SavesMentionsInGroupThread(t *testing.T) {
+	token, _ := auth.GenerateToken("u-riya", "student@nitw.ac.in")
+	var saved []messages.Mention
+	mockRepo := &MockMessagesRepository{
+		GetParticipantsFunc: func(ctx context.Context, threadID string) ([]messages.Participant, error) {
+			return mentionParticipants, nil
+		},
+		SaveMentionsFunc: func(ctx context.Context, messageID string, mentions []messages.Mention) error {
+			saved = mentions
+			return nil
+		},
+		CreateMessageFunc: func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error) {
+			return messages.Message{ID: "msg-1", ThreadID: threadID, SenderID: senderID, Content: content}, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]string{"thread_id": "thread-1", "content": "@Riya Sharma @Riyan ping"})
+	req, _ := http.NewRequest("POST", "/messages/send", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST /messages/send: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	// The sender cannot mention themselves
+	if len(saved) != 1 || saved[0].UserID != "u-riyan" {
+		t.Fatalf("expected only u-riyan to be mentioned, got %+v", saved)
+	}
+	var msg messages.Message
+	json.NewDecoder(rr.Body).Decode(&msg)
+	if len(msg.Mentions) != 1 || msg.Mentions[0].Offset != 13 {
+		t.Errorf("response should carry mention offsets, got %+v", msg.Mentions)
+	}
+}
+
+func TestSendMessage_NoMentionsInDirectThread(t *testing.T) {
+	token, _ := auth.GenerateToken("u-riya", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		GetThreadFunc: func(ctx context.Context, threadID string) (messages.Thread, error) {
+			return messages.Thread{ID: threadID, Type: "direct"}, nil
+		},
+		GetParticipantsFunc: func(ctx context.Context, threadID string) ([]messages.Participant, error) {
+			return mentionParticipants, nil
+		},
+		SaveMentionsFunc: func(ctx context.Context, messageID string, mentions []messages.Mention) error {
+			t.Error("mentions must not be saved for direct threads")
+			return nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]string{"thread_id": "thread-1", "content": "@Riyan hi"})
+	req, _ := http.NewRequest("POST", "/messages/send", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Errorf("POST /messages/send: got %d, want 201", rr.Code)
+	}
+}
+
+func TestListMentions_RequiresAuth(t *testing.T) {
+	router := newMsgRouter(t, &MockMessagesRepository{})
+	req, _ := http.NewRequest("GET", "/me/mentions", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("GET /me/mentions without auth: got %d, want 401", rr.Code)
+	}
+}
+
+func TestListMentions_Success(t *testing.T) {
+	token, _ := auth.GenerateToken("u-riyan", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		ListMentionsFunc: func(ctx context.Context, userID string, after *messages.Cursor, limit int) ([]messages.MentionedMessage, error) {
+			if userID != "u-riyan" || after != nil || limit != 21 {
+				t.Errorf("unexpected args: user=%s after=%v limit=%d", userID, after, limit)
+			}
+			return []messages.MentionedMessage{{Message: messages.Message{ID: "msg-1"}, ThreadName: "Goa Trip"}}, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("GET", "/me/mentions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /me/mentions: got %d, want 200", rr.Code)
+	}
+	var resp messages.MentionsResponse
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if len(resp.Messages) != 1 || resp.HasMore || resp.NextCursor != "" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
@@ -20,11 +20,11 @@ import (
 func newMsgRouter(t *testing.T, msgRepo messages.Repository) http.Handler {
 	t.Helper()
 	t.Setenv("JWT_SECRET", "testsecret")
-	hub := messages.NewHub(msgRepo, nil)
+	hub := messages.NewHub(msgRepo, nil, nil)
 	return server.NewRouter(
 		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
 		&MockEventsRepository{}, &MockGroupsRepository{},
-		msgRepo, hub, &MockFileStorage{}, "./uploads",
+		msgRepo, hub, &MockFileStorage{}, "./uploads", nil, nil, nil,
 	)
 }
 
@@ -101,7 +101,7 @@ func TestGetMessages_Success(t *testing.T) {
 		IsParticipantFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
 			return true, nil
 		},
-		GetMessagesFunc: func(ctx context.Context, threadID, userID string, before time.Time, limit int) ([]messages.Message, error) {
+		GetMessagesFunc: func(ctx context.Context, threadID, userID string, q messages.MessageQuery) ([]messages.Message, error) {
 			return []messages.Message{
 				{ID: "msg-1", Content: "Hello"},
 			}, nil
@@ -440,3 +440,356 @@ func TestRegisterDeviceToken_Success(t *testing.T) {
 		t.Errorf("POST /me/device-token: got %d, want 200", rr.Code)
 	}
 }
+
+// --- SearchMessages ---
+
+func TestSearchMessages_RequiresAuth(t *testing.T) {
+	router := newMsgRouter(t, &MockMessagesRepository{})
+	req, _ := http.NewRequest("GET", "/messages/search?q=pnr", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("GET /messages/search without auth: got %d, want 401", rr.Code)
+	}
+}
+
+func TestSearchMessages_QueryTooShort(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, &MockMessagesRepository{})
+	req, _ := http.NewRequest("GET", "/messages/search?q=a", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("GET /messages/search short query: got %d, want 400", rr.Code)
+	}
+}
+
+func TestSearchMessages_InvalidCursor(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, &MockMessagesRepository{})
+	req, _ := http.NewRequest("GET", "/messages/search?q=pnr&cursor=not-a-cursor", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("GET /messages/search bad cursor: got %d, want 400", rr.Code)
+	}
+}
+
+func TestSearchMessages_Paginates(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	now := time.Now().UTC()
+	mockRepo := &MockMessagesRepository{
+		SearchMessagesFunc: func(ctx context.Context, userID, query string, after *messages.Cursor, limit int) ([]messages.SearchResult, error) {
+			if userID != "user-1" || query != "pnr" {
+				t.Errorf("unexpected search args: user=%s query=%s", userID, query)
+			}
+			// limit is page size + 1; return a full extra row so has_more is set.
+			var results []messages.SearchResult
+			for i := 0; i < limit; i++ {
+				results = append(results, messages.SearchResult{
+					MessageID: "00000000-0000-0000-0000-00000000000" + string(rune('1'+i)),
+					CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+				})
+			}
+			return results, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("GET", "/messages/search?q=pnr&limit=2", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /messages/search: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var resp messages.SearchResponse
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if len(resp.Results) != 2 || !resp.HasMore {
+		t.Fatalf("expected 2 results with has_more, got %d (has_more=%v)", len(resp.Results), resp.HasMore)
+	}
+	c, err := messages.DecodeCursor(resp.NextCursor)
+	if err != nil {
+		t.Fatalf("next_cursor did not decode: %v", err)
+	}
+	if c.ID != resp.Results[1].MessageID || !c.CreatedAt.Equal(resp.Results[1].CreatedAt) {
+		t.Errorf("next_cursor should point at the last result, got %+v", c)
+	}
+}
+
+// --- Pins ---
+
+func TestListPins_NotParticipant(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		IsParticipantFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return false, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("GET", "/messages/thread-1/pins", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("GET /messages/{id}/pins not participant: got %d, want 403", rr.Code)
+	}
+}
+
+func TestPinMessage_NotAdmin(t *testing.T) {
+	token, _ := auth.GenerateToken("user-2", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		CanManageThreadFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return false, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]string{"message_id": "msg-1"})
+	req, _ := http.NewRequest("POST", "/messages/thread-1/pins", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("POST /messages/{id}/pins as non-admin: got %d, want 403", rr.Code)
+	}
+}
+
+func TestPinMessage_LimitReached(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		PinMessageFunc: func(ctx context.Context, threadID, messageID, userID string) error {
+			return messages.ErrPinLimitReached
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]string{"message_id": "msg-6"})
+	req, _ := http.NewRequest("POST", "/messages/thread-1/pins", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("POST /messages/{id}/pins over limit: got %d, want 409", rr.Code)
+	}
+}
+
+func TestPinMessage_Success(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	var pinned string
+	mockRepo := &MockMessagesRepository{
+		PinMessageFunc: func(ctx context.Context, threadID, messageID, userID string) error {
+			pinned = messageID
+			return nil
+		},
+		GetPinnedMessagesFunc: func(ctx context.Context, threadID string) ([]messages.PinnedMessage, error) {
+			return []messages.PinnedMessage{{Message: messages.Message{ID: pinned, ThreadID: threadID}, PinnedBy: "user-1"}}, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]string{"message_id": "msg-1"})
+	req, _ := http.NewRequest("POST", "/messages/thread-1/pins", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /messages/{id}/pins: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var pins []messages.PinnedMessage
+	json.NewDecoder(rr.Body).Decode(&pins)
+	if len(pins) != 1 || pins[0].ID != "msg-1" {
+		t.Errorf("expected msg-1 to be pinned, got %+v", pins)
+	}
+}
+
+func TestUnpinMessage_NotPinned(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		UnpinMessageFunc: func(ctx context.Context, threadID, messageID string) error {
+			return sql.ErrNoRows
+		},
+		DeleteMessageFunc: func(ctx context.Context, messageID, userID string) (string, error) {
+			t.Error("unpin must not be routed to DeleteMessage")
+			return "", nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("DELETE", "/messages/thread-1/pins/msg-1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("DELETE /messages/{id}/pins/{msg} not pinned: got %d, want 404", rr.Code)
+	}
+}
+
+// --- Thread settings ---
+
+func TestUpdateThreadSettings_NotParticipant(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		GetThreadSettingsFunc: func(ctx context.Context, threadID, userID string) (messages.ThreadSettings, error) {
+			return messages.ThreadSettings{}, sql.ErrNoRows
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]interface{}{"muted": true})
+	req, _ := http.NewRequest("PUT", "/messages/thread-1/settings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("PUT /messages/{id}/settings not participant: got %d, want 403", rr.Code)
+	}
+}
+
+func TestUpdateThreadSettings_MentionsInDirectThread(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		GetThreadFunc: func(ctx context.Context, threadID string) (messages.Thread, error) {
+			return messages.Thread{ID: threadID, Type: "direct"}, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]interface{}{"notification_level": "mentions"})
+	req, _ := http.NewRequest("PUT", "/messages/thread-1/settings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("mentions-only on a direct thread: got %d, want 400", rr.Code)
+	}
+}
+
+func TestUpdateThreadSettings_MuteHoursWithoutMuted(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, &MockMessagesRepository{})
+	body, _ := json.Marshal(map[string]interface{}{"mute_hours": 8})
+	req, _ := http.NewRequest("PUT", "/messages/thread-1/settings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("mute_hours without muted: got %d, want 400", rr.Code)
+	}
+}
+
+func TestUpdateThreadSettings_TimedMute(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	var saved messages.ThreadSettings
+	mockRepo := &MockMessagesRepository{
+		UpdateThreadSettingsFunc: func(ctx context.Context, threadID, userID string, settings messages.ThreadSettings) error {
+			saved = settings
+			return nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]interface{}{"muted": true, "mute_hours": 8})
+	req, _ := http.NewRequest("PUT", "/messages/thread-1/settings", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT /messages/{id}/settings: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if !saved.IsMuted || saved.MutedUntil == nil {
+		t.Fatalf("expected a timed mute to be saved, got %+v", saved)
+	}
+	if d := time.Until(*saved.MutedUntil); d < 7*time.Hour || d > 8*time.Hour {
+		t.Errorf("muted_until should be ~8h from now, got %v", d)
+	}
+	if saved.NotificationLevel != messages.NotifyAll {
+		t.Errorf("notification_level should be unchanged, got %q", saved.NotificationLevel)
+	}
+}
+
+func TestThreadSettings_AllowsPush(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		settings  messages.ThreadSettings
+		mentioned bool
+		want      bool
+	}{
+		{"default", messages.ThreadSettings{NotificationLevel: messages.NotifyAll}, false, true},
+		{"muted indefinitely", messages.ThreadSettings{IsMuted: true, NotificationLevel: messages.NotifyAll}, false, false},
+		{"timed mute active", messages.ThreadSettings{IsMuted: true, MutedUntil: &future, NotificationLevel: messages.NotifyAll}, false, false},
+		{"timed mute lapsed", messages.ThreadSettings{IsMuted: true, MutedUntil: &past, NotificationLevel: messages.NotifyAll}, false, true},
+		{"mentions only", messages.ThreadSettings{NotificationLevel: messages.NotifyMentions}, false, false},
+		{"mention breaks through mute", messages.ThreadSettings{IsMuted: true, NotificationLevel: messages.NotifyMentions}, true, true},
+	}
+	for _, tt := range tests {
+		if got := tt.settings.AllowsPush(now, tt.mentioned); got != tt.want {
+			t.Errorf("%s: AllowsPush = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// --- Replies ---
+
+func TestGetReplies_NotParticipant(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		IsParticipantFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return false, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("GET", "/messages/thread-1/msg-1/replies", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("GET replies not participant: got %d, want 403", rr.Code)
+	}
+}
+
+func TestGetReplies_NotFound(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		GetReplyTreeFunc: func(ctx context.Context, threadID, messageID, userID string) ([]messages.Message, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("GET", "/messages/thread-1/msg-404/replies", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("GET replies for missing message: got %d, want 404", rr.Code)
+	}
+}
+
+func TestGetReplies_Success(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	root, child := "msg-1", "msg-2"
+	mockRepo := &MockMessagesRepository{
+		GetReplyTreeFunc: func(ctx context.Context, threadID, messageID, userID string) ([]messages.Message, error) {
+			if threadID != "thread-1" || messageID != root {
+				t.Errorf("unexpected args: thread=%s message=%s", threadID, messageID)
+			}
+			return []messages.Message{
+				{ID: root, ReplyCount: 1},
+				{ID: child, ReplyToID: &root, ReplyCount: 1},
+				{ID: "msg-3", ReplyToID: &child},
+			}, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("GET", "/messages/thread-1/msg-1/replies", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET replies: got %d, want 200", rr.Code)
+	}
+	var tree messages.ReplyTree
+	json.NewDecoder(rr.Body).Decode(&tree)
+	if tree.Root.ID != root || len(tree.Replies) != 2 {
+		t.Errorf("expected root msg-1 with 2 replies, got %+v", tree)
+	}
+}
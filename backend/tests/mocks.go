@@ -2,6 +2,7 @@ package tests
 
 import (
 	"context"
+	"database/sql"
 	"io"
 	"time"
 
@@ -160,6 +161,9 @@ func (m *MockFileStorage) Delete(filename string) error {
 type MockAdminRepository struct {
 	ListUsersByStatusFunc func(ctx context.Context, status string) ([]admin.UserRow, error)
 	UpdateUserStatusFunc  func(ctx context.Context, userID string, status string) error
+	CreateReportFunc      func(ctx context.Context, reporterID string, req admin.CreateReportRequest) (admin.Report, error)
+	ListReportsFunc       func(ctx context.Context, status string) ([]admin.Report, error)
+	ResolveReportFunc     func(ctx context.Context, reportID string, req admin.ResolveReportRequest) (admin.Report, error)
 }
 
 func (m *MockAdminRepository) ListUsersByStatus(ctx context.Context, status string) ([]admin.UserRow, error) {
@@ -176,6 +180,27 @@ func (m *MockAdminRepository) UpdateUserStatus(ctx context.Context, userID strin
 	return nil
 }
 
+func (m *MockAdminRepository) CreateReport(ctx context.Context, reporterID string, req admin.CreateReportRequest) (admin.Report, error) {
+	if m.CreateReportFunc != nil {
+		return m.CreateReportFunc(ctx, reporterID, req)
+	}
+	return admin.Report{ID: "mock-report-id", ReporterID: &reporterID, TargetType: req.TargetType, TargetID: req.TargetID, Category: req.Category, Status: admin.ReportOpen}, nil
+}
+
+func (m *MockAdminRepository) ListReports(ctx context.Context, status string) ([]admin.Report, error) {
+	if m.ListReportsFunc != nil {
+		return m.ListReportsFunc(ctx, status)
+	}
+	return []admin.Report{}, nil
+}
+
+func (m *MockAdminRepository) ResolveReport(ctx context.Context, reportID string, req admin.ResolveReportRequest) (admin.Report, error) {
+	if m.ResolveReportFunc != nil {
+		return m.ResolveReportFunc(ctx, reportID, req)
+	}
+	return admin.Report{ID: reportID, Status: admin.ReportResolved}, nil
+}
+
 // MockEventsRepository implements events.Repository
 type MockEventsRepository struct{}
 
@@ -211,7 +236,7 @@ func (m *MockGroupsRepository) CreateGroup(ctx context.Context, group *groups.Gr
 	return nil
 }
 func (m *MockGroupsRepository) GetGroup(ctx context.Context, groupID string) (*groups.Group, error) {
-	return &groups.Group{}, nil
+	return &groups.Group{Status: groups.StatusOpen}, nil
 }
 func (m *MockGroupsRepository) GetGroupThreadID(ctx context.Context, groupID string) (string, error) {
 	return "mock-thread-id", nil
@@ -219,7 +244,7 @@ func (m *MockGroupsRepository) GetGroupThreadID(ctx context.Context, groupID str
 func (m *MockGroupsRepository) JoinGroup(ctx context.Context, groupID, userID string) error {
 	return nil
 }
-func (m *MockGroupsRepository) JoinGroupChecked(ctx context.Context, groupID, userID string) (bool, error) {
+func (m *MockGroupsRepository) JoinGroupChecked(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
 	return false, nil
 }
 func (m *MockGroupsRepository) GetMemberCount(ctx context.Context, groupID string) (int, error) {
@@ -246,12 +271,34 @@ func (m *MockGroupsRepository) GetUserInterests(ctx context.Context, userID stri
 func (m *MockGroupsRepository) GetGroupMembers(ctx context.Context, groupID string) ([]groups.GroupMemberProfile, error) {
 	return []groups.GroupMemberProfile{}, nil
 }
-func (m *MockGroupsRepository) UpdateGroup(ctx context.Context, groupID, name, description, meetingPoint string, departureDate *time.Time) error {
+func (m *MockGroupsRepository) GetMatchProfile(ctx context.Context, userID string) (groups.MatchProfile, error) {
+	return groups.MatchProfile{UserID: userID}, nil
+}
+func (m *MockGroupsRepository) GetMatchProfiles(ctx context.Context, userIDs []string) (map[string]groups.MatchProfile, error) {
+	profiles := make(map[string]groups.MatchProfile)
+	for _, id := range userIDs {
+		profiles[id] = groups.MatchProfile{UserID: id}
+	}
+	return profiles, nil
+}
+func (m *MockGroupsRepository) GetGroupMemberProfilesForEvent(ctx context.Context, eventID string) (map[string][]groups.MatchProfile, error) {
+	return map[string][]groups.MatchProfile{}, nil
+}
+func (m *MockGroupsRepository) GetGroupMemberProfiles(ctx context.Context, groupID string) ([]groups.MatchProfile, error) {
+	return []groups.MatchProfile{}, nil
+}
+func (m *MockGroupsRepository) UpdateGroup(ctx context.Context, group *groups.Group) error {
 	return nil
 }
 func (m *MockGroupsRepository) DeleteGroup(ctx context.Context, groupID string) error {
 	return nil
 }
+func (m *MockGroupsRepository) SetGroupStatus(ctx context.Context, groupID, from, to string) error {
+	return nil
+}
+func (m *MockGroupsRepository) AdvanceGroupLifecycle(ctx context.Context, now time.Time, archiveAfter time.Duration) ([]groups.StatusChange, error) {
+	return nil, nil
+}
 func (m *MockGroupsRepository) RemoveMember(ctx context.Context, groupID, userID string) error {
 	return nil
 }
@@ -261,15 +308,15 @@ func (m *MockGroupsRepository) IsGroupMember(ctx context.Context, groupID, userI
 func (m *MockGroupsRepository) GetUserGroups(ctx context.Context, userID string) ([]groups.GroupWithDetails, error) {
 	return []groups.GroupWithDetails{}, nil
 }
-func (m *MockGroupsRepository) GetAllGroups(ctx context.Context, userID string) ([]groups.GroupWithDetails, error) {
+func (m *MockGroupsRepository) SearchGroups(ctx context.Context, userID string, search groups.GroupSearch) ([]groups.GroupWithDetails, error) {
 	return []groups.GroupWithDetails{}, nil
 }
 
 func (m *MockGroupsRepository) CreateJoinRequest(ctx context.Context, groupID, userID string) error {
 	return nil
 }
-func (m *MockGroupsRepository) GetJoinRequests(ctx context.Context, groupID string) ([]groups.GroupMemberProfile, error) {
-	return []groups.GroupMemberProfile{}, nil
+func (m *MockGroupsRepository) GetJoinRequests(ctx context.Context, groupID string) ([]groups.JoinRequest, error) {
+	return []groups.JoinRequest{}, nil
 }
 func (m *MockGroupsRepository) AcceptJoinRequest(ctx context.Context, groupID, userID string) error {
 	return nil
@@ -277,25 +324,151 @@ func (m *MockGroupsRepository) AcceptJoinRequest(ctx context.Context, groupID, u
 func (m *MockGroupsRepository) DeclineJoinRequest(ctx context.Context, groupID, userID string) error {
 	return nil
 }
+func (m *MockGroupsRepository) CancelJoinRequest(ctx context.Context, groupID, userID string) error {
+	return nil
+}
+func (m *MockGroupsRepository) GetUserJoinRequests(ctx context.Context, userID string) ([]groups.OutgoingJoinRequest, error) {
+	return []groups.OutgoingJoinRequest{}, nil
+}
+func (m *MockGroupsRepository) ExpireJoinRequests(ctx context.Context, now time.Time, limit int) ([]groups.OutgoingJoinRequest, error) {
+	return nil, nil
+}
+func (m *MockGroupsRepository) GetItinerary(ctx context.Context, groupID string) (*groups.Itinerary, error) {
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepository) SaveItinerary(ctx context.Context, groupID, userID string, legs []groups.ItineraryLeg) (*groups.Itinerary, error) {
+	return &groups.Itinerary{GroupID: groupID, Legs: legs, UpdatedBy: userID}, nil
+}
+func (m *MockGroupsRepository) AddItineraryLeg(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error) {
+	return &groups.Itinerary{GroupID: groupID, Legs: []groups.ItineraryLeg{leg}, UpdatedBy: userID}, nil
+}
+func (m *MockGroupsRepository) UpdateItineraryLeg(ctx context.Context, groupID, userID string, leg groups.ItineraryLeg) (*groups.Itinerary, error) {
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepository) DeleteItineraryLeg(ctx context.Context, groupID, userID, legID string) (*groups.Itinerary, error) {
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepository) DeleteItinerary(ctx context.Context, groupID string) error {
+	return nil
+}
+func (m *MockGroupsRepository) CreateExpense(ctx context.Context, expense *groups.Expense) error {
+	return nil
+}
+func (m *MockGroupsRepository) GetExpense(ctx context.Context, groupID, expenseID string) (*groups.Expense, error) {
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepository) GetExpenses(ctx context.Context, groupID string) ([]groups.Expense, error) {
+	return []groups.Expense{}, nil
+}
+func (m *MockGroupsRepository) DeleteExpense(ctx context.Context, groupID, expenseID string) error {
+	return nil
+}
+func (m *MockGroupsRepository) CreateSettlement(ctx context.Context, settlement *groups.Settlement) error {
+	return nil
+}
+func (m *MockGroupsRepository) GetSettlements(ctx context.Context, groupID string) ([]groups.Settlement, error) {
+	return []groups.Settlement{}, nil
+}
+func (m *MockGroupsRepository) GetMemberRole(ctx context.Context, groupID, userID string) (string, error) {
+	return "", sql.ErrNoRows
+}
+func (m *MockGroupsRepository) SetMemberRole(ctx context.Context, groupID, userID, role string) error {
+	return nil
+}
+func (m *MockGroupsRepository) TransferOwnership(ctx context.Context, groupID, toUserID string) error {
+	return nil
+}
+func (m *MockGroupsRepository) CreateInvite(ctx context.Context, invite *groups.Invite) error {
+	return nil
+}
+func (m *MockGroupsRepository) GetInvites(ctx context.Context, groupID string) ([]groups.Invite, error) {
+	return []groups.Invite{}, nil
+}
+func (m *MockGroupsRepository) GetInviteByCode(ctx context.Context, code string) (*groups.InviteDetails, error) {
+	return nil, sql.ErrNoRows
+}
+func (m *MockGroupsRepository) GetUserInvites(ctx context.Context, userID string) ([]groups.InviteDetails, error) {
+	return []groups.InviteDetails{}, nil
+}
+func (m *MockGroupsRepository) UseInvite(ctx context.Context, inviteID string) error {
+	return nil
+}
+func (m *MockGroupsRepository) ReleaseInvite(ctx context.Context, inviteID string) error {
+	return nil
+}
+func (m *MockGroupsRepository) CloseInvite(ctx context.Context, groupID, inviteID, status string) error {
+	return nil
+}
+func (m *MockGroupsRepository) JoinWaitlist(ctx context.Context, groupID, userID string) (int, error) {
+	return 1, nil
+}
+func (m *MockGroupsRepository) GetWaitlist(ctx context.Context, groupID string) ([]groups.WaitlistEntry, error) {
+	return []groups.WaitlistEntry{}, nil
+}
+func (m *MockGroupsRepository) LeaveWaitlist(ctx context.Context, groupID, userID string) error {
+	return sql.ErrNoRows
+}
+func (m *MockGroupsRepository) PromoteFromWaitlist(ctx context.Context, groupID string) ([]groups.Promotion, error) {
+	return nil, nil
+}
+func (m *MockGroupsRepository) ClaimWaitlistOffer(ctx context.Context, groupID, userID string) error {
+	return groups.ErrNoWaitlistOffer
+}
+func (m *MockGroupsRepository) ExpireWaitlistOffers(ctx context.Context, now time.Time, limit int) ([]groups.WaitlistEntry, error) {
+	return nil, nil
+}
+func (m *MockGroupsRepository) CreateRating(ctx context.Context, rating *groups.Rating) error {
+	return nil
+}
+func (m *MockGroupsRepository) GetRatingsByRater(ctx context.Context, groupID, raterID string) ([]groups.Rating, error) {
+	return nil, nil
+}
 
 // MockMessagesRepository implements messages.Repository with optional func overrides.
 type MockMessagesRepository struct {
-	GetOrCreateDirectThreadFunc func(ctx context.Context, userID1, userID2 string, isRequest bool) (messages.Thread, error)
-	CreateGroupThreadFunc       func(ctx context.Context, groupID string, memberIDs []string) (messages.Thread, error)
-	ListUserThreadsFunc         func(ctx context.Context, userID string) ([]messages.ThreadSummary, error)
-	GetMessagesFunc             func(ctx context.Context, threadID, userID string, before time.Time, limit int) ([]messages.Message, error)
-	CreateMessageFunc           func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error)
-	DeleteMessageFunc           func(ctx context.Context, messageID, userID string) (string, error)
-	ClearThreadFunc             func(ctx context.Context, threadID, userID string) error
-	MarkThreadAsReadFunc        func(ctx context.Context, threadID, userID string) error
-	AcceptRequestFunc           func(ctx context.Context, threadID, userID string) error
-	DeclineRequestFunc          func(ctx context.Context, threadID, userID string) error
-	IsParticipantFunc           func(ctx context.Context, threadID, userID string) (bool, error)
-	GetParticipantIDsFunc       func(ctx context.Context, threadID string) ([]string, error)
-	UpsertDeviceTokenFunc       func(ctx context.Context, userID, token, platform string) error
-	GetDeviceTokensFunc         func(ctx context.Context, userID string) ([]string, error)
-	RemoveDeviceTokenFunc       func(ctx context.Context, userID, token string) error
-	IsBlockedFunc               func(ctx context.Context, userID1, userID2 string) (bool, error)
+	GetOrCreateDirectThreadFunc   func(ctx context.Context, userID1, userID2 string, isRequest bool) (messages.Thread, error)
+	CreateGroupThreadFunc         func(ctx context.Context, groupID string, memberIDs []string) (messages.Thread, error)
+	ListUserThreadsFunc           func(ctx context.Context, userID string) ([]messages.ThreadSummary, error)
+	GetMessagesFunc               func(ctx context.Context, threadID, userID string, q messages.MessageQuery) ([]messages.Message, error)
+	CreateMessageFunc             func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error)
+	CreateForwardedMessageFunc    func(ctx context.Context, threadID, senderID string, src messages.Message) (messages.Message, error)
+	CreateSystemMessageFunc       func(ctx context.Context, threadID, actorID, kind string, metadata map[string]string) (messages.Message, error)
+	GetMessageFunc                func(ctx context.Context, messageID, userID string) (messages.Message, error)
+	DeleteMessageFunc             func(ctx context.Context, messageID, userID string) (string, error)
+	GetReplyTreeFunc              func(ctx context.Context, threadID, messageID, userID string) ([]messages.Message, error)
+	SearchMessagesFunc            func(ctx context.Context, userID, query string, after *messages.Cursor, limit int) ([]messages.SearchResult, error)
+	DeleteExpiredMessagesFunc     func(ctx context.Context, now time.Time, limit int) ([]messages.ExpiredMessage, error)
+	CreateScheduledMessageFunc    func(ctx context.Context, sm messages.ScheduledMessage) (messages.ScheduledMessage, error)
+	ListScheduledMessagesFunc     func(ctx context.Context, userID string) ([]messages.ScheduledMessage, error)
+	CancelScheduledMessageFunc    func(ctx context.Context, id, userID string) error
+	ClaimDueScheduledMessagesFunc func(ctx context.Context, now time.Time, limit int) ([]messages.ScheduledMessage, error)
+	CompleteScheduledMessageFunc  func(ctx context.Context, id string, messageID *string, deliveryErr error) error
+	SaveMentionsFunc              func(ctx context.Context, messageID string, mentions []messages.Mention) error
+	ListMentionsFunc              func(ctx context.Context, userID string, after *messages.Cursor, limit int) ([]messages.MentionedMessage, error)
+	GetParticipantsFunc           func(ctx context.Context, threadID string) ([]messages.Participant, error)
+	ClearThreadFunc               func(ctx context.Context, threadID, userID string) error
+	MarkThreadAsReadFunc          func(ctx context.Context, threadID, userID string) error
+	AcceptRequestFunc             func(ctx context.Context, threadID, userID string) error
+	DeclineRequestFunc            func(ctx context.Context, threadID, userID string, block bool) error
+	OpenMessageRequestFunc        func(ctx context.Context, senderID, recipientID string) (messages.Thread, error)
+	IsRequestRecipientFunc        func(ctx context.Context, threadID, userID string) (bool, error)
+	GetRequestStatsFunc           func(ctx context.Context, senderID string, now time.Time) (messages.RequestStats, error)
+	GetThreadFunc                 func(ctx context.Context, threadID string) (messages.Thread, error)
+	GetThreadSettingsFunc         func(ctx context.Context, threadID, userID string) (messages.ThreadSettings, error)
+	UpdateThreadSettingsFunc      func(ctx context.Context, threadID, userID string, settings messages.ThreadSettings) error
+	SetThreadNoForwardFunc        func(ctx context.Context, threadID string, noForward bool) error
+	SetThreadMessageTTLFunc       func(ctx context.Context, threadID string, ttlSeconds int) error
+	GetPushPreferencesFunc        func(ctx context.Context, userID string) (messages.PushPreferences, error)
+	CanManageThreadFunc           func(ctx context.Context, threadID, userID string) (bool, error)
+	PinMessageFunc                func(ctx context.Context, threadID, messageID, userID string) error
+	UnpinMessageFunc              func(ctx context.Context, threadID, messageID string) error
+	GetPinnedMessagesFunc         func(ctx context.Context, threadID string) ([]messages.PinnedMessage, error)
+	IsParticipantFunc             func(ctx context.Context, threadID, userID string) (bool, error)
+	GetParticipantIDsFunc         func(ctx context.Context, threadID string) ([]string, error)
+	UpsertDeviceTokenFunc         func(ctx context.Context, userID, token, platform string) error
+	GetDeviceTokensFunc           func(ctx context.Context, userID string) ([]string, error)
+	RemoveDeviceTokenFunc         func(ctx context.Context, userID, token string) error
+	IsBlockedFunc                 func(ctx context.Context, userID1, userID2 string) (bool, error)
 }
 
 func (m *MockMessagesRepository) GetOrCreateDirectThread(ctx context.Context, userID1, userID2 string, isRequest bool) (messages.Thread, error) {
@@ -316,9 +489,9 @@ func (m *MockMessagesRepository) ListUserThreads(ctx context.Context, userID str
 	}
 	return []messages.ThreadSummary{}, nil
 }
-func (m *MockMessagesRepository) GetMessages(ctx context.Context, threadID, userID string, before time.Time, limit int) ([]messages.Message, error) {
+func (m *MockMessagesRepository) GetMessages(ctx context.Context, threadID, userID string, q messages.MessageQuery) ([]messages.Message, error) {
 	if m.GetMessagesFunc != nil {
-		return m.GetMessagesFunc(ctx, threadID, userID, before, limit)
+		return m.GetMessagesFunc(ctx, threadID, userID, q)
 	}
 	return []messages.Message{}, nil
 }
@@ -328,12 +501,92 @@ func (m *MockMessagesRepository) CreateMessage(ctx context.Context, threadID, se
 	}
 	return messages.Message{ID: "mock-msg-id", Content: content}, nil
 }
+func (m *MockMessagesRepository) CreateForwardedMessage(ctx context.Context, threadID, senderID string, src messages.Message) (messages.Message, error) {
+	if m.CreateForwardedMessageFunc != nil {
+		return m.CreateForwardedMessageFunc(ctx, threadID, senderID, src)
+	}
+	return messages.Message{ID: "mock-fwd-id", ThreadID: threadID, SenderID: senderID, Content: src.Content, IsForwarded: true, OriginalMessageID: &src.ID}, nil
+}
+func (m *MockMessagesRepository) CreateSystemMessage(ctx context.Context, threadID, actorID, kind string, metadata map[string]string) (messages.Message, error) {
+	if m.CreateSystemMessageFunc != nil {
+		return m.CreateSystemMessageFunc(ctx, threadID, actorID, kind, metadata)
+	}
+	return messages.Message{ID: "mock-sys-id", ThreadID: threadID, SenderID: actorID, Kind: kind, Metadata: metadata}, nil
+}
+func (m *MockMessagesRepository) GetMessage(ctx context.Context, messageID, userID string) (messages.Message, error) {
+	if m.GetMessageFunc != nil {
+		return m.GetMessageFunc(ctx, messageID, userID)
+	}
+	return messages.Message{ID: messageID, ThreadID: "mock-thread-id", Content: "mock content"}, nil
+}
 func (m *MockMessagesRepository) DeleteMessage(ctx context.Context, messageID, userID string) (string, error) {
 	if m.DeleteMessageFunc != nil {
 		return m.DeleteMessageFunc(ctx, messageID, userID)
 	}
 	return "mock-thread-id", nil
 }
+func (m *MockMessagesRepository) GetReplyTree(ctx context.Context, threadID, messageID, userID string) ([]messages.Message, error) {
+	if m.GetReplyTreeFunc != nil {
+		return m.GetReplyTreeFunc(ctx, threadID, messageID, userID)
+	}
+	return []messages.Message{{ID: messageID, ThreadID: threadID}}, nil
+}
+func (m *MockMessagesRepository) SearchMessages(ctx context.Context, userID, query string, after *messages.Cursor, limit int) ([]messages.SearchResult, error) {
+	if m.SearchMessagesFunc != nil {
+		return m.SearchMessagesFunc(ctx, userID, query, after, limit)
+	}
+	return []messages.SearchResult{}, nil
+}
+func (m *MockMessagesRepository) DeleteExpiredMessages(ctx context.Context, now time.Time, limit int) ([]messages.ExpiredMessage, error) {
+	if m.DeleteExpiredMessagesFunc != nil {
+		return m.DeleteExpiredMessagesFunc(ctx, now, limit)
+	}
+	return nil, nil
+}
+func (m *MockMessagesRepository) CreateScheduledMessage(ctx context.Context, sm messages.ScheduledMessage) (messages.ScheduledMessage, error) {
+	if m.CreateScheduledMessageFunc != nil {
+		return m.CreateScheduledMessageFunc(ctx, sm)
+	}
+	sm.ID = "mock-scheduled-id"
+	sm.Status = messages.ScheduledPending
+	return sm, nil
+}
+func (m *MockMessagesRepository) ListScheduledMessages(ctx context.Context, userID string) ([]messages.ScheduledMessage, error) {
+	if m.ListScheduledMessagesFunc != nil {
+		return m.ListScheduledMessagesFunc(ctx, userID)
+	}
+	return []messages.ScheduledMessage{}, nil
+}
+func (m *MockMessagesRepository) CancelScheduledMessage(ctx context.Context, id, userID string) error {
+	if m.CancelScheduledMessageFunc != nil {
+		return m.CancelScheduledMessageFunc(ctx, id, userID)
+	}
+	return nil
+}
+func (m *MockMessagesRepository) ClaimDueScheduledMessages(ctx context.Context, now time.Time, limit int) ([]messages.ScheduledMessage, error) {
+	if m.ClaimDueScheduledMessagesFunc != nil {
+		return m.ClaimDueScheduledMessagesFunc(ctx, now, limit)
+	}
+	return []messages.ScheduledMessage{}, nil
+}
+func (m *MockMessagesRepository) CompleteScheduledMessage(ctx context.Context, id string, messageID *string, deliveryErr error) error {
+	if m.CompleteScheduledMessageFunc != nil {
+		return m.CompleteScheduledMessageFunc(ctx, id, messageID, deliveryErr)
+	}
+	return nil
+}
+func (m *MockMessagesRepository) SaveMentions(ctx context.Context, messageID string, mentions []messages.Mention) error {
+	if m.SaveMentionsFunc != nil {
+		return m.SaveMentionsFunc(ctx, messageID, mentions)
+	}
+	return nil
+}
+func (m *MockMessagesRepository) ListMentions(ctx context.Context, userID string, after *messages.Cursor, limit int) ([]messages.MentionedMessage, error) {
+	if m.ListMentionsFunc != nil {
+		return m.ListMentionsFunc(ctx, userID, after, limit)
+	}
+	return []messages.MentionedMessage{}, nil
+}
 func (m *MockMessagesRepository) ClearThread(ctx context.Context, threadID, userID string) error {
 	if m.ClearThreadFunc != nil {
 		return m.ClearThreadFunc(ctx, threadID, userID)
@@ -352,12 +605,90 @@ func (m *MockMessagesRepository) AcceptRequest(ctx context.Context, threadID, us
 	}
 	return nil
 }
-func (m *MockMessagesRepository) DeclineRequest(ctx context.Context, threadID, userID string) error {
+func (m *MockMessagesRepository) DeclineRequest(ctx context.Context, threadID, userID string, block bool) error {
 	if m.DeclineRequestFunc != nil {
-		return m.DeclineRequestFunc(ctx, threadID, userID)
+		return m.DeclineRequestFunc(ctx, threadID, userID, block)
 	}
 	return nil
 }
+func (m *MockMessagesRepository) OpenMessageRequest(ctx context.Context, senderID, recipientID string) (messages.Thread, error) {
+	if m.OpenMessageRequestFunc != nil {
+		return m.OpenMessageRequestFunc(ctx, senderID, recipientID)
+	}
+	return messages.Thread{ID: "mock-thread-id", Type: "direct", IsRequest: true}, nil
+}
+func (m *MockMessagesRepository) IsRequestRecipient(ctx context.Context, threadID, userID string) (bool, error) {
+	if m.IsRequestRecipientFunc != nil {
+		return m.IsRequestRecipientFunc(ctx, threadID, userID)
+	}
+	return true, nil
+}
+func (m *MockMessagesRepository) GetRequestStats(ctx context.Context, senderID string, now time.Time) (messages.RequestStats, error) {
+	if m.GetRequestStatsFunc != nil {
+		return m.GetRequestStatsFunc(ctx, senderID, now)
+	}
+	return messages.RequestStats{}, nil
+}
+func (m *MockMessagesRepository) GetThread(ctx context.Context, threadID string) (messages.Thread, error) {
+	if m.GetThreadFunc != nil {
+		return m.GetThreadFunc(ctx, threadID)
+	}
+	return messages.Thread{ID: threadID, Type: "group"}, nil
+}
+func (m *MockMessagesRepository) GetThreadSettings(ctx context.Context, threadID, userID string) (messages.ThreadSettings, error) {
+	if m.GetThreadSettingsFunc != nil {
+		return m.GetThreadSettingsFunc(ctx, threadID, userID)
+	}
+	return messages.ThreadSettings{NotificationLevel: messages.NotifyAll}, nil
+}
+func (m *MockMessagesRepository) UpdateThreadSettings(ctx context.Context, threadID, userID string, settings messages.ThreadSettings) error {
+	if m.UpdateThreadSettingsFunc != nil {
+		return m.UpdateThreadSettingsFunc(ctx, threadID, userID, settings)
+	}
+	return nil
+}
+func (m *MockMessagesRepository) SetThreadNoForward(ctx context.Context, threadID string, noForward bool) error {
+	if m.SetThreadNoForwardFunc != nil {
+		return m.SetThreadNoForwardFunc(ctx, threadID, noForward)
+	}
+	return nil
+}
+func (m *MockMessagesRepository) SetThreadMessageTTL(ctx context.Context, threadID string, ttlSeconds int) error {
+	if m.SetThreadMessageTTLFunc != nil {
+		return m.SetThreadMessageTTLFunc(ctx, threadID, ttlSeconds)
+	}
+	return nil
+}
+func (m *MockMessagesRepository) GetPushPreferences(ctx context.Context, userID string) (messages.PushPreferences, error) {
+	if m.GetPushPreferencesFunc != nil {
+		return m.GetPushPreferencesFunc(ctx, userID)
+	}
+	return messages.PushPreferences{PushNotifications: true, MessageAlerts: true}, nil
+}
+func (m *MockMessagesRepository) CanManageThread(ctx context.Context, threadID, userID string) (bool, error) {
+	if m.CanManageThreadFunc != nil {
+		return m.CanManageThreadFunc(ctx, threadID, userID)
+	}
+	return true, nil
+}
+func (m *MockMessagesRepository) PinMessage(ctx context.Context, threadID, messageID, userID string) error {
+	if m.PinMessageFunc != nil {
+		return m.PinMessageFunc(ctx, threadID, messageID, userID)
+	}
+	return nil
+}
+func (m *MockMessagesRepository) UnpinMessage(ctx context.Context, threadID, messageID string) error {
+	if m.UnpinMessageFunc != nil {
+		return m.UnpinMessageFunc(ctx, threadID, messageID)
+	}
+	return nil
+}
+func (m *MockMessagesRepository) GetPinnedMessages(ctx context.Context, threadID string) ([]messages.PinnedMessage, error) {
+	if m.GetPinnedMessagesFunc != nil {
+		return m.GetPinnedMessagesFunc(ctx, threadID)
+	}
+	return []messages.PinnedMessage{}, nil
+}
 func (m *MockMessagesRepository) IsParticipant(ctx context.Context, threadID, userID string) (bool, error) {
 	if m.IsParticipantFunc != nil {
 		return m.IsParticipantFunc(ctx, threadID, userID)
@@ -370,6 +701,12 @@ func (m *MockMessagesRepository) GetParticipantIDs(ctx context.Context, threadID
 	}
 	return []string{}, nil
 }
+func (m *MockMessagesRepository) GetParticipants(ctx context.Context, threadID string) ([]messages.Participant, error) {
+	if m.GetParticipantsFunc != nil {
+		return m.GetParticipantsFunc(ctx, threadID)
+	}
+	return []messages.Participant{}, nil
+}
 func (m *MockMessagesRepository) UpsertDeviceToken(ctx context.Context, userID, token, platform string) error {
 	if m.UpsertDeviceTokenFunc != nil {
 		return m.UpsertDeviceTokenFunc(ctx, userID, token, platform)
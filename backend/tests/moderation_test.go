@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/moderation"
+	"github.com/muskan953/college-Hop/internal/profile"
+	"github.com/muskan953/college-Hop/internal/server"
+)
+
+type recordingFlagger struct {
+	flags []moderation.Flag
+}
+
+func (f *recordingFlagger) Flag(ctx context.Context, fl moderation.Flag) error {
+	f.flags = append(f.flags, fl)
+	return nil
+}
+
+// newTestModerator returns a moderator with cfg and a flagger that records what it flags.
+func newTestModerator(t *testing.T, cfg moderation.Config) (*moderation.Moderator, *recordingFlagger) {
+	t.Helper()
+	m := moderation.New()
+	if err := m.SetRules(cfg); err != nil {
+		t.Fatalf("SetRules: %v", err)
+	}
+	flagger := &recordingFlagger{}
+	m.SetFlagger(flagger)
+	return m, flagger
+}
+
+var testRules = moderation.Config{
+	Rules: []moderation.RuleConfig{
+		{Name: "slurs", Words: []string{"badword"}, Action: moderation.ActionReject},
+		{Name: "mild", Words: []string{"darn", "heck"}, Action: moderation.ActionMask},
+		{Name: "links", Pattern: `https?://\S+`, Action: moderation.ActionFlag, Fields: []string{moderation.FieldMessage}},
+	},
+}
+
+func TestModerator_Check(t *testing.T) {
+	m, _ := newTestModerator(t, testRules)
+
+	tests := []struct {
+		name       string
+		in         moderation.Input
+		wantAction string
+		wantText   string
+	}{
+		{"clean", moderation.Input{Field: moderation.FieldMessage, Text: "See you at the station"}, moderation.ActionAllow, "See you at the station"},
+		{"reject is case-insensitive", moderation.Input{Field: moderation.FieldBio, Text: "I am a BadWord"}, moderation.ActionReject, "I am a BadWord"},
+		{"whole words only", moderation.Input{Field: moderation.FieldMessage, Text: "checkdarnit"}, moderation.ActionAllow, "checkdarnit"},
+		{"mask", moderation.Input{Field: moderation.FieldMessage, Text: "Oh darn, the heck"}, moderation.ActionMask, "Oh ****, the ****"},
+		{"flag scoped to messages", moderation.Input{Field: moderation.FieldMessage, Text: "https://example.com"}, moderation.ActionFlag, "https://example.com"},
+		{"flag not applied to bios", moderation.Input{Field: moderation.FieldBio, Text: "https://example.com"}, moderation.ActionAllow, "https://example.com"},
+		{"phone outside request thread", moderation.Input{Field: moderation.FieldMessage, Text: "Call 9876543210"}, moderation.ActionAllow, "Call 9876543210"},
+		{"phone in request thread", moderation.Input{Field: moderation.FieldMessage, Text: "Call +91 98765 43210", RequestThread: true}, moderation.ActionMask, "Call *** ***** *****"},
+		{"upi in request thread", moderation.Input{Field: moderation.FieldMessage, Text: "pay riya@okaxis", RequestThread: true}, moderation.ActionMask, "pay ***********"},
+		{"email is not a upi id", moderation.Input{Field: moderation.FieldMessage, Text: "mail riya@nitw.ac.in", RequestThread: true}, moderation.ActionAllow, "mail riya@nitw.ac.in"},
+	}
+	for _, tt := range tests {
+		v := m.Check(tt.in)
+		if v.Action != tt.wantAction || v.Text != tt.wantText {
+			t.Errorf("%s: got (%s, %q), want (%s, %q)", tt.name, v.Action, v.Text, tt.wantAction, tt.wantText)
+		}
+	}
+}
+
+func TestModerator_InvalidRules(t *testing.T) {
+	m := moderation.New()
+	bad := []moderation.Config{
+		{Rules: []moderation.RuleConfig{{Name: "no action", Words: []string{"x"}}}},
+		{Rules: []moderation.RuleConfig{{Name: "both", Words: []string{"x"}, Pattern: "y", Action: moderation.ActionFlag}}},
+		{Rules: []moderation.RuleConfig{{Name: "bad regex", Pattern: "(", Action: moderation.ActionFlag}}},
+		{Rules: []moderation.RuleConfig{{Name: "bad field", Words: []string{"x"}, Action: moderation.ActionFlag, Fields: []string{"title"}}}},
+		{ContactLeakAction: "shout"},
+	}
+	for _, cfg := range bad {
+		if err := m.SetRules(cfg); err == nil {
+			t.Errorf("expected %+v to be rejected", cfg)
+		}
+	}
+}
+
+func TestModerator_ReloadKeepsRulesOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	write := func(cfg interface{}) {
+		data, _ := json.Marshal(cfg)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := moderation.New()
+	write(moderation.Config{Rules: []moderation.RuleConfig{{Words: []string{"darn"}, Action: moderation.ActionReject}}})
+	if err := m.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if !m.Check(moderation.Input{Field: moderation.FieldBio, Text: "darn"}).Rejected() {
+		t.Fatal("expected the loaded rule to reject")
+	}
+
+	write(moderation.Config{Rules: []moderation.RuleConfig{{Words: []string{"darn"}, Action: moderation.ActionMask}}})
+	if err := m.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if v := m.Check(moderation.Input{Field: moderation.FieldBio, Text: "darn"}); v.Action != moderation.ActionMask {
+		t.Fatalf("expected the reloaded rule to mask, got %s", v.Action)
+	}
+
+	write(map[string]interface{}{"rules": "not a list"})
+	if err := m.Reload(); err == nil {
+		t.Fatal("expected an invalid file to fail")
+	}
+	if v := m.Check(moderation.Input{Field: moderation.FieldBio, Text: "darn"}); v.Action != moderation.ActionMask {
+		t.Errorf("invalid reload should keep the previous rules, got %s", v.Action)
+	}
+}
+
+func postSend(t *testing.T, m *moderation.Moderator, mockRepo *MockMessagesRepository, content string) *httptest.ResponseRecorder {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "testsecret")
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
+		&MockEventsRepository{}, &MockGroupsRepository{},
+		mockRepo, messages.NewHub(mockRepo, nil, m), &MockFileStorage{}, "./uploads", nil, m, nil,
+	)
+	body, _ := json.Marshal(map[string]string{"thread_id": "thread-1", "content": content})
+	req, _ := http.NewRequest("POST", "/messages/send", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSendMessage_RejectedByModeration(t *testing.T) {
+	m, _ := newTestModerator(t, testRules)
+	mockRepo := &MockMessagesRepository{
+		CreateMessageFunc: func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error) {
+			t.Error("rejected content must not be stored")
+			return messages.Message{}, nil
+		},
+	}
+	rr := postSend(t, m, mockRepo, "you badword")
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("POST /messages/send with rejected word: got %d, want 400", rr.Code)
+	}
+}
+
+func TestSendMessage_MasksContactsInRequestThread(t *testing.T) {
+	m, _ := newTestModerator(t, testRules)
+	var stored string
+	mockRepo := &MockMessagesRepository{
+		GetThreadFunc: func(ctx context.Context, threadID string) (messages.Thread, error) {
+			return messages.Thread{ID: threadID, Type: "direct", IsRequest: true}, nil
+		},
+		CreateMessageFunc: func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error) {
+			stored = content
+			return messages.Message{ID: "msg-1", ThreadID: threadID, SenderID: senderID, Content: content}, nil
+		},
+	}
+	rr := postSend(t, m, mockRepo, "text me on 9876543210")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST /messages/send: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if stored != "text me on **********" {
+		t.Errorf("phone number should be masked in request threads, stored %q", stored)
+	}
+}
+
+func TestSendMessage_FlaggedForReview(t *testing.T) {
+	m, flagger := newTestModerator(t, testRules)
+	rr := postSend(t, m, &MockMessagesRepository{}, "join via https://example.com/x")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST /messages/send: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if len(flagger.flags) != 1 || flagger.flags[0].TargetType != "message" || flagger.flags[0].UserID != "user-1" {
+		t.Fatalf("expected one message flag for user-1, got %+v", flagger.flags)
+	}
+	if flagger.flags[0].Reason != "matched links" {
+		t.Errorf("unexpected flag reason %q", flagger.flags[0].Reason)
+	}
+}
+
+func TestUpdateMe_BioRejectedByModeration(t *testing.T) {
+	m, _ := newTestModerator(t, testRules)
+	t.Setenv("JWT_SECRET", "testsecret")
+	mockProfileRepo := &MockProfileRepository{
+		UpsertProfileFunc: func(ctx context.Context, userID string, req profile.UpdateProfileRequest) error {
+			t.Error("rejected bio must not be saved")
+			return nil
+		},
+	}
+	router := server.NewRouter(&MockAuthRepository{}, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads", nil, m, nil)
+
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+	body, _ := json.Marshal(map[string]interface{}{
+		"full_name":    "Test User",
+		"college_name": "NIT Warangal",
+		"major":        "CS",
+		"roll_number":  "123",
+		"bio":          "badword enthusiast",
+	})
+	req, _ := http.NewRequest("PUT", "/me", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("PUT /me with rejected bio: got %d, want 400", rr.Code)
+	}
+}
@@ -0,0 +1,214 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// threadHistory returns n messages one minute apart, oldest first, with the last
+// two sharing a timestamp to exercise the id tie-break.
+func threadHistory(n int) []messages.Message {
+	base := time.Date(2026, 4, 14, 12, 0, 0, 0, time.UTC)
+	msgs := make([]messages.Message, n)
+	for i := range msgs {
+		ts := base.Add(time.Duration(i) * time.Minute)
+		if i == n-1 {
+			ts = msgs[i-1].CreatedAt
+		}
+		msgs[i] = messages.Message{
+			ID:        fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+			ThreadID:  "thread-1",
+			Content:   fmt.Sprintf("message %d", i),
+			CreatedAt: ts,
+		}
+	}
+	return msgs
+}
+
+func cursorLess(m messages.Message, c messages.Cursor) bool {
+	if !m.CreatedAt.Equal(c.CreatedAt) {
+		return m.CreatedAt.Before(c.CreatedAt)
+	}
+	return m.ID < c.ID
+}
+
+// pagedMessagesRepo answers GetMessages/GetMessage from history the way the SQL does.
+func pagedMessagesRepo(history []messages.Message) *MockMessagesRepository {
+	return &MockMessagesRepository{
+		GetMessageFunc: func(ctx context.Context, messageID, userID string) (messages.Message, error) {
+			for _, m := range history {
+				if m.ID == messageID {
+					return m, nil
+				}
+			}
+			return messages.Message{}, sql.ErrNoRows
+		},
+		GetMessagesFunc: func(ctx context.Context, threadID, userID string, q messages.MessageQuery) ([]messages.Message, error) {
+			var out []messages.Message
+			if q.After != nil {
+				for _, m := range history {
+					if !cursorLess(m, *q.After) && m.ID != q.After.ID && len(out) < q.Limit {
+						out = append([]messages.Message{m}, out...)
+					}
+				}
+				return out, nil
+			}
+			for i := len(history) - 1; i >= 0 && len(out) < q.Limit; i-- {
+				m := history[i]
+				if q.Before == nil || cursorLess(m, *q.Before) || (q.Inclusive && m.ID == q.Before.ID) {
+					out = append(out, m)
+				}
+			}
+			return out, nil
+		},
+	}
+}
+
+func getMessagePage(t *testing.T, repo *MockMessagesRepository, query string) (*httptest.ResponseRecorder, messages.MessagePage) {
+	t.Helper()
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, repo)
+	sep := "?"
+	if strings.Contains(query, "?") {
+		sep = "&"
+	}
+	req, _ := http.NewRequest("GET", "/messages/thread-1"+query+sep+"format=page", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	var page messages.MessagePage
+	json.NewDecoder(rr.Body).Decode(&page)
+	return rr, page
+}
+
+func pageContents(page messages.MessagePage) []string {
+	out := make([]string, len(page.Messages))
+	for i, m := range page.Messages {
+		out[i] = m.Content
+	}
+	return out
+}
+
+func TestGetMessages_PagesBackwardWithoutGaps(t *testing.T) {
+	repo := pagedMessagesRepo(threadHistory(7))
+
+	_, first := getMessagePage(t, repo, "?limit=3")
+	if got := fmt.Sprint(pageContents(first)); got != "[message 6 message 5 message 4]" {
+		t.Fatalf("first page = %s", got)
+	}
+	if !first.HasMoreBefore || first.HasMoreAfter {
+		t.Errorf("first page has_more_before=%v has_more_after=%v, want true/false", first.HasMoreBefore, first.HasMoreAfter)
+	}
+
+	_, second := getMessagePage(t, repo, "?limit=3&before="+first.BeforeCursor)
+	if got := fmt.Sprint(pageContents(second)); got != "[message 3 message 2 message 1]" {
+		t.Fatalf("second page = %s", got)
+	}
+
+	_, last := getMessagePage(t, repo, "?limit=3&before="+second.BeforeCursor)
+	if got := fmt.Sprint(pageContents(last)); got != "[message 0]" {
+		t.Fatalf("last page = %s", got)
+	}
+	if last.HasMoreBefore {
+		t.Error("last page should not have more before")
+	}
+}
+
+func TestGetMessages_After(t *testing.T) {
+	history := threadHistory(7)
+	repo := pagedMessagesRepo(history)
+	from := messages.Cursor{CreatedAt: history[1].CreatedAt, ID: history[1].ID}.Encode()
+
+	_, page := getMessagePage(t, repo, "?limit=2&after="+from)
+	if got := fmt.Sprint(pageContents(page)); got != "[message 3 message 2]" {
+		t.Fatalf("after page = %s", got)
+	}
+	if !page.HasMoreAfter {
+		t.Error("expected more messages after")
+	}
+
+	// Caught up: the cursor is handed back for polling
+	top := messages.Cursor{CreatedAt: history[6].CreatedAt, ID: history[6].ID}.Encode()
+	_, empty := getMessagePage(t, repo, "?after="+top)
+	if len(empty.Messages) != 0 || empty.HasMoreAfter || empty.AfterCursor != top {
+		t.Errorf("caught-up page = %+v, want empty with the same after_cursor", empty)
+	}
+}
+
+func TestGetMessages_Around(t *testing.T) {
+	history := threadHistory(9)
+	repo := pagedMessagesRepo(history)
+
+	rr, page := getMessagePage(t, repo, "?limit=4&around="+history[4].ID)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("around: got %d, want 200", rr.Code)
+	}
+	if got := fmt.Sprint(pageContents(page)); got != "[message 6 message 5 message 4 message 3]" {
+		t.Fatalf("around page = %s", got)
+	}
+	if !page.HasMoreBefore || !page.HasMoreAfter {
+		t.Errorf("around page has_more_before=%v has_more_after=%v, want both true", page.HasMoreBefore, page.HasMoreAfter)
+	}
+}
+
+func TestGetMessages_AroundOtherThread(t *testing.T) {
+	repo := pagedMessagesRepo(nil)
+	repo.GetMessageFunc = func(ctx context.Context, messageID, userID string) (messages.Message, error) {
+		return messages.Message{ID: messageID, ThreadID: "thread-2"}, nil
+	}
+	rr, _ := getMessagePage(t, repo, "?around=00000000-0000-0000-0000-000000000001")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("around a message in another thread: got %d, want 404", rr.Code)
+	}
+}
+
+func TestGetMessages_InvalidParams(t *testing.T) {
+	cursor := messages.Cursor{CreatedAt: time.Now(), ID: "00000000-0000-0000-0000-000000000001"}.Encode()
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"garbage cursor", "?after=not-a-cursor"},
+		{"limit too large", "?limit=101"},
+		{"limit zero", "?limit=0"},
+		{"two modes", "?before=" + cursor + "&after=" + cursor},
+	}
+	for _, tt := range tests {
+		rr, _ := getMessagePage(t, &MockMessagesRepository{}, tt.query)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", tt.name, rr.Code)
+		}
+	}
+}
+
+func TestGetMessages_LegacyClients(t *testing.T) {
+	repo := pagedMessagesRepo(threadHistory(7))
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, repo)
+
+	// Without format=page the response is a bare array, and before may be a timestamp
+	req, _ := http.NewRequest("GET", "/messages/thread-1?before=2026-04-14T12:03:00Z", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET with a timestamp before: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var msgs []messages.Message
+	if err := json.NewDecoder(rr.Body).Decode(&msgs); err != nil {
+		t.Fatalf("response is not an array: %v", err)
+	}
+	if got := fmt.Sprint(pageContents(messages.MessagePage{Messages: msgs})); got != "[message 2 message 1 message 0]" {
+		t.Errorf("messages before 12:03 = %s", got)
+	}
+}
@@ -30,7 +30,7 @@ func TestProfileGetMe(t *testing.T) {
 	}
 	mockStore := &MockFileStorage{}
 
-	router := server.NewRouter(mockAuthRepo, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, mockStore, "./uploads")
+	router := server.NewRouter(mockAuthRepo, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, mockStore, "./uploads", nil, nil, nil)
 
 	// Generate token (this uses the JWT_SECRET from env)
 	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
@@ -67,7 +67,7 @@ func TestProfileUpdateMe(t *testing.T) {
 	}
 	mockStore := &MockFileStorage{}
 
-	router := server.NewRouter(mockAuthRepo, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, mockStore, "./uploads")
+	router := server.NewRouter(mockAuthRepo, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, mockStore, "./uploads", nil, nil, nil)
 
 	// Generate token
 	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
@@ -97,7 +97,7 @@ func TestProfileUpdateValidation(t *testing.T) {
 	mockProfileRepo := &MockProfileRepository{}
 	mockStore := &MockFileStorage{}
 
-	router := server.NewRouter(mockAuthRepo, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, mockStore, "./uploads")
+	router := server.NewRouter(mockAuthRepo, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, mockStore, "./uploads", nil, nil, nil)
 	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
 
 	tests := []struct {
@@ -158,7 +158,7 @@ func TestProfileUpdateValidation(t *testing.T) {
 
 func TestGetConnections_RequiresAuth(t *testing.T) {
 	t.Setenv("JWT_SECRET", "testsecret")
-	router := server.NewRouter(&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads")
+	router := server.NewRouter(&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil)
 	req, _ := http.NewRequest("GET", "/me/connections", nil)
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
@@ -170,7 +170,7 @@ func TestGetConnections_RequiresAuth(t *testing.T) {
 func TestGetConnections_Success(t *testing.T) {
 	t.Setenv("JWT_SECRET", "testsecret")
 	mockProfileRepo := &MockProfileRepository{}
-	router := server.NewRouter(&MockAuthRepository{}, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads")
+	router := server.NewRouter(&MockAuthRepository{}, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil)
 	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
 	req, _ := http.NewRequest("GET", "/me/connections", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -183,7 +183,7 @@ func TestGetConnections_Success(t *testing.T) {
 
 func TestBlockUser_RequiresAuth(t *testing.T) {
 	t.Setenv("JWT_SECRET", "testsecret")
-	router := server.NewRouter(&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads")
+	router := server.NewRouter(&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil)
 	req, _ := http.NewRequest("POST", "/users/some-user-id/block", nil)
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
@@ -194,7 +194,7 @@ func TestBlockUser_RequiresAuth(t *testing.T) {
 
 func TestBlockUser_Success(t *testing.T) {
 	t.Setenv("JWT_SECRET", "testsecret")
-	router := server.NewRouter(&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads")
+	router := server.NewRouter(&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil)
 	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
 	req, _ := http.NewRequest("POST", "/users/other-user/block", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -221,7 +221,7 @@ func TestIsAlumni_VerifiedUser_ReturnsAlumniBadge(t *testing.T) {
 			}, nil
 		},
 	}
-	router := server.NewRouter(&MockAuthRepository{}, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads")
+	router := server.NewRouter(&MockAuthRepository{}, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil)
 	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
 	req, _ := http.NewRequest("GET", "/me", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -249,7 +249,7 @@ func TestIsAlumni_PendingUser_NoAlumniBadge(t *testing.T) {
 			}, nil
 		},
 	}
-	router := server.NewRouter(&MockAuthRepository{}, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads")
+	router := server.NewRouter(&MockAuthRepository{}, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil)
 	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
 	req, _ := http.NewRequest("GET", "/me", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -262,3 +262,38 @@ func TestIsAlumni_PendingUser_NoAlumniBadge(t *testing.T) {
 		t.Error("expected is_alumni=false for pending user even with past expiry")
 	}
 }
+
+func TestUpdateMe_OmittedGenderIsLeftUnchanged(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	var got profile.UpdateProfileRequest
+	mockProfileRepo := &MockProfileRepository{
+		UpsertProfileFunc: func(ctx context.Context, userID string, req profile.UpdateProfileRequest) error {
+			got = req
+			return nil
+		},
+	}
+	router := server.NewRouter(&MockAuthRepository{}, nil, mockProfileRepo, &MockAdminRepository{}, &MockEventsRepository{}, &MockGroupsRepository{}, nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil)
+
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+	body, _ := json.Marshal(map[string]interface{}{
+		"full_name":    "Test User",
+		"college_name": "NIT Warangal",
+		"major":        "CS",
+		"roll_number":  "123",
+		"home_city":    " Pune ",
+	})
+	req, _ := http.NewRequest("PUT", "/me", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT /me: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if got.Gender != nil {
+		t.Errorf("gender was not sent but reached the repository as %q", *got.Gender)
+	}
+	if got.HomeCity == nil || *got.HomeCity != "Pune" {
+		t.Errorf("home_city = %v, want Pune", got.HomeCity)
+	}
+}
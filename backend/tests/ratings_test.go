@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+)
+
+func TestNewTrustScore(t *testing.T) {
+	if ts := groups.NewTrustScore(groups.MinTrustRatings-1, 5, 5); ts != nil {
+		t.Errorf("below the minimum: got %+v, want nil", ts)
+	}
+	ts := groups.NewTrustScore(groups.MinTrustRatings, 4.666, 3.5)
+	if ts == nil {
+		t.Fatal("at the minimum: got nil")
+	}
+	if ts.Score != 4.08 || ts.Punctuality != 4.67 || ts.Communication != 3.5 || ts.Ratings != groups.MinTrustRatings {
+		t.Errorf("trust score = %+v", ts)
+	}
+}
+
+func TestRateMember(t *testing.T) {
+	var saved []groups.Rating
+	repo := statusRepo(groups.StatusCompleted, map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember})
+	repo.CreateRatingFunc = func(ctx context.Context, rating *groups.Rating) error {
+		for _, r := range saved {
+			if r.RaterID == rating.RaterID && r.RateeID == rating.RateeID {
+				return groups.ErrAlreadyRated
+			}
+		}
+		rating.ID = "r1"
+		saved = append(saved, *rating)
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	tests := []struct {
+		name string
+		user string
+		body groups.RateMemberRequest
+		want int
+	}{
+		{"outsider", "outsider", groups.RateMemberRequest{UserID: "owner", Punctuality: 5, Communication: 5}, http.StatusForbidden},
+		{"self", "member", groups.RateMemberRequest{UserID: "member", Punctuality: 5, Communication: 5}, http.StatusBadRequest},
+		{"ratee not a member", "member", groups.RateMemberRequest{UserID: "outsider", Punctuality: 5, Communication: 5}, http.StatusBadRequest},
+		{"score out of range", "member", groups.RateMemberRequest{UserID: "owner", Punctuality: 6, Communication: 5}, http.StatusBadRequest},
+		{"valid", "member", groups.RateMemberRequest{UserID: "owner", Punctuality: 4, Communication: 5}, http.StatusCreated},
+		{"rated twice", "member", groups.RateMemberRequest{UserID: "owner", Punctuality: 1, Communication: 1}, http.StatusConflict},
+		{"rated back", "owner", groups.RateMemberRequest{UserID: "member", Punctuality: 3, Communication: 4}, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		if rr := doItinerary(t, router, tt.user, "POST", "/groups/g1/ratings", tt.body); rr.Code != tt.want {
+			t.Errorf("%s: got %d, want %d. Body: %s", tt.name, rr.Code, tt.want, rr.Body.String())
+		}
+	}
+
+	if len(saved) != 2 {
+		t.Fatalf("saved %d ratings, want 2", len(saved))
+	}
+	if r := saved[0]; r.GroupID != "g1" || r.RaterID != "member" || r.RateeID != "owner" || r.Punctuality != 4 || r.Communication != 5 {
+		t.Errorf("saved rating = %+v", r)
+	}
+}
+
+func TestRateMember_OnlyCompletedGroups(t *testing.T) {
+	for _, status := range []string{groups.StatusOpen, groups.StatusLocked, groups.StatusTraveling, groups.StatusArchived} {
+		repo := statusRepo(status, map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember})
+		repo.CreateRatingFunc = func(ctx context.Context, rating *groups.Rating) error {
+			t.Errorf("%s: rating should not be saved", status)
+			return nil
+		}
+		router, _ := newSystemMessageRouter(t, repo)
+
+		body := groups.RateMemberRequest{UserID: "owner", Punctuality: 5, Communication: 5}
+		if rr := doItinerary(t, router, "member", "POST", "/groups/g1/ratings", body); rr.Code != http.StatusConflict {
+			t.Errorf("%s group: got %d, want 409", status, rr.Code)
+		}
+	}
+}
+
+func TestListMyRatings(t *testing.T) {
+	repo := statusRepo(groups.StatusCompleted, map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember})
+	repo.GetRatingsByRaterFunc = func(ctx context.Context, groupID, raterID string) ([]groups.Rating, error) {
+		if raterID != "member" {
+			return nil, nil
+		}
+		return []groups.Rating{{ID: "r1", GroupID: groupID, RaterID: raterID, RateeID: "owner", Punctuality: 4, Communication: 5}}, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "outsider", "GET", "/groups/g1/ratings", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("outsider: got %d, want 403", rr.Code)
+	}
+	for user, want := range map[string]int{"member": 1, "owner": 0} {
+		rr := doItinerary(t, router, user, "GET", "/groups/g1/ratings", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: got %d, want 200", user, rr.Code)
+		}
+		var ratings []groups.Rating
+		if err := json.NewDecoder(rr.Body).Decode(&ratings); err != nil || ratings == nil || len(ratings) != want {
+			t.Errorf("%s: ratings = %v (%v), want %d", user, ratings, err, want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/muskan953/college-Hop/internal/admin"
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/server"
+)
+
+func newReportsRouter(t *testing.T, adminRepo *MockAdminRepository) http.Handler {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "testsecret")
+	t.Setenv("ADMIN_SECRET", "test-admin-secret")
+	return server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, adminRepo,
+		&MockEventsRepository{}, &MockGroupsRepository{},
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
+	)
+}
+
+func postReport(t *testing.T, router http.Handler, payload map[string]interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", "/reports", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+const reportedMessageID = "7f0c7c1e-3f55-4d8e-9a55-2f1f4f7d2b10"
+
+func TestCreateReport_InvalidCategory(t *testing.T) {
+	router := newReportsRouter(t, &MockAdminRepository{})
+	rr := postReport(t, router, map[string]interface{}{
+		"target_type": "message",
+		"target_id":   reportedMessageID,
+		"category":    "rude",
+	})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("POST /reports with unknown category: got %d, want 400", rr.Code)
+	}
+}
+
+func TestCreateReport_Self(t *testing.T) {
+	router := newReportsRouter(t, &MockAdminRepository{
+		CreateReportFunc: func(ctx context.Context, reporterID string, req admin.CreateReportRequest) (admin.Report, error) {
+			return admin.Report{}, admin.ErrSelfReport
+		},
+	})
+	rr := postReport(t, router, map[string]interface{}{
+		"target_type": "message",
+		"target_id":   reportedMessageID,
+		"category":    "spam",
+	})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("POST /reports on own message: got %d, want 400", rr.Code)
+	}
+}
+
+func TestCreateReport_Duplicate(t *testing.T) {
+	router := newReportsRouter(t, &MockAdminRepository{
+		CreateReportFunc: func(ctx context.Context, reporterID string, req admin.CreateReportRequest) (admin.Report, error) {
+			return admin.Report{}, admin.ErrAlreadyReported
+		},
+	})
+	rr := postReport(t, router, map[string]interface{}{
+		"target_type": "message",
+		"target_id":   reportedMessageID,
+		"category":    "harassment",
+	})
+	if rr.Code != http.StatusConflict {
+		t.Errorf("POST /reports twice: got %d, want 409", rr.Code)
+	}
+}
+
+func TestCreateReport_Success(t *testing.T) {
+	var got admin.CreateReportRequest
+	var reporter string
+	router := newReportsRouter(t, &MockAdminRepository{
+		CreateReportFunc: func(ctx context.Context, reporterID string, req admin.CreateReportRequest) (admin.Report, error) {
+			got, reporter = req, reporterID
+			return admin.Report{ID: "report-1", Status: admin.ReportOpen}, nil
+		},
+	})
+	rr := postReport(t, router, map[string]interface{}{
+		"target_type": "message",
+		"target_id":   reportedMessageID,
+		"category":    "harassment",
+		"details":     "  Keeps messaging me after I asked them to stop  ",
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST /reports: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if reporter != "user-1" || got.TargetID != reportedMessageID || got.Category != "harassment" {
+		t.Errorf("unexpected report passed to repo: reporter=%q %+v", reporter, got)
+	}
+	if got.Details != "Keeps messaging me after I asked them to stop" {
+		t.Errorf("details should be trimmed, got %q", got.Details)
+	}
+}
+
+func TestListReports_NoSecret(t *testing.T) {
+	router := newReportsRouter(t, &MockAdminRepository{})
+	req, _ := http.NewRequest("GET", "/admin/reports", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("GET /admin/reports without secret: got %d, want 403", rr.Code)
+	}
+}
+
+func TestListReports_DefaultsToOpen(t *testing.T) {
+	var status string
+	router := newReportsRouter(t, &MockAdminRepository{
+		ListReportsFunc: func(ctx context.Context, s string) ([]admin.Report, error) {
+			status = s
+			return []admin.Report{{ID: "report-1", Status: s}}, nil
+		},
+	})
+	req, _ := http.NewRequest("GET", "/admin/reports", nil)
+	req.Header.Set("X-Admin-Secret", "test-admin-secret")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /admin/reports: got %d, want 200", rr.Code)
+	}
+	if status != admin.ReportOpen {
+		t.Errorf("expected the open queue by default, got %q", status)
+	}
+}
+
+func TestResolveReport_SuspendDaysWithoutSuspend(t *testing.T) {
+	router := newReportsRouter(t, &MockAdminRepository{})
+	body, _ := json.Marshal(map[string]interface{}{"action": "warn", "suspend_days": 7})
+	req, _ := http.NewRequest("POST", "/admin/reports/report-1/resolve", bytes.NewBuffer(body))
+	req.Header.Set("X-Admin-Secret", "test-admin-secret")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("suspend_days with action=warn: got %d, want 400", rr.Code)
+	}
+}
+
+func TestResolveReport_Suspend(t *testing.T) {
+	var gotID string
+	var got admin.ResolveReportRequest
+	router := newReportsRouter(t, &MockAdminRepository{
+		ResolveReportFunc: func(ctx context.Context, reportID string, req admin.ResolveReportRequest) (admin.Report, error) {
+			gotID, got = reportID, req
+			return admin.Report{ID: reportID, Status: admin.ReportResolved, Resolution: &req.Action}, nil
+		},
+	})
+	body, _ := json.Marshal(map[string]interface{}{"action": "suspend", "suspend_days": 7, "note": "Repeated harassment"})
+	req, _ := http.NewRequest("POST", "/admin/reports/report-1/resolve", bytes.NewBuffer(body))
+	req.Header.Set("X-Admin-Secret", "test-admin-secret")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /admin/reports/{id}/resolve: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if gotID != "report-1" || got.Action != admin.ActionSuspend || got.SuspendDays != 7 {
+		t.Errorf("unexpected resolution passed to repo: id=%q %+v", gotID, got)
+	}
+}
+
+func TestResolveReport_AlreadyClosed(t *testing.T) {
+	router := newReportsRouter(t, &MockAdminRepository{
+		ResolveReportFunc: func(ctx context.Context, reportID string, req admin.ResolveReportRequest) (admin.Report, error) {
+			return admin.Report{}, admin.ErrReportClosed
+		},
+	})
+	body, _ := json.Marshal(map[string]interface{}{"action": "dismiss"})
+	req, _ := http.NewRequest("POST", "/admin/reports/report-1/resolve", bytes.NewBuffer(body))
+	req.Header.Set("X-Admin-Secret", "test-admin-secret")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("resolving a closed report: got %d, want 409", rr.Code)
+	}
+}
+
+// liveReportsRouter returns a test server whose hub is running, plus the hub.
+func liveReportsRouter(t *testing.T, authRepo *MockAuthRepository, adminRepo *MockAdminRepository) (*httptest.Server, *messages.Hub) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "testsecret")
+	t.Setenv("ADMIN_SECRET", "test-admin-secret")
+	msgRepo := &MockMessagesRepository{}
+	hub := messages.NewHub(msgRepo, nil, nil)
+	go hub.Run()
+	srv := httptest.NewServer(server.NewRouter(
+		authRepo, nil, &MockProfileRepository{}, adminRepo,
+		&MockEventsRepository{}, &MockGroupsRepository{},
+		msgRepo, hub, &MockFileStorage{}, "./uploads", nil, nil, nil,
+	))
+	t.Cleanup(srv.Close)
+	return srv, hub
+}
+
+func dialWS(srv *httptest.Server, userID string) (*websocket.Conn, *http.Response, error) {
+	token, _ := auth.GenerateToken(userID, "student@nitw.ac.in")
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws?token="+token, nil)
+}
+
+func TestServeWS_RejectsSuspendedUser(t *testing.T) {
+	srv, hub := liveReportsRouter(t, &MockAuthRepository{
+		GetUserStatusFunc: func(ctx context.Context, userID string) (string, error) { return "suspended", nil },
+	}, &MockAdminRepository{})
+
+	conn, resp, err := dialWS(srv, "user-2")
+	if err == nil {
+		conn.Close()
+		t.Fatal("suspended user was able to open a WebSocket")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("dial as a suspended user: got %v, want 403", resp)
+	}
+	if hub.IsOnline("user-2") {
+		t.Error("suspended user should not be registered with the hub")
+	}
+}
+
+func TestResolveReport_SuspendDisconnectsUser(t *testing.T) {
+	reported := "user-2"
+	srv, hub := liveReportsRouter(t, &MockAuthRepository{}, &MockAdminRepository{
+		ResolveReportFunc: func(ctx context.Context, reportID string, req admin.ResolveReportRequest) (admin.Report, error) {
+			return admin.Report{ID: reportID, Status: admin.ReportResolved, Resolution: &req.Action, ReportedUserID: &reported}, nil
+		},
+	})
+
+	conn, _, err := dialWS(srv, reported)
+	if err != nil {
+		t.Fatalf("dial as %s: %v", reported, err)
+	}
+	defer conn.Close()
+	for deadline := time.Now().Add(2 * time.Second); !hub.IsOnline(reported); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s never came online", reported)
+		}
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"action": "suspend", "suspend_days": 7})
+	req, _ := http.NewRequest("POST", srv.URL+"/admin/reports/report-1/resolve", bytes.NewBuffer(body))
+	req.Header.Set("X-Admin-Secret", "test-admin-secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /admin/reports/{id}/resolve: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /admin/reports/{id}/resolve: got %d, want 200", resp.StatusCode)
+	}
+
+	// The open socket is closed, so the suspended user cannot keep sending
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				t.Fatal("suspended user's socket was not closed")
+			}
+			break
+		}
+	}
+	for deadline := time.Now().Add(2 * time.Second); hub.IsOnline(reported); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("suspended user is still registered with the hub")
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/admin"
+)
+
+// insertTestGroup creates an event and a group owned by ownerID, with a group
+// chat thread, and returns the group and thread IDs.
+func insertTestGroup(t *testing.T, ownerID string) (groupID, threadID string) {
+	t.Helper()
+	var eventID string
+	if err := testDB.QueryRow(`
+		INSERT INTO events (name, date, location, organizer, status)
+		VALUES ('Spring Fest', NOW() + INTERVAL '30 days', 'Kharagpur', 'IIT KGP', 'approved') RETURNING id
+	`).Scan(&eventID); err != nil {
+		t.Fatalf("failed to insert event: %v", err)
+	}
+	if err := testDB.QueryRow(`
+		INSERT INTO travel_groups (event_id, name, description, created_by) VALUES ($1, 'Team Alpha', 'Night train', $2) RETURNING id
+	`, eventID, ownerID).Scan(&groupID); err != nil {
+		t.Fatalf("failed to insert group: %v", err)
+	}
+	if _, err := testDB.Exec(`INSERT INTO group_members (group_id, user_id, role) VALUES ($1, $2, 'owner')`, groupID, ownerID); err != nil {
+		t.Fatalf("failed to insert member: %v", err)
+	}
+	threadID = insertTestThread(t, "group", ownerID)
+	if _, err := testDB.Exec(`UPDATE message_threads SET group_id = $2 WHERE id = $1`, threadID, groupID); err != nil {
+		t.Fatalf("failed to link thread: %v", err)
+	}
+	return groupID, threadID
+}
+
+func TestAdminRepository_DeleteGroupRemovesThread(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "reports", "messages", "message_threads", "group_members", "travel_groups", "events", "users")
+
+	repo := admin.NewRepository(testDB)
+	ctx := context.Background()
+	owner := insertTestUser(t, "owner@nitw.ac.in")
+	reporter := insertTestUser(t, "reporter@nitw.ac.in")
+	groupID, threadID := insertTestGroup(t, owner)
+	if _, err := testDB.Exec(`INSERT INTO messages (thread_id, sender_id, content) VALUES ($1, $2, 'hello')`, threadID, owner); err != nil {
+		t.Fatalf("failed to insert message: %v", err)
+	}
+
+	rep, err := repo.CreateReport(ctx, reporter, admin.CreateReportRequest{TargetType: admin.TargetGroup, TargetID: groupID, Category: "spam"})
+	if err != nil {
+		t.Fatalf("CreateReport: %v", err)
+	}
+	if _, err := repo.ResolveReport(ctx, rep.ID, admin.ResolveReportRequest{Action: admin.ActionDeleteContent}); err != nil {
+		t.Fatalf("ResolveReport: %v", err)
+	}
+
+	for table, query := range map[string]string{
+		"travel_groups":   `SELECT COUNT(*) FROM travel_groups WHERE id = $1`,
+		"message_threads": `SELECT COUNT(*) FROM message_threads WHERE id = $1`,
+		"messages":        `SELECT COUNT(*) FROM messages WHERE thread_id = $1`,
+	} {
+		id := threadID
+		if table == "travel_groups" {
+			id = groupID
+		}
+		var n int
+		if err := testDB.QueryRow(query, id).Scan(&n); err != nil || n != 0 {
+			t.Errorf("%s rows left after deleting the group: %d (%v)", table, n, err)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+)
+
+func TestGroupsRepository_DeleteGroupRemovesThread(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "messages", "message_threads", "group_members", "travel_groups", "events", "users")
+
+	repo := groups.NewRepository(testDB)
+	owner := insertTestUser(t, "owner@nitw.ac.in")
+	groupID, threadID := insertTestGroup(t, owner)
+
+	if err := repo.DeleteGroup(context.Background(), groupID); err != nil {
+		t.Fatalf("DeleteGroup: %v", err)
+	}
+	var n int
+	if err := testDB.QueryRow(`SELECT COUNT(*) FROM message_threads WHERE id = $1`, threadID).Scan(&n); err != nil || n != 0 {
+		t.Errorf("group thread left behind: %d (%v)", n, err)
+	}
+}
+
+func TestGroupsRepository_ConcurrentItineraryEdits(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "group_itinerary_legs", "group_itineraries", "message_threads", "group_members", "travel_groups", "events", "users")
+
+	repo := groups.NewRepository(testDB)
+	ctx := context.Background()
+	owner := insertTestUser(t, "owner@nitw.ac.in")
+	groupID, _ := insertTestGroup(t, owner)
+	departs := time.Date(2026, 11, 3, 6, 0, 0, 0, time.UTC)
+	leg := func(from string) groups.ItineraryLeg {
+		return groups.ItineraryLeg{
+			ID: uuid.NewString(), DepartureStation: from, DepartureTime: departs,
+			ArrivalStation: "Warangal", ArrivalTime: departs.Add(3 * time.Hour),
+		}
+	}
+
+	// Members adding legs at the same time must not overwrite each other
+	var wg sync.WaitGroup
+	for i := 0; i < groups.MaxItineraryLegs+2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.AddItineraryLeg(ctx, groupID, owner, leg("Secunderabad")); err != nil && err != groups.ErrTooManyLegs {
+				t.Errorf("AddItineraryLeg: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	it, err := repo.GetItinerary(ctx, groupID)
+	if err != nil || len(it.Legs) != groups.MaxItineraryLegs {
+		t.Fatalf("GetItinerary = %d legs, %v; want %d", len(it.Legs), err, groups.MaxItineraryLegs)
+	}
+
+	first, second := it.Legs[0], it.Legs[1]
+	first.DepartureStation = "Kazipet"
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if _, err := repo.UpdateItineraryLeg(ctx, groupID, owner, first); err != nil {
+			t.Errorf("UpdateItineraryLeg: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if _, err := repo.DeleteItineraryLeg(ctx, groupID, owner, second.ID); err != nil {
+			t.Errorf("DeleteItineraryLeg: %v", err)
+		}
+	}()
+	wg.Wait()
+
+	it, _ = repo.GetItinerary(ctx, groupID)
+	if len(it.Legs) != groups.MaxItineraryLegs-1 {
+		t.Errorf("after a delete there are %d legs, want %d", len(it.Legs), groups.MaxItineraryLegs-1)
+	}
+	for _, l := range it.Legs {
+		if l.ID == second.ID {
+			t.Error("the deleted leg came back")
+		}
+		if l.ID == first.ID && l.DepartureStation != "Kazipet" {
+			t.Errorf("the concurrent update was lost: %+v", l)
+		}
+	}
+	if _, err := repo.DeleteItineraryLeg(ctx, groupID, owner, second.ID); err != sql.ErrNoRows {
+		t.Errorf("deleting a missing leg: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestGroupsRepository_AcceptJoinRequestChecksCapacity(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "group_join_requests", "group_waitlist", "message_threads", "group_members", "travel_groups", "events", "users")
+
+	repo := groups.NewRepository(testDB)
+	ctx := context.Background()
+	owner := insertTestUser(t, "owner@nitw.ac.in")
+	groupID, _ := insertTestGroup(t, owner)
+	// One spot left for the owner's group, and it is held for a waitlisted user
+	if _, err := testDB.Exec(`UPDATE travel_groups SET max_members = 2, requires_approval = TRUE WHERE id = $1`, groupID); err != nil {
+		t.Fatalf("failed to update group: %v", err)
+	}
+	waiting := insertTestUser(t, "waiting@nitw.ac.in")
+	if _, err := testDB.Exec(`INSERT INTO group_waitlist (group_id, user_id, status, offer_expires_at) VALUES ($1, $2, 'offered', NOW() + INTERVAL '1 hour')`, groupID, waiting); err != nil {
+		t.Fatalf("failed to insert offer: %v", err)
+	}
+
+	var requesters []string
+	for _, email := range []string{"a@nitw.ac.in", "b@nitw.ac.in"} {
+		user := insertTestUser(t, email)
+		if err := repo.CreateJoinRequest(ctx, groupID, user); err != nil {
+			t.Fatalf("CreateJoinRequest: %v", err)
+		}
+		requesters = append(requesters, user)
+	}
+
+	if err := repo.AcceptJoinRequest(ctx, groupID, requesters[0]); err != groups.ErrGroupFull {
+		t.Fatalf("accepting while the last spot is offered: err = %v, want ErrGroupFull", err)
+	}
+
+	// Once the offer lapses the two accepts race for the one spot
+	if _, err := testDB.Exec(`DELETE FROM group_waitlist WHERE group_id = $1`, groupID); err != nil {
+		t.Fatalf("failed to clear waitlist: %v", err)
+	}
+	errs := make([]error, len(requesters))
+	var wg sync.WaitGroup
+	for i, user := range requesters {
+		wg.Add(1)
+		go func(i int, user string) {
+			defer wg.Done()
+			errs[i] = repo.AcceptJoinRequest(ctx, groupID, user)
+		}(i, user)
+	}
+	wg.Wait()
+
+	full := 0
+	for _, err := range errs {
+		if err == groups.ErrGroupFull {
+			full++
+		} else if err != nil {
+			t.Errorf("AcceptJoinRequest: %v", err)
+		}
+	}
+	if count, _ := repo.GetMemberCount(ctx, groupID); count != 2 || full != 1 {
+		t.Errorf("after concurrent accepts: %d members and %d ErrGroupFull, want 2 and 1", count, full)
+	}
+
+	if _, err := testDB.Exec(`UPDATE travel_groups SET max_members = 10, status = 'locked' WHERE id = $1`, groupID); err != nil {
+		t.Fatalf("failed to lock group: %v", err)
+	}
+	for _, user := range requesters {
+		if err := repo.AcceptJoinRequest(ctx, groupID, user); err != nil && err != groups.ErrGroupClosed && err != sql.ErrNoRows {
+			t.Errorf("AcceptJoinRequest: %v", err)
+		}
+	}
+	if count, _ := repo.GetMemberCount(ctx, groupID); count != 2 {
+		t.Errorf("accepting into a locked group added members: %d", count)
+	}
+}
+
+func TestGroupsRepository_GetMatchProfiles(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "user_interests", "interests", "profiles", "message_threads", "group_members", "travel_groups", "events", "users")
+
+	repo := groups.NewRepository(testDB)
+	ctx := context.Background()
+	alice := insertTestUser(t, "alice@nitw.ac.in")
+	bob := insertTestUser(t, "bob@nitw.ac.in")
+	if _, err := testDB.Exec(`
+		INSERT INTO profiles (user_id, full_name, college_name, major, roll_number, id_expiration, home_city)
+		VALUES ($1, 'Alice', 'NIT Warangal', 'CS', '21CS01', NOW() + INTERVAL '1 year', 'Hyderabad')
+	`, alice); err != nil {
+		t.Fatalf("failed to insert profile: %v", err)
+	}
+	if _, err := testDB.Exec(`INSERT INTO interests (name) VALUES ('AI'), ('Music')`); err != nil {
+		t.Fatalf("failed to insert interests: %v", err)
+	}
+	if _, err := testDB.Exec(`INSERT INTO user_interests (user_id, interest_id) SELECT $1, id FROM interests`, alice); err != nil {
+		t.Fatalf("failed to insert user interests: %v", err)
+	}
+
+	profiles, err := repo.GetMatchProfiles(ctx, []string{alice, bob})
+	if err != nil {
+		t.Fatalf("GetMatchProfiles: %v", err)
+	}
+	if p := profiles[alice]; p.College != "NIT Warangal" || p.HomeCity != "Hyderabad" || len(p.Interests) != 2 {
+		t.Errorf("alice = %+v, want her college, city and 2 interests", p)
+	}
+	if p, ok := profiles[bob]; !ok || p.UserID != bob || p.College != "" || len(p.Interests) != 0 {
+		t.Errorf("bob without a profile = %+v (present %v), want an empty profile", p, ok)
+	}
+
+	groupID, _ := insertTestGroup(t, alice)
+	insertTestGroup(t, bob)
+	members, err := repo.GetGroupMemberProfiles(ctx, groupID)
+	if err != nil || len(members) != 1 || members[0].UserID != alice || len(members[0].Interests) != 2 {
+		t.Errorf("GetGroupMemberProfiles = %+v, %v; want only alice with her interests", members, err)
+	}
+}
+
+func TestGroupsRepository_ConcurrentSettlementsCappedAtBalance(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "group_settlements", "group_expense_shares", "group_expenses", "message_threads", "group_members", "travel_groups", "events", "users")
+
+	repo := groups.NewRepository(testDB)
+	ctx := context.Background()
+	owner := insertTestUser(t, "owner@nitw.ac.in")
+	friend := insertTestUser(t, "friend@nitw.ac.in")
+	groupID, _ := insertTestGroup(t, owner)
+
+	// friend owes owner 400 INR
+	err := repo.CreateExpense(ctx, &groups.Expense{
+		GroupID: groupID, Description: "Cab", Amount: 800, Currency: "INR", PaidBy: owner,
+		SplitType: groups.SplitEqual, CreatedBy: owner,
+		Shares: []groups.ExpenseShare{{UserID: owner, Amount: 400}, {UserID: friend, Amount: 400}},
+	})
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+
+	// Marking the same debt as paid several times at once must not overshoot it
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.CreateSettlement(ctx, &groups.Settlement{
+				GroupID: groupID, FromUser: friend, ToUser: owner, Amount: 200, Currency: "INR", CreatedBy: owner,
+			})
+			if err != nil && err != groups.ErrSettlementExceedsBalance {
+				t.Errorf("CreateSettlement: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	settlements, err := repo.GetSettlements(ctx, groupID)
+	if err != nil || len(settlements) != 2 {
+		t.Errorf("GetSettlements = %d settlements, %v; want 2", len(settlements), err)
+	}
+}
+
+func TestGroupsRepository_ReleaseInviteKeepsClosedInvitesClosed(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "group_invites", "message_threads", "group_members", "travel_groups", "events", "users")
+
+	repo := groups.NewRepository(testDB)
+	ctx := context.Background()
+	owner := insertTestUser(t, "owner@nitw.ac.in")
+	invitee := insertTestUser(t, "invitee@nitw.ac.in")
+	groupID, _ := insertTestGroup(t, owner)
+
+	invite := &groups.Invite{
+		GroupID: groupID, Code: uuid.NewString()[:8], Kind: groups.InviteDirect, InviteeID: invitee,
+		CreatedBy: owner, ExpiresAt: time.Now().Add(groups.DefaultInviteTTL),
+	}
+	if err := repo.CreateInvite(ctx, invite); err != nil {
+		t.Fatalf("CreateInvite: %v", err)
+	}
+	status := func() string {
+		t.Helper()
+		var s string
+		if err := testDB.QueryRow(`SELECT status FROM group_invites WHERE id = $1`, invite.ID).Scan(&s); err != nil {
+			t.Fatalf("failed to read invite status: %v", err)
+		}
+		return s
+	}
+
+	// A failed join hands the accepted invite back
+	if err := repo.UseInvite(ctx, invite.ID); err != nil {
+		t.Fatalf("UseInvite: %v", err)
+	}
+	if err := repo.ReleaseInvite(ctx, invite.ID); err != nil || status() != groups.InviteActive {
+		t.Errorf("after release the invite is %q (%v), want active", status(), err)
+	}
+
+	// ...but not once it has been revoked in the meantime
+	if err := repo.UseInvite(ctx, invite.ID); err != nil {
+		t.Fatalf("UseInvite: %v", err)
+	}
+	if _, err := testDB.Exec(`UPDATE group_invites SET status = 'revoked' WHERE id = $1`, invite.ID); err != nil {
+		t.Fatalf("failed to revoke invite: %v", err)
+	}
+	if err := repo.ReleaseInvite(ctx, invite.ID); err != nil || status() != groups.InviteRevoked {
+		t.Errorf("after release the revoked invite is %q (%v), want revoked", status(), err)
+	}
+}
+
+func TestGroupsRepository_OwnershipChangesMoveCreatedBy(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "message_threads", "group_members", "travel_groups", "events", "users")
+
+	repo := groups.NewRepository(testDB)
+	ctx := context.Background()
+	owner := insertTestUser(t, "owner@nitw.ac.in")
+	admin := insertTestUser(t, "admin@nitw.ac.in")
+	member := insertTestUser(t, "member@nitw.ac.in")
+	groupID, _ := insertTestGroup(t, owner)
+	for _, uid := range []string{admin, member} {
+		if err := repo.JoinGroup(ctx, groupID, uid); err != nil {
+			t.Fatalf("JoinGroup: %v", err)
+		}
+	}
+	createdBy := func() string {
+		t.Helper()
+		g, err := repo.GetGroup(ctx, groupID)
+		if err != nil {
+			t.Fatalf("GetGroup: %v", err)
+		}
+		return g.CreatedBy
+	}
+
+	if err := repo.TransferOwnership(ctx, groupID, admin); err != nil {
+		t.Fatalf("TransferOwnership: %v", err)
+	}
+	if got := createdBy(); got != admin {
+		t.Errorf("after a transfer created_by = %s, want the new owner %s", got, admin)
+	}
+
+	// The owner leaving hands the group to the longest-standing member
+	if err := repo.RemoveMember(ctx, groupID, admin); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	if got := createdBy(); got != owner {
+		t.Errorf("after the owner left created_by = %s, want %s", got, owner)
+	}
+}
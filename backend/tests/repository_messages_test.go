@@ -0,0 +1,289 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// insertTestUser creates a bare user row and returns its ID.
+func insertTestUser(t *testing.T, email string) string {
+	t.Helper()
+	id := uuid.New().String()
+	if _, err := testDB.Exec(`INSERT INTO users (id, email, status) VALUES ($1, $2, 'active')`, id, email); err != nil {
+		t.Fatalf("failed to insert user %s: %v", email, err)
+	}
+	return id
+}
+
+// insertTestThread creates a thread with the given participants and returns its ID.
+func insertTestThread(t *testing.T, threadType string, participants ...string) string {
+	t.Helper()
+	var id string
+	if err := testDB.QueryRow(`INSERT INTO message_threads (type) VALUES ($1) RETURNING id`, threadType).Scan(&id); err != nil {
+		t.Fatalf("failed to insert thread: %v", err)
+	}
+	for _, uid := range participants {
+		if _, err := testDB.Exec(`INSERT INTO thread_participants (thread_id, user_id) VALUES ($1, $2)`, id, uid); err != nil {
+			t.Fatalf("failed to add participant: %v", err)
+		}
+	}
+	return id
+}
+
+func TestMessagesRepository_ReclaimsAbandonedScheduledMessages(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "scheduled_messages", "message_threads", "users")
+
+	repo := messages.NewRepository(testDB)
+	ctx := context.Background()
+	sender := insertTestUser(t, "scheduler@nitw.ac.in")
+	thread := insertTestThread(t, "direct", sender)
+
+	now := time.Now()
+	sm, err := repo.CreateScheduledMessage(ctx, messages.ScheduledMessage{
+		ThreadID: thread,
+		SenderID: sender,
+		Content:  "Bus leaves at 6am",
+		SendAt:   now.Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("CreateScheduledMessage: %v", err)
+	}
+
+	claimed, err := repo.ClaimDueScheduledMessages(ctx, now, 10)
+	if err != nil || len(claimed) != 1 || claimed[0].ID != sm.ID {
+		t.Fatalf("first claim = %v, %v; want the due message", claimed, err)
+	}
+
+	// The dispatcher dies here without completing the message
+	if again, _ := repo.ClaimDueScheduledMessages(ctx, now.Add(time.Minute), 10); len(again) != 0 {
+		t.Errorf("claim within the timeout returned %d messages, want 0", len(again))
+	}
+	again, err := repo.ClaimDueScheduledMessages(ctx, now.Add(messages.ScheduledClaimTimeout+time.Second), 10)
+	if err != nil || len(again) != 1 || again[0].ID != sm.ID {
+		t.Fatalf("claim after the timeout = %v, %v; want the abandoned message", again, err)
+	}
+
+	msgID := uuid.New().String()
+	if _, err := testDB.Exec(`INSERT INTO messages (id, thread_id, sender_id, content) VALUES ($1, $2, $3, $4)`, msgID, thread, sender, sm.Content); err != nil {
+		t.Fatalf("failed to insert message: %v", err)
+	}
+	if err := repo.CompleteScheduledMessage(ctx, sm.ID, &msgID, nil); err != nil {
+		t.Fatalf("CompleteScheduledMessage: %v", err)
+	}
+	if late, _ := repo.ClaimDueScheduledMessages(ctx, now.Add(time.Hour), 10); len(late) != 0 {
+		t.Errorf("a sent message was claimed again: %v", late)
+	}
+}
+
+func TestMessagesRepository_HidesExpiredMessages(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "pinned_messages", "message_mentions", "messages", "message_threads", "users")
+
+	repo := messages.NewRepository(testDB)
+	ctx := context.Background()
+	alice := insertTestUser(t, "alice@nitw.ac.in")
+	bob := insertTestUser(t, "bob@nitw.ac.in")
+	thread := insertTestThread(t, "direct", alice, bob)
+
+	insert := func(content string, expiresAt time.Time, replyTo *string) string {
+		t.Helper()
+		var id string
+		err := testDB.QueryRow(`
+			INSERT INTO messages (thread_id, sender_id, content, reply_to_id, expires_at)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id
+		`, thread, alice, content, replyTo, expiresAt).Scan(&id)
+		if err != nil {
+			t.Fatalf("failed to insert message: %v", err)
+		}
+		if _, err := testDB.Exec(`INSERT INTO message_mentions (message_id, user_id, start_offset, length) VALUES ($1, $2, 0, 4)`, id, bob); err != nil {
+			t.Fatalf("failed to insert mention: %v", err)
+		}
+		return id
+	}
+	expired := insert("@Bob meet at platform seven", time.Now().Add(-time.Minute), nil)
+	reply := insert("@Bob platform seven it is", time.Now().Add(time.Hour), &expired)
+
+	if _, err := repo.GetMessage(ctx, expired, bob); err != sql.ErrNoRows {
+		t.Errorf("GetMessage on an expired message: err = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := repo.GetReplyTree(ctx, thread, expired, bob); err != sql.ErrNoRows {
+		t.Errorf("GetReplyTree rooted at an expired message: err = %v, want sql.ErrNoRows", err)
+	}
+	if tree, err := repo.GetReplyTree(ctx, thread, reply, bob); err != nil || len(tree) != 1 {
+		t.Errorf("GetReplyTree rooted at the live reply = %d messages, %v; want 1", len(tree), err)
+	} else if tree[0].ExpiresAt == nil {
+		t.Error("GetReplyTree should return expires_at like GetMessages")
+	}
+
+	results, err := repo.SearchMessages(ctx, bob, "platform seven", nil, 10)
+	if err != nil || len(results) != 1 || results[0].MessageID != reply {
+		t.Errorf("SearchMessages = %+v, %v; want only the live reply", results, err)
+	}
+	mentions, err := repo.ListMentions(ctx, bob, nil, 10)
+	if err != nil || len(mentions) != 1 || mentions[0].ID != reply {
+		t.Errorf("ListMentions = %+v, %v; want only the live reply", mentions, err)
+	}
+
+	// A pin made before the message expired must not keep its content visible
+	if _, err := testDB.Exec(`INSERT INTO pinned_messages (thread_id, message_id, pinned_by) VALUES ($1, $2, $3)`, thread, expired, alice); err != nil {
+		t.Fatalf("failed to pin message: %v", err)
+	}
+	if pins, err := repo.GetPinnedMessages(ctx, thread); err != nil || len(pins) != 0 {
+		t.Errorf("GetPinnedMessages = %+v, %v; want no pins", pins, err)
+	}
+	if err := repo.PinMessage(ctx, thread, expired, alice); err != messages.ErrMessageNotInThread {
+		t.Errorf("PinMessage on an expired message: err = %v, want ErrMessageNotInThread", err)
+	}
+
+	threads, err := repo.ListUserThreads(ctx, bob)
+	if err != nil || len(threads) != 1 {
+		t.Fatalf("ListUserThreads = %+v, %v; want one thread", threads, err)
+	}
+	if threads[0].PinnedMessageID != nil {
+		t.Errorf("ListUserThreads pinned message = %v, want none", *threads[0].PinnedMessageID)
+	}
+	if threads[0].UnreadCount != 1 {
+		t.Errorf("ListUserThreads unread count = %d, want 1", threads[0].UnreadCount)
+	}
+
+	msgs, err := repo.GetMessages(ctx, thread, bob, messages.MessageQuery{Limit: 10})
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("GetMessages = %+v, %v; want only the live reply", msgs, err)
+	}
+	if msgs[0].ReplyToContent != nil {
+		t.Errorf("reply preview = %q, want none for an expired message", *msgs[0].ReplyToContent)
+	}
+	if tree, err := repo.GetReplyTree(ctx, thread, reply, bob); err == nil && len(tree) == 1 && tree[0].ReplyToContent != nil {
+		t.Errorf("GetReplyTree reply preview = %q, want none for an expired message", *tree[0].ReplyToContent)
+	}
+}
+
+func TestMessagesRepository_OpenMessageRequestEnforcesQuota(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "message_requests", "message_threads", "users")
+
+	repo := messages.NewRepository(testDB)
+	ctx := context.Background()
+	sender := insertTestUser(t, "sender@nitw.ac.in")
+
+	// Concurrent requests must not go past the daily quota
+	errs := make(chan error, messages.DailyRequestQuota+5)
+	for i := 0; i < cap(errs); i++ {
+		recipient := insertTestUser(t, uuid.New().String()+"@nitw.ac.in")
+		go func() {
+			_, err := repo.OpenMessageRequest(ctx, sender, recipient)
+			errs <- err
+		}()
+	}
+	var recorded, rejected int
+	for i := 0; i < cap(errs); i++ {
+		switch err := <-errs; err {
+		case nil:
+			recorded++
+		case messages.ErrRequestQuotaExceeded:
+			rejected++
+		default:
+			t.Fatalf("OpenMessageRequest: %v", err)
+		}
+	}
+	if recorded != messages.DailyRequestQuota || rejected != 5 {
+		t.Errorf("recorded %d and rejected %d requests, want %d and 5", recorded, rejected, messages.DailyRequestQuota)
+	}
+
+	// Rejected requests leave no empty request thread in either inbox
+	var threads int
+	if err := testDB.QueryRow(`SELECT COUNT(*) FROM message_threads WHERE is_request`).Scan(&threads); err != nil {
+		t.Fatalf("failed to count threads: %v", err)
+	}
+	if threads != messages.DailyRequestQuota {
+		t.Errorf("%d request threads exist, want %d", threads, messages.DailyRequestQuota)
+	}
+}
+
+func TestMessagesRepository_OpenMessageRequestReusesPendingThread(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "message_requests", "message_threads", "users")
+
+	repo := messages.NewRepository(testDB)
+	ctx := context.Background()
+	sender := insertTestUser(t, "sender@nitw.ac.in")
+	recipient := insertTestUser(t, "recipient@nitw.ac.in")
+
+	first, err := repo.OpenMessageRequest(ctx, sender, recipient)
+	if err != nil {
+		t.Fatalf("OpenMessageRequest: %v", err)
+	}
+	again, err := repo.OpenMessageRequest(ctx, sender, recipient)
+	if err != nil || again.ID != first.ID {
+		t.Errorf("repeat OpenMessageRequest = %q, %v; want the pending thread %q", again.ID, err, first.ID)
+	}
+	stats, err := repo.GetRequestStats(ctx, sender, time.Now())
+	if err != nil || stats.SentToday != 1 {
+		t.Errorf("GetRequestStats = %+v, %v; want one request sent", stats, err)
+	}
+}
+
+func TestMessagesRepository_IsRequestRecipient(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "message_requests", "message_threads", "users")
+
+	repo := messages.NewRepository(testDB)
+	ctx := context.Background()
+	sender := insertTestUser(t, "sender@nitw.ac.in")
+	recipient := insertTestUser(t, "recipient@nitw.ac.in")
+
+	thread, err := repo.OpenMessageRequest(ctx, sender, recipient)
+	if err != nil {
+		t.Fatalf("OpenMessageRequest: %v", err)
+	}
+	if ok, err := repo.IsRequestRecipient(ctx, thread.ID, recipient); err != nil || !ok {
+		t.Errorf("IsRequestRecipient(recipient) = %v, %v; want true", ok, err)
+	}
+	if ok, err := repo.IsRequestRecipient(ctx, thread.ID, sender); err != nil || ok {
+		t.Errorf("IsRequestRecipient(sender) = %v, %v; want false", ok, err)
+	}
+}
+
+func TestMessagesRepository_ReplyTreeIncludesMetadata(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+	clearTables(t, "messages", "message_threads", "users")
+
+	repo := messages.NewRepository(testDB)
+	alice := insertTestUser(t, "alice@nitw.ac.in")
+	thread := insertTestThread(t, "group", alice)
+
+	var id string
+	err := testDB.QueryRow(`
+		INSERT INTO messages (thread_id, sender_id, content, kind, metadata)
+		VALUES ($1, $2, '', 'group_renamed', '{"old_name": "Team A", "name": "Team Alpha"}') RETURNING id
+	`, thread, alice).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to insert message: %v", err)
+	}
+
+	tree, err := repo.GetReplyTree(context.Background(), thread, id, alice)
+	if err != nil || len(tree) != 1 {
+		t.Fatalf("GetReplyTree = %d messages, %v; want 1", len(tree), err)
+	}
+	if tree[0].Kind != "group_renamed" || tree[0].Metadata["name"] != "Team Alpha" {
+		t.Errorf("GetReplyTree returned kind %q metadata %v, want the rename details", tree[0].Kind, tree[0].Metadata)
+	}
+}
@@ -60,3 +60,51 @@ func TestProfileRepository_UpsertAndGet(t *testing.T) {
 }
 
 
+
+func TestProfileRepository_UpsertKeepsOmittedMatchingFields(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping integration test: DB not connected")
+	}
+
+	repo := profile.NewRepository(testDB)
+	ctx := context.Background()
+	clearTables(t, "user_interests", "profiles", "users", "interests")
+	userID := insertTestUser(t, "matching_fields@nitw.ac.in")
+
+	gender, city := "female", "Hyderabad"
+	req := profile.UpdateProfileRequest{
+		FullName:    "Test Student",
+		CollegeName: "NIT Warangal",
+		Major:       "CSE",
+		RollNumber:  "123456",
+		Gender:      &gender,
+		HomeCity:    &city,
+	}
+	if err := repo.UpsertProfile(ctx, userID, req); err != nil {
+		t.Fatalf("Failed to upsert profile: %v", err)
+	}
+
+	// An older client that does not know about the fields leaves them out
+	req.Gender, req.HomeCity = nil, nil
+	req.Bio = "Updated bio"
+	if err := repo.UpsertProfile(ctx, userID, req); err != nil {
+		t.Fatalf("Failed to upsert profile: %v", err)
+	}
+	p, err := repo.GetProfile(ctx, userID)
+	if err != nil {
+		t.Fatalf("Failed to get profile: %v", err)
+	}
+	if p.Gender != gender || p.HomeCity != city {
+		t.Errorf("gender %q and home_city %q were overwritten by an update that omitted them", p.Gender, p.HomeCity)
+	}
+
+	// An empty string clears the field
+	empty := ""
+	req.HomeCity = &empty
+	if err := repo.UpsertProfile(ctx, userID, req); err != nil {
+		t.Fatalf("Failed to upsert profile: %v", err)
+	}
+	if p, _ = repo.GetProfile(ctx, userID); p.HomeCity != "" || p.Gender != gender {
+		t.Errorf("after clearing home_city got gender %q home_city %q", p.Gender, p.HomeCity)
+	}
+}
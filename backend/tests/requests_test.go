@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+func postConnect(t *testing.T, mockRepo *MockMessagesRepository, message string) *httptest.ResponseRecorder {
+	t.Helper()
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]string{"message": message})
+	req, _ := http.NewRequest("POST", "/users/user-2/connect", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRequestStats_Throttling(t *testing.T) {
+	tests := []struct {
+		name      string
+		stats     messages.RequestStats
+		throttled bool
+		remaining int
+	}{
+		{"fresh sender", messages.RequestStats{}, false, messages.DailyRequestQuota},
+		{"few responses", messages.RequestStats{SentToday: 3, Responded: 3, Declined: 3}, false, messages.DailyRequestQuota - 3},
+		{"mostly declined", messages.RequestStats{SentToday: 1, Responded: 10, Declined: 7}, true, messages.ThrottledRequestQuota - 1},
+		{"mostly accepted", messages.RequestStats{Responded: 10, Declined: 2}, false, messages.DailyRequestQuota},
+		{"over quota", messages.RequestStats{SentToday: 12}, false, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.stats.Throttled(); got != tt.throttled {
+			t.Errorf("%s: Throttled() = %v, want %v", tt.name, got, tt.throttled)
+		}
+		if got := tt.stats.Remaining(); got != tt.remaining {
+			t.Errorf("%s: Remaining() = %d, want %d", tt.name, got, tt.remaining)
+		}
+	}
+}
+
+func TestConnectUser_QuotaExceeded(t *testing.T) {
+	created := false
+	mockRepo := &MockMessagesRepository{
+		GetRequestStatsFunc: func(ctx context.Context, senderID string, now time.Time) (messages.RequestStats, error) {
+			return messages.RequestStats{SentToday: messages.DailyRequestQuota}, nil
+		},
+		OpenMessageRequestFunc: func(ctx context.Context, senderID, recipientID string) (messages.Thread, error) {
+			created = true
+			return messages.Thread{ID: "t1", IsRequest: true}, nil
+		},
+	}
+	rr := postConnect(t, mockRepo, "hi, travelling to the fest?")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Errorf("over quota: got %d, want 429", rr.Code)
+	}
+	if created {
+		t.Error("request thread should not be created over quota")
+	}
+}
+
+func TestConnectUser_Blocked(t *testing.T) {
+	mockRepo := &MockMessagesRepository{
+		IsBlockedFunc: func(ctx context.Context, userID1, userID2 string) (bool, error) {
+			return true, nil
+		},
+	}
+	rr := postConnect(t, mockRepo, "hello again")
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("blocked: got %d, want 403", rr.Code)
+	}
+}
+
+func TestConnectUser_RecordsRequest(t *testing.T) {
+	var recorded []string
+	var sentIn string
+	mockRepo := &MockMessagesRepository{
+		OpenMessageRequestFunc: func(ctx context.Context, senderID, recipientID string) (messages.Thread, error) {
+			recorded = []string{senderID, recipientID}
+			return messages.Thread{ID: "t1", Type: "direct", IsRequest: true}, nil
+		},
+		CreateMessageFunc: func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error) {
+			sentIn = threadID
+			return messages.Message{ID: "m1", ThreadID: threadID}, nil
+		},
+	}
+	rr := postConnect(t, mockRepo, "hi, travelling to the fest?")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("connect: got %d, want 201", rr.Code)
+	}
+	if len(recorded) != 2 || recorded[0] != "user-1" || recorded[1] != "user-2" {
+		t.Errorf("recorded request = %v, want [user-1 user-2]", recorded)
+	}
+	if sentIn != "t1" {
+		t.Errorf("first message sent in %q, want the request thread t1", sentIn)
+	}
+}
+
+func TestConnectUser_QuotaExceededWhileRecording(t *testing.T) {
+	// Another request from the same sender used up the quota after the early check
+	mockRepo := &MockMessagesRepository{
+		OpenMessageRequestFunc: func(ctx context.Context, senderID, recipientID string) (messages.Thread, error) {
+			return messages.Thread{}, messages.ErrRequestQuotaExceeded
+		},
+		CreateMessageFunc: func(ctx context.Context, threadID, senderID, content string, replyToID *string, isForwarded bool) (messages.Message, error) {
+			t.Error("no message should be sent over quota")
+			return messages.Message{}, nil
+		},
+	}
+	rr := postConnect(t, mockRepo, "hi, travelling to the fest?")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Errorf("over quota: got %d, want 429", rr.Code)
+	}
+}
+
+func TestListRequests_FiltersIncoming(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		ListUserThreadsFunc: func(ctx context.Context, userID string) ([]messages.ThreadSummary, error) {
+			return []messages.ThreadSummary{
+				{ID: "incoming", Type: "direct", IsRequest: true},
+				{ID: "outgoing", Type: "direct", IsRequest: true, IsRequester: true},
+				{ID: "chat", Type: "direct"},
+			}, nil
+		},
+		GetRequestStatsFunc: func(ctx context.Context, senderID string, now time.Time) (messages.RequestStats, error) {
+			return messages.RequestStats{SentToday: 4}, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+
+	req, _ := http.NewRequest("GET", "/messages/requests", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /messages/requests: got %d, want 200", rr.Code)
+	}
+
+	var inbox messages.RequestInbox
+	json.NewDecoder(rr.Body).Decode(&inbox)
+	if len(inbox.Requests) != 1 || inbox.Requests[0].ID != "incoming" {
+		t.Errorf("requests = %+v, want only the incoming request", inbox.Requests)
+	}
+	if inbox.Quota.Remaining != messages.DailyRequestQuota-4 {
+		t.Errorf("quota remaining = %d, want %d", inbox.Quota.Remaining, messages.DailyRequestQuota-4)
+	}
+
+	req, _ = http.NewRequest("GET", "/messages/requests?direction=sent", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	inbox = messages.RequestInbox{}
+	json.NewDecoder(rr.Body).Decode(&inbox)
+	if len(inbox.Requests) != 1 || inbox.Requests[0].ID != "outgoing" {
+		t.Errorf("sent requests = %+v, want only the outgoing request", inbox.Requests)
+	}
+}
+
+func TestDeclineRequest_AndBlock(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	var blocked bool
+	mockRepo := &MockMessagesRepository{
+		DeclineRequestFunc: func(ctx context.Context, threadID, userID string, block bool) error {
+			blocked = block
+			return nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("POST", "/messages/threads/t1/decline", bytes.NewBufferString(`{"block": true}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /messages/threads/{id}/decline: got %d, want 200", rr.Code)
+	}
+	if !blocked {
+		t.Error("expected the sender to be blocked")
+	}
+}
+
+func TestRespondToRequest_OnlyRecipient(t *testing.T) {
+	// The sender is a participant too, but must not clear their own request
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	for _, action := range []string{"accept", "decline"} {
+		mockRepo := &MockMessagesRepository{
+			IsRequestRecipientFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+				return userID == "user-2", nil
+			},
+			AcceptRequestFunc: func(ctx context.Context, threadID, userID string) error {
+				t.Error("sender should not be able to accept their own request")
+				return nil
+			},
+			DeclineRequestFunc: func(ctx context.Context, threadID, userID string, block bool) error {
+				t.Error("sender should not be able to decline their own request")
+				return nil
+			},
+		}
+		router := newMsgRouter(t, mockRepo)
+		req, _ := http.NewRequest("POST", "/messages/threads/t1/"+action, bytes.NewBufferString(`{"block": true}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("POST /messages/threads/{id}/%s as the sender: got %d, want 403", action, rr.Code)
+		}
+	}
+}
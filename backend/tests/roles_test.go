@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// rolesRepo is a groups repo whose members have the given roles.
+func rolesRepo(roles map[string]string) *MockGroupsRepositoryFull {
+	return &MockGroupsRepositoryFull{
+		GetMemberRoleFunc: func(ctx context.Context, groupID, userID string) (string, error) {
+			if role, ok := roles[userID]; ok {
+				return role, nil
+			}
+			return "", sql.ErrNoRows
+		},
+		IsGroupMemberFunc: func(ctx context.Context, groupID, userID string) (bool, error) {
+			_, ok := roles[userID]
+			return ok, nil
+		},
+		GetMemberCountFunc: func(ctx context.Context, groupID string) (int, error) {
+			return len(roles), nil
+		},
+	}
+}
+
+func TestCanRemove(t *testing.T) {
+	tests := []struct {
+		role, target string
+		want         bool
+	}{
+		{groups.RoleOwner, groups.RoleAdmin, true},
+		{groups.RoleOwner, groups.RoleMember, true},
+		{groups.RoleAdmin, groups.RoleMember, true},
+		{groups.RoleAdmin, groups.RoleAdmin, false},
+		{groups.RoleAdmin, groups.RoleOwner, false},
+		{groups.RoleMember, groups.RoleMember, false},
+	}
+	for _, tt := range tests {
+		if got := groups.CanRemove(tt.role, tt.target); got != tt.want {
+			t.Errorf("CanRemove(%s, %s) = %v, want %v", tt.role, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestKickMember_Roles(t *testing.T) {
+	roles := map[string]string{"owner": groups.RoleOwner, "admin": groups.RoleAdmin, "admin2": groups.RoleAdmin, "member": groups.RoleMember}
+	tests := []struct {
+		actor, target string
+		code          int
+	}{
+		{"admin", "member", http.StatusOK},
+		{"admin", "admin2", http.StatusForbidden},
+		{"admin", "owner", http.StatusForbidden},
+		{"member", "admin", http.StatusForbidden},
+		{"owner", "admin", http.StatusOK},
+	}
+	for _, tt := range tests {
+		removed := ""
+		repo := rolesRepo(roles)
+		repo.RemoveMemberFunc = func(ctx context.Context, groupID, userID string) error {
+			removed = userID
+			return nil
+		}
+		router, _ := newSystemMessageRouter(t, repo)
+
+		rr := doItinerary(t, router, tt.actor, "POST", "/groups/g1/kick", map[string]string{"user_id": tt.target})
+		if rr.Code != tt.code {
+			t.Errorf("%s kicking %s: got %d, want %d", tt.actor, tt.target, rr.Code, tt.code)
+		}
+		if (tt.code == http.StatusOK) != (removed == tt.target) {
+			t.Errorf("%s kicking %s: removed %q", tt.actor, tt.target, removed)
+		}
+	}
+}
+
+func TestUpdateAndDeleteGroup_Admin(t *testing.T) {
+	deleted := false
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner, "admin": groups.RoleAdmin})
+	repo.DeleteGroupFunc = func(ctx context.Context, groupID string) error {
+		deleted = true
+		return nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "admin", "PUT", "/groups/g1", map[string]string{"name": "Renamed"}); rr.Code != http.StatusOK {
+		t.Errorf("admin updating group: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if rr := doItinerary(t, router, "admin", "DELETE", "/groups/g1", nil); rr.Code != http.StatusForbidden || deleted {
+		t.Errorf("admin deleting group: got %d (deleted=%v), want 403", rr.Code, deleted)
+	}
+}
+
+func TestPromoteAndDemote(t *testing.T) {
+	var changed []string
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner, "admin": groups.RoleAdmin, "member": groups.RoleMember})
+	repo.SetMemberRoleFunc = func(ctx context.Context, groupID, userID, role string) error {
+		changed = append(changed, userID+"="+role)
+		return nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "admin", "POST", "/groups/g1/members/member/promote", nil); rr.Code != http.StatusForbidden {
+		t.Errorf("admin promoting: got %d, want 403", rr.Code)
+	}
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/members/stranger/promote", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("promoting a non-member: got %d, want 404", rr.Code)
+	}
+	for _, path := range []string{"/groups/g1/members/member/promote", "/groups/g1/members/admin/demote", "/groups/g1/members/admin/promote"} {
+		if rr := doItinerary(t, router, "owner", "POST", path, nil); rr.Code != http.StatusOK {
+			t.Errorf("POST %s: got %d, want 200", path, rr.Code)
+		}
+	}
+
+	// Promoting someone who is already an admin changes nothing
+	if len(changed) != 2 || changed[0] != "member=admin" || changed[1] != "admin=member" {
+		t.Errorf("role changes = %v, want [member=admin admin=member]", changed)
+	}
+	if len(*posted) != 2 || (*posted)[0].kind != messages.KindRoleChanged || (*posted)[0].metadata["role"] != groups.RoleAdmin {
+		t.Errorf("system messages = %+v, want two role_changed", *posted)
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	newOwner := ""
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember})
+	repo.TransferOwnershipFunc = func(ctx context.Context, groupID, toUserID string) error {
+		if toUserID != "member" {
+			return sql.ErrNoRows
+		}
+		newOwner = toUserID
+		return nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "member", "POST", "/groups/g1/transfer", map[string]string{"user_id": "member"}); rr.Code != http.StatusForbidden {
+		t.Errorf("non-owner transferring: got %d, want 403", rr.Code)
+	}
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/transfer", map[string]string{"user_id": "stranger"}); rr.Code != http.StatusNotFound {
+		t.Errorf("transferring to a non-member: got %d, want 404", rr.Code)
+	}
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/transfer", map[string]string{"user_id": "member"}); rr.Code != http.StatusOK {
+		t.Fatalf("transferring: got %d, want 200", rr.Code)
+	}
+	if newOwner != "member" || len(*posted) != 1 || (*posted)[0].metadata["previous_owner"] != "owner" {
+		t.Errorf("new owner = %q, system messages = %+v", newOwner, *posted)
+	}
+}
+
+func TestLeaveGroup_OwnerHandsOver(t *testing.T) {
+	removed := ""
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner, "early": groups.RoleMember, "late": groups.RoleMember})
+	repo.RemoveMemberFunc = func(ctx context.Context, groupID, userID string) error {
+		removed = userID
+		return nil
+	}
+	repo.GetGroupMembersFunc = func(ctx context.Context, groupID string) ([]groups.GroupMemberProfile, error) {
+		return []groups.GroupMemberProfile{{UserID: "early", Role: groups.RoleOwner}, {UserID: "late", Role: groups.RoleMember}}, nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	rr := doItinerary(t, router, "owner", "POST", "/groups/g1/leave", nil)
+	if rr.Code != http.StatusOK || removed != "owner" {
+		t.Fatalf("owner leaving: got %d (removed %q), want 200", rr.Code, removed)
+	}
+	if len(*posted) != 2 || (*posted)[0].kind != messages.KindMemberLeft || (*posted)[1].kind != messages.KindRoleChanged || (*posted)[1].metadata["user_id"] != "early" {
+		t.Errorf("system messages = %+v, want member_left then role_changed for early", *posted)
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/server"
+)
+
+func place(city string, lat, lng float64) *groups.Place {
+	return &groups.Place{City: city, Lat: &lat, Lng: &lng}
+}
+
+func newRouteRouter(t *testing.T, repo groups.Repository) http.Handler {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "testsecret")
+	return server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
+		&MockEventsRepository{}, repo,
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
+	)
+}
+
+func TestHaversineKm(t *testing.T) {
+	// Hyderabad to Warangal is about 136 km as the crow flies
+	d := groups.HaversineKm(17.3850, 78.4867, 17.9689, 79.5941)
+	if math.Abs(d-136) > 3 {
+		t.Errorf("Hyderabad-Warangal = %.1f km, want ~136", d)
+	}
+	if d := groups.HaversineKm(17.3850, 78.4867, 17.3850, 78.4867); d != 0 {
+		t.Errorf("same point = %.3f km, want 0", d)
+	}
+}
+
+func TestListAllGroups_FiltersByOrigin(t *testing.T) {
+	repo := &MockGroupsRepositoryFull{
+		SearchGroupsFunc: func(ctx context.Context, userID string, search groups.GroupSearch) ([]groups.GroupWithDetails, error) {
+			return []groups.GroupWithDetails{
+				{Group: groups.Group{ID: "pune", Origin: place("Pune", 18.5204, 73.8567)}},
+				{Group: groups.Group{ID: "secunderabad", Origin: place("Secunderabad", 17.4399, 78.4983)}},
+				{Group: groups.Group{ID: "hyd-no-coords", Origin: &groups.Place{City: "hyderabad"}}},
+				{Group: groups.Group{ID: "hyd-centre", Origin: place("Hyderabad", 17.3850, 78.4867)}},
+				{Group: groups.Group{ID: "no-origin"}},
+			}, nil
+		},
+	}
+	router := newRouteRouter(t, repo)
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+
+	req, _ := http.NewRequest("GET", "/groups?origin=Hyderabad&origin_lat=17.385&origin_lng=78.4867&radius_km=20", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /groups with origin: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+
+	var page groups.GroupPage
+	json.NewDecoder(rr.Body).Decode(&page)
+	result := page.Groups
+	var ids []string
+	for _, g := range result {
+		ids = append(ids, g.ID)
+	}
+	want := []string{"hyd-centre", "secunderabad", "hyd-no-coords"}
+	if len(ids) != len(want) {
+		t.Fatalf("groups = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("groups = %v, want %v", ids, want)
+		}
+	}
+	if result[0].DistanceKm == nil || *result[0].DistanceKm != 0 || result[2].DistanceKm != nil {
+		t.Errorf("distance_km not set as expected: %v, %v", result[0].DistanceKm, result[2].DistanceKm)
+	}
+}
+
+func TestListAllGroups_InvalidOrigin(t *testing.T) {
+	router := newRouteRouter(t, &MockGroupsRepositoryFull{})
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+
+	for _, query := range []string{"?origin_lat=17.3", "?origin_lat=abc&origin_lng=78", "?origin_lat=95&origin_lng=78", "?radius_km=10", "?origin=Pune&radius_km=10"} {
+		req, _ := http.NewRequest("GET", "/groups"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("GET /groups%s: got %d, want 400", query, rr.Code)
+		}
+	}
+}
+
+func TestCreateGroup_Route(t *testing.T) {
+	var created *groups.Group
+	repo := &MockGroupsRepositoryFull{
+		CreateGroupFunc: func(ctx context.Context, group *groups.Group) error {
+			created = group
+			return nil
+		},
+	}
+	router := newRouteRouter(t, repo)
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+
+	tests := []struct {
+		name  string
+		route map[string]interface{}
+		code  int
+	}{
+		{"valid", map[string]interface{}{"origin": map[string]interface{}{"city": " Hyderabad ", "lat": 17.385, "lng": 78.4867}, "destination": map[string]interface{}{"city": "Warangal"}, "transport_mode": "train"}, http.StatusCreated},
+		{"unknown mode", map[string]interface{}{"transport_mode": "rocket"}, http.StatusBadRequest},
+		{"lat without lng", map[string]interface{}{"origin": map[string]interface{}{"city": "Hyderabad", "lat": 17.385}}, http.StatusBadRequest},
+		{"no city", map[string]interface{}{"destination": map[string]interface{}{"lat": 17.9, "lng": 79.5}}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		payload := map[string]interface{}{"event_id": "evt-1", "name": "Team Alpha"}
+		for k, v := range tt.route {
+			payload[k] = v
+		}
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", "/groups", bytes.NewBuffer(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		if rr.Code != tt.code {
+			t.Errorf("%s: got %d, want %d. Body: %s", tt.name, rr.Code, tt.code, rr.Body.String())
+		}
+	}
+
+	if created == nil || created.Origin == nil || created.Origin.City != "Hyderabad" || !created.Origin.HasCoords() ||
+		created.Destination.City != "Warangal" || created.TransportMode != groups.TransportTrain {
+		t.Errorf("created group route = %+v", created)
+	}
+}
+
+func TestWeightedScorer_OriginDistance(t *testing.T) {
+	scorer := groups.NewWeightedScorer(groups.DefaultWeights)
+	user := groups.MatchProfile{UserID: "me", Origin: place("Hyderabad", 17.3850, 78.4867)}
+
+	near := scorer.Score(user, groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4, Origin: place("Secunderabad", 17.4399, 78.4983)}}, nil)
+	far := scorer.Score(user, groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4, Origin: place("Pune", 18.5204, 73.8567)}}, nil)
+
+	nearOrigin, farOrigin := factorScore(near, groups.FactorOrigin), factorScore(far, groups.FactorOrigin)
+	if !nearOrigin.Applied || nearOrigin.Score < 0.9 {
+		t.Errorf("near origin = %+v, want a score above 0.9", nearOrigin)
+	}
+	if !farOrigin.Applied || farOrigin.Score != 0 {
+		t.Errorf("far origin = %+v, want applied with score 0", farOrigin)
+	}
+	if near.Score <= far.Score {
+		t.Errorf("near group scored %.3f, far group %.3f; want near ranked higher", near.Score, far.Score)
+	}
+}
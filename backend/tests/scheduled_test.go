@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+func TestScheduleMessage_SendAtInPast(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	router := newMsgRouter(t, &MockMessagesRepository{})
+	body, _ := json.Marshal(map[string]interface{}{
+		"thread_id": "thread-1",
+		"content":   "Reminder: bus leaves at 6am",
+		"send_at":   time.Now().Add(-time.Minute),
+	})
+	req, _ := http.NewRequest("POST", "/messages/schedule", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("POST /messages/schedule in the past: got %d, want 400", rr.Code)
+	}
+}
+
+func TestScheduleMessage_NotParticipant(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		IsParticipantFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			return false, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]interface{}{
+		"thread_id": "thread-1",
+		"content":   "Reminder: bus leaves at 6am",
+		"send_at":   time.Now().Add(time.Hour),
+	})
+	req, _ := http.NewRequest("POST", "/messages/schedule", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("POST /messages/schedule not participant: got %d, want 403", rr.Code)
+	}
+}
+
+func TestScheduleMessage_Success(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	sendAt := time.Now().Add(10 * time.Hour).UTC().Truncate(time.Second)
+	var created messages.ScheduledMessage
+	mockRepo := &MockMessagesRepository{
+		CreateScheduledMessageFunc: func(ctx context.Context, sm messages.ScheduledMessage) (messages.ScheduledMessage, error) {
+			created = sm
+			sm.ID = "sched-1"
+			sm.Status = messages.ScheduledPending
+			return sm, nil
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	body, _ := json.Marshal(map[string]interface{}{
+		"thread_id": "thread-1",
+		"content":   "Reminder: bus leaves at 6am",
+		"send_at":   sendAt,
+	})
+	req, _ := http.NewRequest("POST", "/messages/schedule", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST /messages/schedule: got %d, want 201. Body: %s", rr.Code, rr.Body.String())
+	}
+	if created.SenderID != "user-1" || !created.SendAt.Equal(sendAt) {
+		t.Errorf("unexpected scheduled message: %+v", created)
+	}
+}
+
+func TestCancelScheduledMessage_NotPending(t *testing.T) {
+	token, _ := auth.GenerateToken("user-1", "student@nitw.ac.in")
+	mockRepo := &MockMessagesRepository{
+		CancelScheduledMessageFunc: func(ctx context.Context, id, userID string) error {
+			return sql.ErrNoRows
+		},
+	}
+	router := newMsgRouter(t, mockRepo)
+	req, _ := http.NewRequest("DELETE", "/messages/scheduled/sched-1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("DELETE /messages/scheduled/{id} already sent: got %d, want 404", rr.Code)
+	}
+}
+
+func TestScheduler_DispatchDue(t *testing.T) {
+	outcomes := map[string]error{}
+	mockRepo := &MockMessagesRepository{
+		ClaimDueScheduledMessagesFunc: func(ctx context.Context, now time.Time, limit int) ([]messages.ScheduledMessage, error) {
+			return []messages.ScheduledMessage{
+				{ID: "sched-ok", ThreadID: "thread-1", SenderID: "user-1", Content: "Bus at 6am"},
+				{ID: "sched-left", ThreadID: "thread-2", SenderID: "user-1", Content: "Bus at 6am"},
+			}, nil
+		},
+		IsParticipantFunc: func(ctx context.Context, threadID, userID string) (bool, error) {
+			// The sender has since left thread-2
+			return threadID == "thread-1", nil
+		},
+		CompleteScheduledMessageFunc: func(ctx context.Context, id string, messageID *string, deliveryErr error) error {
+			outcomes[id] = deliveryErr
+			return nil
+		},
+	}
+	hub := messages.NewHub(mockRepo, nil, nil)
+	sent := messages.NewScheduler(mockRepo, hub).DispatchDue(context.Background())
+
+	if sent != 1 {
+		t.Errorf("expected 1 message delivered, got %d", sent)
+	}
+	if err, ok := outcomes["sched-ok"]; !ok || err != nil {
+		t.Errorf("sched-ok should be marked sent, got %v", err)
+	}
+	if outcomes["sched-left"] != messages.ErrNotParticipant {
+		t.Errorf("sched-left should fail with ErrNotParticipant, got %v", outcomes["sched-left"])
+	}
+}
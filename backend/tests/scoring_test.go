@@ -0,0 +1,301 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/server"
+)
+
+func factorScore(m groups.Match, f groups.Factor) groups.FactorScore {
+	for _, fs := range m.Breakdown {
+		if fs.Factor == f {
+			return fs
+		}
+	}
+	return groups.FactorScore{}
+}
+
+func TestWeightedScorer_Breakdown(t *testing.T) {
+	departs := time.Date(2026, 11, 3, 18, 0, 0, 0, time.UTC)
+	wants := time.Date(2026, 11, 4, 0, 0, 0, 0, time.UTC)
+	user := groups.MatchProfile{
+		UserID: "me", College: "NIT Warangal", HomeCity: "Hyderabad",
+		Interests: []string{"AI", "ML"}, DepartureDate: &wants,
+	}
+	group := groups.GroupWithDetails{
+		Group:       groups.Group{ID: "g1", MaxMembers: 4, DepartureDate: &departs},
+		MemberCount: 2,
+	}
+	members := []groups.MatchProfile{
+		{UserID: "a", College: "nit warangal", HomeCity: "Hyderabad", Interests: []string{"AI", "Music"}},
+		{UserID: "b", College: "IIT Bombay", HomeCity: "Pune", Interests: []string{"AI", "ML"}},
+	}
+
+	m := groups.NewWeightedScorer(groups.DefaultWeights).Score(user, group, members)
+	if !m.Eligible {
+		t.Fatalf("expected eligible, got reason %q", m.Reason)
+	}
+
+	// interests (0.5+1)/2, college 1/2, origin 1/2, departure 1 day of 3, fill 2/4
+	want := map[groups.Factor]float64{
+		groups.FactorInterests: 0.75,
+		groups.FactorCollege:   0.5,
+		groups.FactorOrigin:    0.5,
+		groups.FactorDeparture: 2.0 / 3,
+		groups.FactorFill:      0.5,
+	}
+	total := 0.0
+	for f, score := range want {
+		fs := factorScore(m, f)
+		if !fs.Applied || math.Abs(fs.Score-score) > 1e-9 {
+			t.Errorf("%s = %+v, want applied with score %.3f", f, fs, score)
+		}
+		total += groups.DefaultWeights[f] * score
+	}
+	if math.Abs(m.Score-total) > 1e-9 {
+		t.Errorf("score = %.4f, want %.4f", m.Score, total)
+	}
+	if len(m.SharedInterests) != 2 {
+		t.Errorf("shared interests = %v, want [AI ML]", m.SharedInterests)
+	}
+}
+
+func TestWeightedScorer_SkipsFactorsWithoutData(t *testing.T) {
+	user := groups.MatchProfile{UserID: "me", Interests: []string{"AI"}}
+	group := groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4}, MemberCount: 1}
+	members := []groups.MatchProfile{{UserID: "me"}, {UserID: "a", Interests: []string{"AI"}}}
+
+	m := groups.NewWeightedScorer(groups.DefaultWeights).Score(user, group, members)
+
+	for _, f := range []groups.Factor{groups.FactorCollege, groups.FactorOrigin, groups.FactorDeparture} {
+		if fs := factorScore(m, f); fs.Applied || fs.Contribution != 0 {
+			t.Errorf("%s should be skipped, got %+v", f, fs)
+		}
+	}
+	// Only interests (1.0) and fill (0.25) apply, reweighted over 0.35+0.1
+	want := (0.35*1 + 0.1*0.25) / 0.45
+	if math.Abs(m.Score-want) > 1e-9 {
+		t.Errorf("score = %.4f, want %.4f", m.Score, want)
+	}
+}
+
+func TestWeightedScorer_Eligibility(t *testing.T) {
+	scorer := groups.NewWeightedScorer(groups.DefaultWeights)
+	tests := []struct {
+		name     string
+		gender   string
+		group    groups.GroupWithDetails
+		eligible bool
+	}{
+		{"open group", "", groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4, GenderPreference: groups.GenderAny}, MemberCount: 1}, true},
+		{"full group", "", groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4}, MemberCount: 4}, false},
+		{"women only, woman", "female", groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4, GenderPreference: groups.GenderWomenOnly}, MemberCount: 1}, true},
+		{"women only, man", "male", groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4, GenderPreference: groups.GenderWomenOnly}, MemberCount: 1}, false},
+		{"men only, unset", "", groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4, GenderPreference: groups.GenderMenOnly}, MemberCount: 1}, false},
+	}
+	for _, tt := range tests {
+		m := scorer.Score(groups.MatchProfile{UserID: "me", Gender: tt.gender}, tt.group, nil)
+		if m.Eligible != tt.eligible {
+			t.Errorf("%s: eligible = %v (%s), want %v", tt.name, m.Eligible, m.Reason, tt.eligible)
+		}
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	w, err := groups.ParseWeights("interests=0.6, fill=0")
+	if err != nil {
+		t.Fatalf("ParseWeights: %v", err)
+	}
+	if w[groups.FactorInterests] != 0.6 || w[groups.FactorFill] != 0 || w[groups.FactorCollege] != groups.DefaultWeights[groups.FactorCollege] {
+		t.Errorf("weights = %v, want interests/fill overridden and the rest defaulted", w)
+	}
+	if groups.DefaultWeights[groups.FactorInterests] != 0.35 {
+		t.Error("ParseWeights must not modify DefaultWeights")
+	}
+	for _, bad := range []string{"karma=1", "interests", "origin=-1", "fill=abc"} {
+		if _, err := groups.ParseWeights(bad); err == nil {
+			t.Errorf("ParseWeights(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSuggestedGroups_RanksWithBreakdown(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+
+	mockGroupsRepo := &MockGroupsRepositoryFull{
+		GetMatchProfileFunc: func(ctx context.Context, userID string) (groups.MatchProfile, error) {
+			return groups.MatchProfile{UserID: userID, College: "NIT Warangal", Gender: "male", Interests: []string{"AI"}}, nil
+		},
+		GetGroupsWithCountsForEventFunc: func(ctx context.Context, eventID string) ([]groups.GroupWithDetails, error) {
+			return []groups.GroupWithDetails{
+				{Group: groups.Group{ID: "weak", MaxMembers: 4, GenderPreference: groups.GenderAny}, MemberCount: 1},
+				{Group: groups.Group{ID: "strong", MaxMembers: 4, GenderPreference: groups.GenderAny}, MemberCount: 1},
+				{Group: groups.Group{ID: "women", MaxMembers: 4, GenderPreference: groups.GenderWomenOnly}, MemberCount: 1},
+			}, nil
+		},
+		GetGroupMemberProfilesForEventFunc: func(ctx context.Context, eventID string) (map[string][]groups.MatchProfile, error) {
+			return map[string][]groups.MatchProfile{
+				// A member with nothing in common no longer vetoes the group, it just ranks lower
+				"weak":   {{UserID: "a", College: "IIT Bombay", Interests: []string{"Music"}}},
+				"strong": {{UserID: "b", College: "NIT Warangal", Interests: []string{"AI"}}},
+				"women":  {{UserID: "c", College: "NIT Warangal", Gender: "female", Interests: []string{"AI"}}},
+			}, nil
+		},
+	}
+
+	router := server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
+		&MockEventsRepository{}, mockGroupsRepo,
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
+	)
+
+	req, _ := http.NewRequest("GET", "/groups/suggested?event_id=evt-1&departure_date=2026-11-04", nil)
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /groups/suggested: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var result []groups.GroupWithDetails
+	json.NewDecoder(rr.Body).Decode(&result)
+	if len(result) != 2 || result[0].ID != "strong" || result[1].ID != "weak" {
+		t.Fatalf("suggested = %+v, want [strong weak]", result)
+	}
+	if len(result[0].MatchBreakdown) != 6 {
+		t.Errorf("breakdown has %d factors, want 6", len(result[0].MatchBreakdown))
+	}
+}
+
+// scorerFunc lets a test rank groups with a plain function.
+type scorerFunc func(user groups.MatchProfile, group groups.GroupWithDetails, members []groups.MatchProfile) groups.Match
+
+func (f scorerFunc) Score(user groups.MatchProfile, group groups.GroupWithDetails, members []groups.MatchProfile) groups.Match {
+	return f(user, group, members)
+}
+
+func TestSuggestedGroups_UsesInjectedScorer(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+
+	mockGroupsRepo := &MockGroupsRepositoryFull{
+		GetGroupsWithCountsForEventFunc: func(ctx context.Context, eventID string) ([]groups.GroupWithDetails, error) {
+			return []groups.GroupWithDetails{
+				{Group: groups.Group{ID: "first", MaxMembers: 4}, MemberCount: 1},
+				{Group: groups.Group{ID: "second", MaxMembers: 4}, MemberCount: 1},
+			}, nil
+		},
+	}
+	// Rank by ID, the reverse of what the repository returns
+	scorer := scorerFunc(func(user groups.MatchProfile, group groups.GroupWithDetails, members []groups.MatchProfile) groups.Match {
+		score := 0.2
+		if group.ID == "second" {
+			score = 0.9
+		}
+		return groups.Match{Score: score, Eligible: true}
+	})
+
+	router := server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
+		&MockEventsRepository{}, mockGroupsRepo,
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, scorer,
+	)
+
+	req, _ := http.NewRequest("GET", "/groups/suggested?event_id=evt-1", nil)
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /groups/suggested: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	var result []groups.GroupWithDetails
+	json.NewDecoder(rr.Body).Decode(&result)
+	if len(result) != 2 || result[0].ID != "second" {
+		t.Errorf("suggested = %+v, want second ranked first by the injected scorer", result)
+	}
+}
+
+func TestSuggestedGroups_InvalidDepartureDate(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+
+	router := server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
+		&MockEventsRepository{}, &MockGroupsRepositoryFull{},
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
+	)
+
+	req, _ := http.NewRequest("GET", "/groups/suggested?event_id=evt-1&departure_date=next-week", nil)
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid departure_date: got %d, want 400", rr.Code)
+	}
+}
+
+func TestJoinGroup_GenderPreference(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+
+	joined := false
+	mockGroupsRepo := &MockGroupsRepositoryFull{
+		GetGroupFunc: func(ctx context.Context, groupID string) (*groups.Group, error) {
+			return &groups.Group{ID: groupID, MaxMembers: 4, GenderPreference: groups.GenderWomenOnly}, nil
+		},
+		GetMatchProfileFunc: func(ctx context.Context, userID string) (groups.MatchProfile, error) {
+			return groups.MatchProfile{UserID: userID, Gender: "male"}, nil
+		},
+		JoinGroupCheckedFunc: func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
+			joined = true
+			return false, nil
+		},
+	}
+
+	router := server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
+		&MockEventsRepository{}, mockGroupsRepo,
+		nil, nil, &MockFileStorage{}, "./uploads", nil, nil, nil,
+	)
+
+	req, _ := http.NewRequest("POST", "/groups/g1/join", nil)
+	token, _ := auth.GenerateToken("test-user-id", "student@nitw.ac.in")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("joining a women_only group as a man: got %d, want 403", rr.Code)
+	}
+	if joined {
+		t.Error("user should not have been added to the group")
+	}
+}
+
+func TestWeightedScorer_TrustFactor(t *testing.T) {
+	user := groups.MatchProfile{UserID: "me"}
+	group := groups.GroupWithDetails{Group: groups.Group{MaxMembers: 4}, MemberCount: 2}
+	trusted := groups.NewTrustScore(groups.MinTrustRatings, 5, 4)
+	scorer := groups.NewWeightedScorer(groups.DefaultWeights)
+
+	m := scorer.Score(user, group, []groups.MatchProfile{{UserID: "a", Trust: trusted}, {UserID: "b"}})
+	// Only members with a trust score count: (4.5-1)/4
+	if fs := factorScore(m, groups.FactorTrust); !fs.Applied || math.Abs(fs.Score-0.875) > 1e-9 {
+		t.Errorf("trust = %+v, want applied with score 0.875", fs)
+	}
+
+	m = scorer.Score(user, group, []groups.MatchProfile{{UserID: "a"}, {UserID: "b"}})
+	if fs := factorScore(m, groups.FactorTrust); fs.Applied || fs.Contribution != 0 {
+		t.Errorf("trust should be skipped without trust scores, got %+v", fs)
+	}
+}
@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+)
+
+const (
+	groupA = "00000000-0000-0000-0000-00000000000a"
+	groupB = "00000000-0000-0000-0000-00000000000b"
+	groupC = "00000000-0000-0000-0000-00000000000c"
+	event1 = "00000000-0000-0000-0000-0000000000e1"
+)
+
+func TestParseGroupSearch(t *testing.T) {
+	q, _ := url.ParseQuery("event_id=" + event1 + "&departure_from=2026-11-01&departure_to=2026-11-03&open_seats=2" +
+		"&requires_approval=false&college=NIT+Warangal&origin=Hyderabad&q=+night+train+&sort=departure&limit=10")
+	s, err := groups.ParseGroupSearch(q)
+	if err != nil {
+		t.Fatalf("ParseGroupSearch: %v", err)
+	}
+	if s.EventID != event1 || s.MinOpenSeats != 2 || s.RequiresApproval == nil || *s.RequiresApproval ||
+		s.College != "NIT Warangal" || s.Query != "night train" || s.Sort != groups.SortDeparture || s.Limit != 10 {
+		t.Errorf("search = %+v", s)
+	}
+	if s.DepartureFrom == nil || s.DepartureTo == nil || s.DepartureTo.Sub(*s.DepartureFrom) != 48*time.Hour {
+		t.Errorf("departure range = %v..%v", s.DepartureFrom, s.DepartureTo)
+	}
+	if s.Origin == nil || s.Origin.Place.City != "Hyderabad" || !s.InDatabase() {
+		t.Errorf("origin = %+v, in database = %v; want a city filter applied in the database", s.Origin, s.InDatabase())
+	}
+
+	s, _ = groups.ParseGroupSearch(url.Values{})
+	if s.Sort != groups.SortNewest || s.Limit != groups.DefaultGroupPageSize || !s.InDatabase() {
+		t.Errorf("defaults = %+v", s)
+	}
+	q, _ = url.ParseQuery("origin_lat=17.385&origin_lng=78.4867")
+	if s, _ = groups.ParseGroupSearch(q); s.Sort != groups.SortDistance || s.InDatabase() {
+		t.Errorf("with coordinates: sort = %q, in database = %v; want distance in Go", s.Sort, s.InDatabase())
+	}
+
+	departureCursor := groups.GroupCursor{Sort: groups.SortDeparture, ID: groupA}.Encode()
+	for _, bad := range []string{
+		"event_id=evt-1",
+		"departure_from=01-11-2026",
+		"departure_from=2026-11-03&departure_to=2026-11-01",
+		"open_seats=0",
+		"requires_approval=maybe",
+		"limit=0",
+		"limit=51",
+		"sort=match",
+		"sort=distance&origin=Pune",
+		"sort=popular",
+		"cursor=not-a-cursor",
+		"sort=newest&cursor=" + departureCursor,
+	} {
+		q, _ := url.ParseQuery(bad)
+		if _, err := groups.ParseGroupSearch(q); err == nil {
+			t.Errorf("ParseGroupSearch(%q): want error", bad)
+		}
+	}
+}
+
+func decodeGroupPage(t *testing.T, router http.Handler, path string) groups.GroupPage {
+	t.Helper()
+	rr := doItinerary(t, router, "test-user-id", "GET", path, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET %s: got %d, want 200. Body: %s", path, rr.Code, rr.Body.String())
+	}
+	var page groups.GroupPage
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	return page
+}
+
+func pageIDs(page groups.GroupPage) []string {
+	ids := []string{}
+	for _, g := range page.Groups {
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
+
+func TestListAllGroups_PaginatesInDatabase(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	all := []groups.GroupWithDetails{
+		{Group: groups.Group{ID: groupC, CreatedAt: created}},
+		{Group: groups.Group{ID: groupB, CreatedAt: created.Add(-time.Hour)}},
+		{Group: groups.Group{ID: groupA, CreatedAt: created.Add(-2 * time.Hour)}},
+	}
+	var searches []groups.GroupSearch
+	repo := &MockGroupsRepositoryFull{
+		SearchGroupsFunc: func(ctx context.Context, userID string, search groups.GroupSearch) ([]groups.GroupWithDetails, error) {
+			searches = append(searches, search)
+			rest := all
+			if search.After != nil {
+				for i, g := range all {
+					if g.ID == search.After.ID {
+						rest = all[i+1:]
+					}
+				}
+			}
+			if len(rest) > search.Limit+1 {
+				rest = rest[:search.Limit+1]
+			}
+			return rest, nil
+		},
+	}
+	router := newRouteRouter(t, repo)
+
+	page := decodeGroupPage(t, router, "/groups?limit=2&open_seats=1")
+	if ids := pageIDs(page); len(ids) != 2 || ids[0] != groupC || ids[1] != groupB || !page.HasMore || page.NextCursor == "" {
+		t.Fatalf("first page = %v (has_more=%v)", ids, page.HasMore)
+	}
+	if s := searches[0]; s.Limit != 2 || s.MinOpenSeats != 1 || s.After != nil {
+		t.Errorf("first search = %+v", s)
+	}
+
+	page = decodeGroupPage(t, router, "/groups?limit=2&open_seats=1&cursor="+page.NextCursor)
+	if ids := pageIDs(page); len(ids) != 1 || ids[0] != groupA || page.HasMore || page.NextCursor != "" {
+		t.Errorf("second page = %v (has_more=%v)", ids, page.HasMore)
+	}
+	if c := searches[1].After; c == nil || c.ID != groupB || c.Sort != groups.SortNewest {
+		t.Errorf("second search cursor = %+v, want one pointing at %s", c, groupB)
+	}
+}
+
+func TestListAllGroups_SortByMatch(t *testing.T) {
+	repo := &MockGroupsRepositoryFull{
+		SearchGroupsFunc: func(ctx context.Context, userID string, search groups.GroupSearch) ([]groups.GroupWithDetails, error) {
+			if search.EventID != event1 {
+				t.Errorf("event_id = %q", search.EventID)
+			}
+			return []groups.GroupWithDetails{
+				{Group: groups.Group{ID: groupA, MaxMembers: 4}, MemberCount: 1},
+				{Group: groups.Group{ID: groupB, MaxMembers: 4}, MemberCount: 1},
+				{Group: groups.Group{ID: groupC, MaxMembers: 4}, MemberCount: 1},
+			}, nil
+		},
+		GetMatchProfileFunc: func(ctx context.Context, userID string) (groups.MatchProfile, error) {
+			return groups.MatchProfile{UserID: userID, Interests: []string{"AI", "ML"}}, nil
+		},
+		GetGroupMemberProfilesForEventFunc: func(ctx context.Context, eventID string) (map[string][]groups.MatchProfile, error) {
+			return map[string][]groups.MatchProfile{
+				groupA: {{UserID: "a", Interests: []string{"Chess"}}},
+				groupB: {{UserID: "b", Interests: []string{"AI", "ML"}}},
+				groupC: {{UserID: "c", Interests: []string{"AI", "Music"}}},
+			}, nil
+		},
+	}
+	router := newRouteRouter(t, repo)
+
+	var ids []string
+	path := "/groups?event_id=" + event1 + "&sort=match&limit=2"
+	page := decodeGroupPage(t, router, path)
+	ids = append(ids, pageIDs(page)...)
+	if !page.HasMore || len(page.Groups) != 2 || len(page.Groups[0].Interests) != 2 {
+		t.Fatalf("first page = %+v", page)
+	}
+	page = decodeGroupPage(t, router, path+"&cursor="+page.NextCursor)
+	ids = append(ids, pageIDs(page)...)
+	if page.HasMore {
+		t.Errorf("second page has_more = true")
+	}
+
+	want := []string{groupB, groupC, groupA}
+	if len(ids) != len(want) || ids[0] != want[0] || ids[1] != want[1] || ids[2] != want[2] {
+		t.Errorf("groups by match = %v, want %v", ids, want)
+	}
+}
+
+func TestListAllGroups_DepartureWithOrigin(t *testing.T) {
+	early := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+	late := early.Add(48 * time.Hour)
+	repo := &MockGroupsRepositoryFull{
+		SearchGroupsFunc: func(ctx context.Context, userID string, search groups.GroupSearch) ([]groups.GroupWithDetails, error) {
+			return []groups.GroupWithDetails{
+				{Group: groups.Group{ID: groupA, Origin: place("Hyderabad", 17.385, 78.4867)}},
+				{Group: groups.Group{ID: groupB, Origin: place("Secunderabad", 17.4399, 78.4983), DepartureDate: &late}},
+				{Group: groups.Group{ID: groupC, Origin: place("Hyderabad", 17.385, 78.4867), DepartureDate: &early}},
+				{Group: groups.Group{ID: "pune", Origin: place("Pune", 18.5204, 73.8567), DepartureDate: &early}},
+			}, nil
+		},
+	}
+	router := newRouteRouter(t, repo)
+
+	var ids []string
+	path := "/groups?origin_lat=17.385&origin_lng=78.4867&sort=departure&limit=1"
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		page := decodeGroupPage(t, router, path+cursor)
+		ids = append(ids, pageIDs(page)...)
+		if !page.HasMore {
+			break
+		}
+		cursor = "&cursor=" + page.NextCursor
+	}
+
+	want := []string{groupC, groupB, groupA}
+	if len(ids) != len(want) || ids[0] != want[0] || ids[1] != want[1] || ids[2] != want[2] {
+		t.Errorf("groups by departure = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/muskan953/college-Hop/internal/auth"
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/messages"
+	"github.com/muskan953/college-Hop/internal/server"
+)
+
+type systemMessage struct {
+	threadID, actorID, kind string
+	metadata                map[string]string
+}
+
+// newSystemMessageRouter wires a groups repo with a real hub that records system messages.
+func newSystemMessageRouter(t *testing.T, groupsRepo groups.Repository) (http.Handler, *[]systemMessage) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "testsecret")
+	var posted []systemMessage
+	msgRepo := &MockMessagesRepository{
+		CreateSystemMessageFunc: func(ctx context.Context, threadID, actorID, kind string, metadata map[string]string) (messages.Message, error) {
+			posted = append(posted, systemMessage{threadID, actorID, kind, metadata})
+			return messages.Message{ID: "sys-1", ThreadID: threadID, SenderID: actorID, Kind: kind, Metadata: metadata}, nil
+		},
+	}
+	router := server.NewRouter(
+		&MockAuthRepository{}, nil, &MockProfileRepository{}, &MockAdminRepository{},
+		&MockEventsRepository{}, groupsRepo,
+		msgRepo, messages.NewHub(msgRepo, nil, nil), &MockFileStorage{}, "./uploads", nil, nil, nil,
+	)
+	return router, &posted
+}
+
+func TestKickMember_PostsSystemMessage(t *testing.T) {
+	groupsRepo := &MockGroupsRepositoryFull{
+		GetGroupFunc: func(ctx context.Context, groupID string) (*groups.Group, error) {
+			return &groups.Group{ID: groupID, CreatedBy: "creator"}, nil
+		},
+		IsGroupMemberFunc: func(ctx context.Context, groupID, userID string) (bool, error) {
+			return true, nil
+		},
+		GetGroupThreadIDFunc: func(ctx context.Context, groupID string) (string, error) {
+			return "thread-g1", nil
+		},
+	}
+	router, posted := newSystemMessageRouter(t, groupsRepo)
+
+	token, _ := auth.GenerateToken("creator", "student@nitw.ac.in")
+	body, _ := json.Marshal(map[string]string{"user_id": "member-2"})
+	req, _ := http.NewRequest("POST", "/groups/g1/kick", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /groups/{id}/kick: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if len(*posted) != 1 {
+		t.Fatalf("posted %d system messages, want 1", len(*posted))
+	}
+	got := (*posted)[0]
+	if got.threadID != "thread-g1" || got.actorID != "creator" || got.kind != messages.KindMemberKicked || got.metadata["user_id"] != "member-2" {
+		t.Errorf("system message = %+v, want member_kicked of member-2 by creator in thread-g1", got)
+	}
+}
+
+func TestUpdateGroup_PostsRenameAndMeetingPoint(t *testing.T) {
+	groupsRepo := &MockGroupsRepositoryFull{
+		GetGroupFunc: func(ctx context.Context, groupID string) (*groups.Group, error) {
+			return &groups.Group{ID: groupID, CreatedBy: "creator", Name: "Old Name", MeetingPoint: "Gate 1"}, nil
+		},
+	}
+	router, posted := newSystemMessageRouter(t, groupsRepo)
+
+	token, _ := auth.GenerateToken("creator", "student@nitw.ac.in")
+	body, _ := json.Marshal(map[string]string{"name": "New Name", "meeting_point": "Gate 3"})
+	req, _ := http.NewRequest("PUT", "/groups/g1", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT /groups/{id}: got %d, want 200. Body: %s", rr.Code, rr.Body.String())
+	}
+	if len(*posted) != 2 {
+		t.Fatalf("posted %d system messages, want 2", len(*posted))
+	}
+	if (*posted)[0].kind != messages.KindGroupRenamed || (*posted)[0].metadata["name"] != "New Name" {
+		t.Errorf("first system message = %+v, want group_renamed to New Name", (*posted)[0])
+	}
+	if (*posted)[1].kind != messages.KindMeetingPointChanged || (*posted)[1].metadata["old_meeting_point"] != "Gate 1" {
+		t.Errorf("second system message = %+v, want meeting_point_changed from Gate 1", (*posted)[1])
+	}
+}
+
+func TestUpdateGroup_NoChangeNoSystemMessage(t *testing.T) {
+	groupsRepo := &MockGroupsRepositoryFull{
+		GetGroupFunc: func(ctx context.Context, groupID string) (*groups.Group, error) {
+			return &groups.Group{ID: groupID, CreatedBy: "creator", Name: "Same", MeetingPoint: "Gate 1"}, nil
+		},
+	}
+	router, posted := newSystemMessageRouter(t, groupsRepo)
+
+	token, _ := auth.GenerateToken("creator", "student@nitw.ac.in")
+	body, _ := json.Marshal(map[string]string{"name": "Same", "meeting_point": "Gate 1", "description": "updated"})
+	req, _ := http.NewRequest("PUT", "/groups/g1", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT /groups/{id}: got %d, want 200", rr.Code)
+	}
+	if len(*posted) != 0 {
+		t.Errorf("posted %d system messages, want 0", len(*posted))
+	}
+}
+
+func TestForwardMessage_SystemMessage(t *testing.T) {
+	mockRepo := &MockMessagesRepository{
+		GetMessageFunc: func(ctx context.Context, messageID, userID string) (messages.Message, error) {
+			return messages.Message{ID: messageID, ThreadID: "thread-1", Kind: messages.KindMemberJoined}, nil
+		},
+	}
+	rr := postForward(t, mockRepo, map[string]interface{}{"message_id": "msg-1", "thread_ids": []string{"thread-2"}})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("forwarding a system message: got %d, want 400", rr.Code)
+	}
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/muskan953/college-Hop/internal/groups"
+	"github.com/muskan953/college-Hop/internal/messages"
+)
+
+// waitlistRepo is a groups repo whose waitlist holds the given users, in order.
+func waitlistRepo(waiting []string, roles map[string]string) *MockGroupsRepositoryFull {
+	repo := rolesRepo(roles)
+	repo.GetWaitlistFunc = func(ctx context.Context, groupID string) ([]groups.WaitlistEntry, error) {
+		var entries []groups.WaitlistEntry
+		for i, u := range waiting {
+			entries = append(entries, groups.WaitlistEntry{GroupID: groupID, UserID: u, Position: i + 1, Status: groups.WaitlistWaiting})
+		}
+		return entries, nil
+	}
+	return repo
+}
+
+func TestJoinGroup_FullMemberNotWaitlisted(t *testing.T) {
+	queued := false
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner})
+	repo.JoinGroupCheckedFunc = func(ctx context.Context, groupID, userID string, skipApproval bool, message string) (bool, error) {
+		return false, groups.ErrGroupFull
+	}
+	repo.JoinWaitlistFunc = func(ctx context.Context, groupID, userID string) (int, error) {
+		queued = true
+		return 1, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/join", nil); rr.Code != http.StatusConflict || queued {
+		t.Errorf("member joining a full group: got %d (queued=%v), want 409", rr.Code, queued)
+	}
+}
+
+func TestLeaveGroup_PromotesFromWaitlist(t *testing.T) {
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember})
+	repo.PromoteFromWaitlistFunc = func(ctx context.Context, groupID string) ([]groups.Promotion, error) {
+		return []groups.Promotion{{UserID: "next", Joined: true}}, nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "member", "POST", "/groups/g1/leave", nil); rr.Code != http.StatusOK {
+		t.Fatalf("leave: got %d, want 200", rr.Code)
+	}
+	if len(*posted) != 2 || (*posted)[1].kind != messages.KindMemberJoined || (*posted)[1].actorID != "next" || (*posted)[1].metadata["from_waitlist"] != "true" {
+		t.Errorf("system messages = %+v, want member_left then member_joined from the waitlist", *posted)
+	}
+}
+
+func TestKickMember_OffersSpotInApprovalGroup(t *testing.T) {
+	promoted := false
+	repo := rolesRepo(map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember})
+	repo.PromoteFromWaitlistFunc = func(ctx context.Context, groupID string) ([]groups.Promotion, error) {
+		promoted = true
+		return []groups.Promotion{{UserID: "next", OfferExpiresAt: time.Now().Add(groups.WaitlistOfferTTL)}}, nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "owner", "POST", "/groups/g1/kick", map[string]string{"user_id": "member"}); rr.Code != http.StatusOK {
+		t.Fatalf("kick: got %d, want 200", rr.Code)
+	}
+	// An offered spot is not a join yet
+	if !promoted || len(*posted) != 1 || (*posted)[0].kind != messages.KindMemberKicked {
+		t.Errorf("promoted = %v, system messages = %+v; want only member_kicked", promoted, *posted)
+	}
+}
+
+func TestGetWaitlist_Visibility(t *testing.T) {
+	repo := waitlistRepo([]string{"w1", "w2", "w3"}, map[string]string{"owner": groups.RoleOwner, "member": groups.RoleMember})
+	router, _ := newSystemMessageRouter(t, repo)
+
+	tests := []struct {
+		user    string
+		entries int
+	}{
+		{"owner", 3},
+		{"member", 0},
+		{"w2", 1},
+	}
+	for _, tt := range tests {
+		rr := doItinerary(t, router, tt.user, "GET", "/groups/g1/waitlist", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s GET waitlist: got %d, want 200", tt.user, rr.Code)
+		}
+		var wl groups.Waitlist
+		json.NewDecoder(rr.Body).Decode(&wl)
+		if wl.Count != 3 || len(wl.Entries) != tt.entries {
+			t.Errorf("%s sees count %d with %d entries, want 3 with %d", tt.user, wl.Count, len(wl.Entries), tt.entries)
+		}
+		if tt.user == "w2" && wl.Entries[0].Position != 2 {
+			t.Errorf("w2 position = %d, want 2", wl.Entries[0].Position)
+		}
+	}
+}
+
+func TestLeaveWaitlist_FreesHeldSpot(t *testing.T) {
+	promoted := false
+	repo := rolesRepo(nil)
+	repo.PromoteFromWaitlistFunc = func(ctx context.Context, groupID string) ([]groups.Promotion, error) {
+		promoted = true
+		return nil, nil
+	}
+	router, _ := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "w1", "DELETE", "/groups/g1/waitlist", nil); rr.Code != http.StatusOK || !promoted {
+		t.Errorf("leaving waitlist: got %d (promoted=%v), want 200 and the next user promoted", rr.Code, promoted)
+	}
+}
+
+func TestClaimWaitlistSpot(t *testing.T) {
+	repo := rolesRepo(nil)
+	repo.ClaimWaitlistOfferFunc = func(ctx context.Context, groupID, userID string) error {
+		if userID != "offered" {
+			return groups.ErrNoWaitlistOffer
+		}
+		return nil
+	}
+	router, posted := newSystemMessageRouter(t, repo)
+
+	if rr := doItinerary(t, router, "waiting", "POST", "/groups/g1/waitlist/claim", nil); rr.Code != http.StatusGone {
+		t.Errorf("claiming without an offer: got %d, want 410", rr.Code)
+	}
+	if rr := doItinerary(t, router, "offered", "POST", "/groups/g1/waitlist/claim", nil); rr.Code != http.StatusOK {
+		t.Fatalf("claiming an offer: got %d, want 200", rr.Code)
+	}
+	if len(*posted) != 1 || (*posted)[0].metadata["user_id"] != "offered" {
+		t.Errorf("system messages = %+v, want member_joined for offered", *posted)
+	}
+}
+
+func TestWaitlistSweeper_PromotesOncePerGroup(t *testing.T) {
+	promoted := map[string]int{}
+	repo := rolesRepo(nil)
+	repo.ExpireWaitlistOffersFunc = func(ctx context.Context, now time.Time, limit int) ([]groups.WaitlistEntry, error) {
+		return []groups.WaitlistEntry{{GroupID: "g1", UserID: "a"}, {GroupID: "g1", UserID: "b"}, {GroupID: "g2", UserID: "c"}}, nil
+	}
+	repo.PromoteFromWaitlistFunc = func(ctx context.Context, groupID string) ([]groups.Promotion, error) {
+		promoted[groupID]++
+		return nil, nil
+	}
+
+	if n := groups.NewWaitlistSweeper(repo, nil).SweepExpiredOffers(context.Background()); n != 3 {
+		t.Errorf("expired offers = %d, want 3", n)
+	}
+	if promoted["g1"] != 1 || promoted["g2"] != 1 {
+		t.Errorf("promotions per group = %v, want one each", promoted)
+	}
+}